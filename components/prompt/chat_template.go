@@ -19,6 +19,9 @@ package prompt
 import (
 	"context"
 
+	"github.com/eino-contrib/jsonschema"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
 	"github.com/mrh997/eino/callbacks"
 	"github.com/mrh997/eino/components"
 	"github.com/mrh997/eino/schema"
@@ -78,6 +81,42 @@ func (t *DefaultChatTemplate) Format(ctx context.Context,
 	return result, nil
 }
 
+// InputVariables returns the variables referenced by the templates, see schema.ExtractVariables.
+func (t *DefaultChatTemplate) InputVariables() ([]schema.TemplateVariable, error) {
+	return schema.ExtractVariables(t.formatType, t.templates...)
+}
+
+// InputSchema returns the declared input schema of the chat template, an object of the variables,
+// where the variables of MessagesPlaceholder are arrays of messages, and the non-optional variables are required.
+func (t *DefaultChatTemplate) InputSchema() (*jsonschema.Schema, error) {
+	vars, err := t.InputVariables()
+	if err != nil {
+		return nil, err
+	}
+	return variablesSchema(vars), nil
+}
+
+// variablesSchema returns the object schema of the variables.
+func variablesSchema(vars []schema.TemplateVariable) *jsonschema.Schema {
+	sc := &jsonschema.Schema{
+		Type:       string(schema.Object),
+		Properties: orderedmap.New[string, *jsonschema.Schema](),
+		Required:   make([]string, 0, len(vars)),
+	}
+	for _, v := range vars {
+		prop := &jsonschema.Schema{}
+		if v.Messages {
+			prop.Type = string(schema.Array)
+			prop.Items = &jsonschema.Schema{Type: string(schema.Object)}
+		}
+		sc.Properties.Set(v.Name, prop)
+		if !v.Optional {
+			sc.Required = append(sc.Required, v.Name)
+		}
+	}
+	return sc
+}
+
 // GetType returns the type of the chat template (Default).
 func (t *DefaultChatTemplate) GetType() string {
 	return "Default"
@@ -185,3 +185,36 @@ func TestMultiContentFormat(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, expected, result)
 }
+
+func TestInputSchema(t *testing.T) {
+	for _, tpl := range []*DefaultChatTemplate{
+		FromMessages(schema.FString,
+			schema.SystemMessage("here is the context: {context}"),
+			schema.MessagesPlaceholder("chat_history", true),
+			schema.UserMessage("question: {question}")),
+		FromMessages(schema.GoTemplate,
+			schema.SystemMessage("here is the context: {{.context}}"),
+			schema.MessagesPlaceholder("chat_history", true),
+			schema.UserMessage("question: {{.question}}")),
+		FromMessages(schema.Jinja2,
+			schema.SystemMessage("here is the context: {{context}}"),
+			schema.MessagesPlaceholder("chat_history", true),
+			schema.UserMessage("question: {{question}}")),
+	} {
+		sc, err := tpl.InputSchema()
+		assert.NoError(t, err)
+		assert.Equal(t, "object", sc.Type)
+		assert.Equal(t, []string{"context", "question"}, sc.Required)
+
+		var keys []string
+		for pair := sc.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			keys = append(keys, pair.Key)
+		}
+		assert.Equal(t, []string{"context", "chat_history", "question"}, keys)
+		history, _ := sc.Properties.Get("chat_history")
+		assert.Equal(t, "array", history.Type)
+	}
+
+	_, err := FromMessages(schema.FString, schema.UserMessage("{question")).InputSchema()
+	assert.Error(t, err)
+}
@@ -0,0 +1,345 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mrh997/eino/components/embedding"
+	"github.com/mrh997/eino/internal/vector"
+	"github.com/mrh997/eino/schema"
+)
+
+// FewShotConfig is the config of NewFewShotTemplate.
+type FewShotConfig struct {
+	// Examples is the pool of examples, each is the variables formatted by ExampleTemplates.
+	Examples []map[string]any
+	// ExampleTemplates format each example into messages, with the format type of the chat template,
+	// e.g. a user message of "{question}" and an assistant message of "{answer}".
+	ExampleTemplates []schema.MessagesTemplate
+	// Selector selects the examples rendered when formatting.
+	// Optional. By default, all the examples are rendered.
+	Selector ExampleSelector
+}
+
+// Example is an example of FewShotConfig.Examples passed to ExampleSelector.
+type Example struct {
+	// Variables is the example in FewShotConfig.Examples.
+	Variables map[string]any
+	// Messages are formatted from Variables by FewShotConfig.ExampleTemplates.
+	Messages []*schema.Message
+}
+
+// ExampleSelector selects the examples rendered by the few-shot template, for the variables of the chat template being formatted.
+// a selector reading the variables can implement schema.VariablesDeclarer to declare them, as the variables of the few-shot template.
+type ExampleSelector interface {
+	Select(ctx context.Context, vs map[string]any, examples []*Example) ([]*Example, error)
+}
+
+type fewShotTemplate struct {
+	examples  []map[string]any
+	templates []schema.MessagesTemplate
+	selector  ExampleSelector
+}
+
+// NewFewShotTemplate creates a MessagesTemplate rendering the few-shot examples chosen by the selector when formatting,
+// which can be used in FromMessages with any FormatType.
+// e.g.
+//
+//	fewShot, err := prompt.NewFewShotTemplate(&prompt.FewShotConfig{
+//		Examples: []map[string]any{
+//			{"question": "1+1", "answer": "2"},
+//			{"question": "2*3", "answer": "6"},
+//		},
+//		ExampleTemplates: []schema.MessagesTemplate{schema.UserMessage("{question}"), schema.AssistantMessage("{answer}", nil)},
+//		Selector:         prompt.NewRandomExampleSelector(1),
+//	})
+//	template := prompt.FromMessages(schema.FString, schema.SystemMessage("you are a calculator."), fewShot, schema.UserMessage("{question}"))
+func NewFewShotTemplate(conf *FewShotConfig) (schema.MessagesTemplate, error) {
+	if conf == nil || len(conf.ExampleTemplates) == 0 {
+		return nil, errors.New("example templates are required")
+	}
+	return &fewShotTemplate{
+		examples:  conf.Examples,
+		templates: conf.ExampleTemplates,
+		selector:  conf.Selector,
+	}, nil
+}
+
+// Format formats all the examples by the example templates, and returns the messages of the selected ones.
+func (f *fewShotTemplate) Format(ctx context.Context, vs map[string]any, formatType schema.FormatType) ([]*schema.Message, error) {
+	examples := make([]*Example, len(f.examples))
+	for i, variables := range f.examples {
+		examples[i] = &Example{Variables: variables}
+		for _, t := range f.templates {
+			msgs, err := t.Format(ctx, variables, formatType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to format example[%d]: %w", i, err)
+			}
+			examples[i].Messages = append(examples[i].Messages, msgs...)
+		}
+	}
+
+	if f.selector != nil {
+		var err error
+		examples, err = f.selector.Select(ctx, vs, examples)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select examples: %w", err)
+		}
+	}
+
+	var result []*schema.Message
+	for _, e := range examples {
+		result = append(result, e.Messages...)
+	}
+	return result, nil
+}
+
+// Variables returns the variables declared by the selector, the variables of the examples are not from the chat template.
+func (f *fewShotTemplate) Variables(formatType schema.FormatType) ([]schema.TemplateVariable, error) {
+	if d, ok := f.selector.(schema.VariablesDeclarer); ok {
+		return d.Variables(formatType)
+	}
+	return nil, nil
+}
+
+// NewFixedExampleSelector creates an ExampleSelector selecting the first n examples, or all the examples if n is not positive.
+func NewFixedExampleSelector(n int) ExampleSelector {
+	return &fixedExampleSelector{n: n}
+}
+
+type fixedExampleSelector struct {
+	n int
+}
+
+func (s *fixedExampleSelector) Select(_ context.Context, _ map[string]any, examples []*Example) ([]*Example, error) {
+	if s.n <= 0 || s.n >= len(examples) {
+		return examples, nil
+	}
+	return examples[:s.n], nil
+}
+
+// NewRandomExampleSelector creates an ExampleSelector selecting n random examples on each format, in the order of the pool,
+// or all the examples if n is not positive.
+func NewRandomExampleSelector(n int) ExampleSelector {
+	return &randomExampleSelector{n: n}
+}
+
+type randomExampleSelector struct {
+	n int
+}
+
+func (s *randomExampleSelector) Select(_ context.Context, _ map[string]any, examples []*Example) ([]*Example, error) {
+	if s.n <= 0 || s.n >= len(examples) {
+		return examples, nil
+	}
+
+	indexes := rand.Perm(len(examples))[:s.n]
+	sort.Ints(indexes)
+	selected := make([]*Example, len(indexes))
+	for i, idx := range indexes {
+		selected[i] = examples[idx]
+	}
+	return selected, nil
+}
+
+// LengthExampleSelectorConfig is the config of NewLengthExampleSelector.
+type LengthExampleSelectorConfig struct {
+	// MaxTokens is the budget of the tokens of the messages of the selected examples.
+	MaxTokens int
+	// Tokenizer counts the tokens of the messages of the examples, see schema.CountMessageTokens.
+	// Optional. Default schema.NewHeuristicTokenizer().
+	Tokenizer schema.Tokenizer
+}
+
+// NewLengthExampleSelector creates an ExampleSelector selecting the examples in the order of the pool,
+// until the next one exceeds the budget of tokens.
+func NewLengthExampleSelector(conf *LengthExampleSelectorConfig) (ExampleSelector, error) {
+	if conf == nil || conf.MaxTokens <= 0 {
+		return nil, errors.New("max tokens must be positive")
+	}
+	tokenizer := conf.Tokenizer
+	if tokenizer == nil {
+		tokenizer = schema.NewHeuristicTokenizer()
+	}
+	return &lengthExampleSelector{maxTokens: conf.MaxTokens, tokenizer: tokenizer}, nil
+}
+
+type lengthExampleSelector struct {
+	maxTokens int
+	tokenizer schema.Tokenizer
+}
+
+func (s *lengthExampleSelector) Select(ctx context.Context, _ map[string]any, examples []*Example) ([]*Example, error) {
+	total := 0
+	for i, e := range examples {
+		tokens, err := schema.CountMessageTokens(ctx, s.tokenizer, e.Messages...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count tokens of example[%d]: %w", i, err)
+		}
+		if total+tokens > s.maxTokens {
+			return examples[:i], nil
+		}
+		total += tokens
+	}
+	return examples, nil
+}
+
+const defaultSemanticExamples = 4
+
+// SemanticExampleSelectorConfig is the config of NewSemanticExampleSelector.
+type SemanticExampleSelectorConfig struct {
+	// Embedder embeds the examples and the variables being formatted.
+	Embedder embedding.Embedder
+	// InputKeys are the keys of the examples and the variables compared by similarity,
+	// the values of the keys are joined by lines into the texts to embed, e.g. ["question"].
+	InputKeys []string
+	// K is the max number of examples selected.
+	// Optional. Default 4.
+	K int
+}
+
+// NewSemanticExampleSelector creates an ExampleSelector selecting the k examples most similar to the variables being formatted,
+// from the most similar one, by the cosine similarity of the embeddings.
+// the embeddings of the examples are cached, so the pool is embedded once.
+func NewSemanticExampleSelector(conf *SemanticExampleSelectorConfig) (ExampleSelector, error) {
+	if conf == nil || conf.Embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+	if len(conf.InputKeys) == 0 {
+		return nil, errors.New("input keys are required")
+	}
+	k := conf.K
+	if k <= 0 {
+		k = defaultSemanticExamples
+	}
+	return &semanticExampleSelector{
+		embedder:  conf.Embedder,
+		inputKeys: conf.InputKeys,
+		k:         k,
+		vectors:   make(map[string][]float64),
+	}, nil
+}
+
+type semanticExampleSelector struct {
+	embedder  embedding.Embedder
+	inputKeys []string
+	k         int
+
+	mu      sync.Mutex
+	vectors map[string][]float64
+}
+
+func (s *semanticExampleSelector) Select(ctx context.Context, vs map[string]any, examples []*Example) ([]*Example, error) {
+	if len(examples) == 0 {
+		return nil, nil
+	}
+
+	texts := make([]string, len(examples))
+	for i, e := range examples {
+		texts[i] = s.textOf(e.Variables)
+	}
+	vectors, err := s.embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	queryVectors, err := s.embedder.EmbedStrings(ctx, []string{s.textOf(vs)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(queryVectors) != 1 {
+		return nil, fmt.Errorf("embedder returns %d vectors for 1 query", len(queryVectors))
+	}
+
+	scores := make([]float64, len(examples))
+	indexes := make([]int, len(examples))
+	for i := range examples {
+		scores[i] = vector.CosineSimilarity(queryVectors[0], vectors[i])
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return scores[indexes[i]] > scores[indexes[j]]
+	})
+	if len(indexes) > s.k {
+		indexes = indexes[:s.k]
+	}
+
+	selected := make([]*Example, len(indexes))
+	for i, idx := range indexes {
+		selected[i] = examples[idx]
+	}
+	return selected, nil
+}
+
+// Variables declares InputKeys as the required variables.
+func (s *semanticExampleSelector) Variables(_ schema.FormatType) ([]schema.TemplateVariable, error) {
+	vars := make([]schema.TemplateVariable, len(s.inputKeys))
+	for i, key := range s.inputKeys {
+		vars[i] = schema.TemplateVariable{Name: key}
+	}
+	return vars, nil
+}
+
+func (s *semanticExampleSelector) textOf(vs map[string]any) string {
+	values := make([]string, 0, len(s.inputKeys))
+	for _, key := range s.inputKeys {
+		if v, ok := vs[key]; ok {
+			values = append(values, fmt.Sprint(v))
+		}
+	}
+	return strings.Join(values, "\n")
+}
+
+// embed returns the vectors of the texts, embedding the texts not cached.
+func (s *semanticExampleSelector) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var missing []string
+	pending := make(map[string]bool)
+	for _, text := range texts {
+		if _, ok := s.vectors[text]; !ok && !pending[text] {
+			pending[text] = true
+			missing = append(missing, text)
+		}
+	}
+	if len(missing) > 0 {
+		vectors, err := s.embedder.EmbedStrings(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed examples: %w", err)
+		}
+		if len(vectors) != len(missing) {
+			return nil, fmt.Errorf("embedder returns %d vectors for %d examples", len(vectors), len(missing))
+		}
+		for i, text := range missing {
+			s.vectors[text] = vectors[i]
+		}
+	}
+
+	ret := make([][]float64, len(texts))
+	for i, text := range texts {
+		ret[i] = s.vectors[text]
+	}
+	return ret, nil
+}
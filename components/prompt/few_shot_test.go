@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/embedding"
+	"github.com/mrh997/eino/schema"
+)
+
+// keywordEmbedder embeds a text by whether it contains each keyword.
+type keywordEmbedder struct {
+	keywords []string
+	calls    [][]string
+}
+
+func (k *keywordEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	k.calls = append(k.calls, texts)
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float64, len(k.keywords))
+		for j, kw := range k.keywords {
+			if strings.Contains(text, kw) {
+				vectors[i][j] = 1
+			}
+		}
+	}
+	return vectors, nil
+}
+
+func TestFewShotTemplate(t *testing.T) {
+	ctx := context.Background()
+	examples := []map[string]any{
+		{"question": "apple color", "answer": "red"},
+		{"question": "sky color", "answer": "blue"},
+		{"question": "banana taste", "answer": "sweet"},
+	}
+	contents := func(msgs []*schema.Message) []string {
+		ret := make([]string, len(msgs))
+		for i, m := range msgs {
+			ret[i] = m.Content
+		}
+		return ret
+	}
+
+	t.Run("format types", func(t *testing.T) {
+		for formatType, tpls := range map[schema.FormatType][]schema.MessagesTemplate{
+			schema.FString:    {schema.UserMessage("Q: {question}"), schema.AssistantMessage("A: {answer}", nil)},
+			schema.GoTemplate: {schema.UserMessage("Q: {{.question}}"), schema.AssistantMessage("A: {{.answer}}", nil)},
+			schema.Jinja2:     {schema.UserMessage("Q: {{question}}"), schema.AssistantMessage("A: {{answer}}", nil)},
+		} {
+			fewShot, err := NewFewShotTemplate(&FewShotConfig{Examples: examples[:2], ExampleTemplates: tpls})
+			assert.NoError(t, err)
+			msgs, err := FromMessages(formatType, schema.SystemMessage("answer briefly"), fewShot, tpls[0]).
+				Format(ctx, map[string]any{"question": "grass color"})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"answer briefly", "Q: apple color", "A: red", "Q: sky color", "A: blue", "Q: grass color"}, contents(msgs))
+			assert.Equal(t, schema.Assistant, msgs[2].Role)
+		}
+
+		_, err := NewFewShotTemplate(&FewShotConfig{Examples: examples})
+		assert.Error(t, err)
+	})
+
+	format := func(t *testing.T, selector ExampleSelector, vs map[string]any) []string {
+		fewShot, err := NewFewShotTemplate(&FewShotConfig{
+			Examples:         examples,
+			ExampleTemplates: []schema.MessagesTemplate{schema.UserMessage("{question}")},
+			Selector:         selector,
+		})
+		assert.NoError(t, err)
+		msgs, err := fewShot.Format(ctx, vs, schema.FString)
+		assert.NoError(t, err)
+		return contents(msgs)
+	}
+
+	t.Run("fixed", func(t *testing.T) {
+		assert.Equal(t, []string{"apple color", "sky color"}, format(t, NewFixedExampleSelector(2), nil))
+		assert.Len(t, format(t, NewFixedExampleSelector(0), nil), 3)
+	})
+
+	t.Run("random", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			selected := format(t, NewRandomExampleSelector(2), nil)
+			assert.Len(t, selected, 2)
+			// in the order of the pool
+			assert.NotEqual(t, "banana taste", selected[0])
+		}
+		assert.Len(t, format(t, NewRandomExampleSelector(5), nil), 3)
+		assert.Len(t, format(t, NewRandomExampleSelector(0), nil), 3)
+	})
+
+	t.Run("length", func(t *testing.T) {
+		// each example is 3 tokens of content and 4 tokens of message overhead
+		selector, err := NewLengthExampleSelector(&LengthExampleSelectorConfig{MaxTokens: 15})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"apple color", "sky color"}, format(t, selector, nil))
+
+		_, err = NewLengthExampleSelector(&LengthExampleSelectorConfig{})
+		assert.Error(t, err)
+	})
+
+	t.Run("semantic", func(t *testing.T) {
+		embedder := &keywordEmbedder{keywords: []string{"color", "taste", "banana", "sky"}}
+		selector, err := NewSemanticExampleSelector(&SemanticExampleSelectorConfig{Embedder: embedder, InputKeys: []string{"question"}, K: 2})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"banana taste", "apple color"}, format(t, selector, map[string]any{"question": "banana taste"}))
+		assert.Equal(t, []string{"sky color", "apple color"}, format(t, selector, map[string]any{"question": "sky color"}))
+		// the examples are embedded once
+		assert.Len(t, embedder.calls, 3)
+		assert.Len(t, embedder.calls[0], 3)
+
+		// the input keys are declared as the variables of the few-shot template
+		fewShot, err := NewFewShotTemplate(&FewShotConfig{
+			Examples:         examples,
+			ExampleTemplates: []schema.MessagesTemplate{schema.UserMessage("{question}")},
+			Selector:         selector,
+		})
+		assert.NoError(t, err)
+		vars, err := FromMessages(schema.FString, fewShot, schema.UserMessage("{query}")).InputVariables()
+		assert.NoError(t, err)
+		assert.Equal(t, []schema.TemplateVariable{{Name: "question"}, {Name: "query"}}, vars)
+
+		_, err = NewSemanticExampleSelector(&SemanticExampleSelectorConfig{Embedder: embedder})
+		assert.Error(t, err)
+	})
+}
@@ -23,7 +23,14 @@ import (
 )
 
 var _ ChatTemplate = &DefaultChatTemplate{}
+var _ InputVariablesDeclarer = &DefaultChatTemplate{}
 
 type ChatTemplate interface {
 	Format(ctx context.Context, vs map[string]any, opts ...Option) ([]*schema.Message, error)
 }
+
+// InputVariablesDeclarer is implemented by the ChatTemplate declaring the variables it formats,
+// which graphs compiled with compose.WithTemplateVariablesCheck verify against the outputs of the predecessors.
+type InputVariablesDeclarer interface {
+	InputVariables() ([]schema.TemplateVariable, error)
+}
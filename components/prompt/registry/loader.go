@@ -0,0 +1,235 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/invopop/yaml"
+
+	"github.com/mrh997/eino/schema"
+)
+
+// partialsDir is the sub directory of the partials shared between prompts.
+const partialsDir = "partials"
+
+// promptFile is the YAML file of a prompt.
+type promptFile struct {
+	Name     string        `json:"name"`
+	Version  string        `json:"version"`
+	Format   string        `json:"format"`
+	Messages []messageSpec `json:"messages"`
+}
+
+type messageSpec struct {
+	Role        string `json:"role"`
+	Content     string `json:"content"`
+	Placeholder string `json:"placeholder"`
+	Optional    bool   `json:"optional"`
+}
+
+type promptVersion struct {
+	name       string
+	version    string
+	formatType schema.FormatType
+	templates  []schema.MessagesTemplate
+	path       string
+}
+
+var (
+	includePattern   = regexp.MustCompile(`\{\{>\s*([\w./-]+)\s*\}\}`)
+	jinjaMetaPattern = regexp.MustCompile(`^\{#\s*(\w+)\s*:\s*(.*?)\s*#\}$`)
+	jinjaRolePattern = regexp.MustCompile(`^<\|(system|user|assistant|placeholder:(\w+)(\?)?)\|>$`)
+)
+
+func parseFormatType(s string) (schema.FormatType, error) {
+	switch strings.ToLower(s) {
+	case "", "fstring", "f-string":
+		return schema.FString, nil
+	case "gotemplate", "go_template":
+		return schema.GoTemplate, nil
+	case "jinja2", "jinja":
+		return schema.Jinja2, nil
+	}
+	return 0, fmt.Errorf("unknown format type: %s", s)
+}
+
+// loadPartials loads the files under the partials directory, keyed by the path relative to it without extension.
+func loadPartials(dir string) (map[string]string, error) {
+	partials := make(map[string]string)
+	root := filepath.Join(dir, partialsDir)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return partials, nil
+	}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		partials[key] = strings.TrimSuffix(string(content), "\n")
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load partials: %w", err)
+	}
+	return partials, nil
+}
+
+// expandIncludes replaces the includes like {{> persona}} with the partials recursively.
+func expandIncludes(text string, partials map[string]string, including []string) (string, error) {
+	var expandErr error
+	ret := includePattern.ReplaceAllStringFunc(text, func(include string) string {
+		if expandErr != nil {
+			return include
+		}
+		key := includePattern.FindStringSubmatch(include)[1]
+		for _, k := range including {
+			if k == key {
+				expandErr = fmt.Errorf("circular include of partial %s", key)
+				return include
+			}
+		}
+		partial, ok := partials[key]
+		if !ok {
+			expandErr = fmt.Errorf("partial %s not found", key)
+			return include
+		}
+		expanded, err := expandIncludes(partial, partials, append(including, key))
+		if err != nil {
+			expandErr = err
+			return include
+		}
+		return expanded
+	})
+	return ret, expandErr
+}
+
+// loadPromptFile loads a prompt from a YAML file, or a Jinja file of role tagged sections, see Registry for the formats.
+func loadPromptFile(path string, partials map[string]string) (*promptVersion, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pf *promptFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		pf = &promptFile{}
+		if err = yaml.Unmarshal(content, pf); err != nil {
+			return nil, fmt.Errorf("invalid yaml: %w", err)
+		}
+	default:
+		pf, err = parseJinjaPrompt(string(content))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if pf.Name == "" {
+		base := filepath.Base(path)
+		pf.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	formatType, err := parseFormatType(pf.Format)
+	if err != nil {
+		return nil, err
+	}
+	if len(pf.Messages) == 0 {
+		return nil, fmt.Errorf("prompt %s has no message", pf.Name)
+	}
+
+	pv := &promptVersion{name: pf.Name, version: pf.Version, formatType: formatType, path: path}
+	for i, m := range pf.Messages {
+		if m.Placeholder != "" {
+			pv.templates = append(pv.templates, schema.MessagesPlaceholder(m.Placeholder, m.Optional))
+			continue
+		}
+		role := schema.RoleType(m.Role)
+		if role != schema.System && role != schema.User && role != schema.Assistant {
+			return nil, fmt.Errorf("invalid role of message[%d]: %q", i, m.Role)
+		}
+		text, err := expandIncludes(m.Content, partials, nil)
+		if err != nil {
+			return nil, err
+		}
+		pv.templates = append(pv.templates, &schema.Message{Role: role, Content: text})
+	}
+	return pv, nil
+}
+
+// parseJinjaPrompt parses the Jinja file, the metadata comments like {# version: v2 #} are at the start,
+// followed by the sections starting with the role tags <|system|>, <|user|>, <|assistant|>,
+// or the placeholders <|placeholder:history|> and <|placeholder:history?|> for the optional ones.
+func parseJinjaPrompt(content string) (*promptFile, error) {
+	pf := &promptFile{Format: "jinja2"}
+	var current *messageSpec
+	var body []string
+	flush := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(strings.Join(body, "\n"))
+			pf.Messages = append(pf.Messages, *current)
+		}
+		current, body = nil, nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current == nil && len(pf.Messages) == 0 {
+			if m := jinjaMetaPattern.FindStringSubmatch(trimmed); m != nil {
+				switch m[1] {
+				case "name":
+					pf.Name = m[2]
+				case "version":
+					pf.Version = m[2]
+				}
+				continue
+			}
+		}
+
+		if m := jinjaRolePattern.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			if m[2] != "" {
+				pf.Messages = append(pf.Messages, messageSpec{Placeholder: m[2], Optional: m[3] == "?"})
+				continue
+			}
+			current = &messageSpec{Role: m[1]}
+			continue
+		}
+
+		if current == nil {
+			if trimmed != "" {
+				return nil, fmt.Errorf("content outside of role sections: %q", trimmed)
+			}
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return pf, nil
+}
@@ -0,0 +1,387 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package registry provides a registry of prompt.ChatTemplate loaded from a directory of prompt files,
+// so that prompts can be maintained and versioned outside the code.
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrh997/eino/callbacks"
+	"github.com/mrh997/eino/components"
+	"github.com/mrh997/eino/components/prompt"
+	"github.com/mrh997/eino/schema"
+)
+
+// Config is the config of Registry.
+type Config struct {
+	// Dir is the directory of the prompt files, searched recursively except the partials sub directory.
+	Dir string
+	// ReloadInterval is the interval to check the changes of the prompt files, the registry reloads when any file changes.
+	// Optional. Default 0, no hot reload, call Registry.Reload to reload manually.
+	ReloadInterval time.Duration
+	// OnReloadError is called when the hot reload fails, the registry keeps the prompts loaded before.
+	// Optional. Default ignore the error.
+	OnReloadError func(err error)
+}
+
+// Registry loads the prompts from the files of a directory, each file is a version of a prompt.
+// a prompt is either a single file without version, or files all with versions.
+//
+// a YAML file (.yaml, .yml) defines the messages by role, or placeholders of MessagesPlaceholder,
+// name defaults to the file name, and format is one of fstring (default), gotemplate and jinja2, e.g.
+//
+//	name: qa
+//	version: v2
+//	format: fstring
+//	messages:
+//	  - role: system
+//	    content: "{{> persona}} answer in {language}."
+//	  - placeholder: history
+//	    optional: true
+//	  - role: user
+//	    content: "{question}"
+//
+// a Jinja file (.jinja, .j2) is formatted by jinja2, with the metadata comments at the start,
+// and the messages separated by role tags, e.g.
+//
+//	{# version: v2 #}
+//	<|system|>
+//	{{> persona}} answer in {{language}}.
+//	<|placeholder:history?|>
+//	<|user|>
+//	{{question}}
+//
+// the files in the partials sub directory are the partials shared between prompts, included by {{> name}},
+// where name is the path relative to the partials directory without extension.
+// the includes are expanded when loading, so the partials are formatted the same as the including prompt.
+type Registry struct {
+	dir           string
+	onReloadError func(err error)
+
+	mu      sync.RWMutex
+	prompts map[string]*promptVersions
+	files   map[string]time.Time
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+type promptVersions struct {
+	// versions are sorted from the oldest to the latest
+	versions  []string
+	byVersion map[string]*promptVersion
+}
+
+// NewRegistry creates a Registry and loads the prompts of the directory.
+func NewRegistry(_ context.Context, conf *Config) (*Registry, error) {
+	if conf == nil || conf.Dir == "" {
+		return nil, errors.New("prompt directory is required")
+	}
+
+	r := &Registry{
+		dir:           conf.Dir,
+		onReloadError: conf.OnReloadError,
+		done:          make(chan struct{}),
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	if conf.ReloadInterval > 0 {
+		go r.watch(conf.ReloadInterval)
+	}
+	return r, nil
+}
+
+// Reload reloads all the prompts of the directory, the prompts loaded before are kept if it fails.
+func (r *Registry) Reload() error {
+	files, err := r.scan()
+	if err != nil {
+		return err
+	}
+	partials, err := loadPartials(r.dir)
+	if err != nil {
+		return err
+	}
+
+	prompts := make(map[string]*promptVersions)
+	for path := range files {
+		if r.isPartial(path) {
+			continue
+		}
+		pv, err := loadPromptFile(path, partials)
+		if err != nil {
+			return fmt.Errorf("failed to load prompt file[%s]: %w", path, err)
+		}
+
+		vs, ok := prompts[pv.name]
+		if !ok {
+			vs = &promptVersions{byVersion: make(map[string]*promptVersion)}
+			prompts[pv.name] = vs
+		}
+		if dup, ok := vs.byVersion[pv.version]; ok {
+			return fmt.Errorf("duplicate version %q of prompt %s in files %s and %s", pv.version, pv.name, dup.path, path)
+		}
+		// the empty version selects the latest one, so an unversioned file can't be told from the latest version
+		if len(vs.versions) > 0 && (pv.version == "" || vs.versions[0] == "") {
+			return fmt.Errorf("prompt %s has both versioned and unversioned files %s and %s",
+				pv.name, vs.byVersion[vs.versions[0]].path, path)
+		}
+		vs.byVersion[pv.version] = pv
+		vs.versions = append(vs.versions, pv.version)
+	}
+	for _, vs := range prompts {
+		sort.Slice(vs.versions, func(i, j int) bool {
+			return compareVersions(vs.versions[i], vs.versions[j]) < 0
+		})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prompts = prompts
+	r.files = files
+	return nil
+}
+
+// Close stops the hot reload.
+func (r *Registry) Close() {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+}
+
+// Names returns the names of all the prompts, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.prompts))
+	for name := range r.prompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Versions returns the versions of the prompt, from the oldest to the latest.
+func (r *Registry) Versions(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vs, ok := r.prompts[name]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), vs.versions...)
+}
+
+// Get returns the ChatTemplate of the prompt, which formats the latest version by default,
+// or the version selected by WithVersion.
+// the version is resolved on each Format, so the template reflects the reloads.
+// e.g.
+//
+//	tpl, err := reg.Get("qa")
+//	msgs, err := tpl.Format(ctx, vars, registry.WithVersion("v1"))
+func (r *Registry) Get(name string) (prompt.ChatTemplate, error) {
+	if _, err := r.lookup(name, ""); err != nil {
+		return nil, err
+	}
+	return &registryTemplate{registry: r, name: name}, nil
+}
+
+// GetVersion returns the ChatTemplate of the version of the prompt, the version is fixed and not changed by reloads.
+func (r *Registry) GetVersion(name, version string) (*prompt.DefaultChatTemplate, error) {
+	pv, err := r.lookup(name, version)
+	if err != nil {
+		return nil, err
+	}
+	return prompt.FromMessages(pv.formatType, pv.templates...), nil
+}
+
+// lookup returns the version of the prompt, the latest version if version is empty.
+func (r *Registry) lookup(name, version string) (*promptVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vs, ok := r.prompts[name]
+	if !ok {
+		return nil, fmt.Errorf("prompt %s not found", name)
+	}
+	if version == "" {
+		return vs.byVersion[vs.versions[len(vs.versions)-1]], nil
+	}
+	pv, ok := vs.byVersion[version]
+	if !ok {
+		return nil, fmt.Errorf("version %s of prompt %s not found", version, name)
+	}
+	return pv, nil
+}
+
+func (r *Registry) isPartial(path string) bool {
+	rel, err := filepath.Rel(r.dir, path)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(filepath.ToSlash(rel), partialsDir+"/")
+}
+
+// scan returns the prompt files and partials of the directory with their modification times.
+func (r *Registry) scan() (map[string]time.Time, error) {
+	files := make(map[string]time.Time)
+	err := filepath.Walk(r.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		if r.isPartial(path) {
+			files[path] = info.ModTime()
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".jinja", ".j2":
+			files[path] = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan prompt directory[%s]: %w", r.dir, err)
+	}
+	return files, nil
+}
+
+func (r *Registry) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		}
+
+		files, err := r.scan()
+		if err == nil && !r.changed(files) {
+			continue
+		}
+		if err == nil {
+			err = r.Reload()
+		}
+		if err != nil && r.onReloadError != nil {
+			r.onReloadError(err)
+		}
+	}
+}
+
+func (r *Registry) changed(files map[string]time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(files) != len(r.files) {
+		return true
+	}
+	for path, modTime := range files {
+		if old, ok := r.files[path]; !ok || !old.Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+type options struct {
+	version string
+}
+
+// WithVersion selects the version of the prompt to format, for the ChatTemplate returned by Registry.Get.
+func WithVersion(version string) prompt.Option {
+	return prompt.WrapImplSpecificOptFn(func(o *options) {
+		o.version = version
+	})
+}
+
+type registryTemplate struct {
+	registry *Registry
+	name     string
+}
+
+func (t *registryTemplate) Format(ctx context.Context, vs map[string]any, opts ...prompt.Option) ([]*schema.Message, error) {
+	ctx = callbacks.EnsureRunInfo(ctx, t.GetType(), components.ComponentOfPrompt)
+
+	o := prompt.GetImplSpecificOptions(&options{}, opts...)
+	pv, err := t.registry.lookup(t.name, o.version)
+	if err != nil {
+		ctx = callbacks.OnStart(ctx, &prompt.CallbackInput{Variables: vs})
+		_ = callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	return prompt.FromMessages(pv.formatType, pv.templates...).Format(ctx, vs, opts...)
+}
+
+// InputVariables returns the variables of the latest version of the prompt.
+func (t *registryTemplate) InputVariables() ([]schema.TemplateVariable, error) {
+	pv, err := t.registry.lookup(t.name, "")
+	if err != nil {
+		return nil, err
+	}
+	return schema.ExtractVariables(pv.formatType, pv.templates...)
+}
+
+func (t *registryTemplate) GetType() string {
+	return "Registry"
+}
+
+func (t *registryTemplate) IsCallbacksEnabled() bool {
+	return true
+}
+
+// compareVersions compares the versions naturally, comparing the digits by value, e.g. v2 < v10.
+func compareVersions(a, b string) int {
+	for a != "" && b != "" {
+		ca, cb := chunk(a), chunk(b)
+		a, b = a[len(ca):], b[len(cb):]
+		if isDigit(ca[0]) && isDigit(cb[0]) {
+			na, nb := strings.TrimLeft(ca, "0"), strings.TrimLeft(cb, "0")
+			if len(na) != len(nb) {
+				return len(na) - len(nb)
+			}
+		}
+		if c := strings.Compare(ca, cb); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// chunk returns the leading run of digits or non digits of the non empty s.
+func chunk(s string) string {
+	digit := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digit {
+		i++
+	}
+	return s[:i]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
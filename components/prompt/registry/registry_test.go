@@ -0,0 +1,199 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/schema"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"partials/persona.txt": "You are a helpful assistant.",
+		"partials/rules.txt":   "{{> persona}} Be brief.",
+		"qa_v1.yaml": `
+name: qa
+version: v2
+messages:
+  - role: system
+    content: "{{> rules}} Answer in {language}."
+  - placeholder: history
+    optional: true
+  - role: user
+    content: "{question}"
+`,
+		"qa_v10.yaml": `
+name: qa
+version: v10
+format: gotemplate
+messages:
+  - role: user
+    content: "{{.question}}?"
+`,
+		"chat.jinja": `{# version: v1 #}
+<|system|>
+{{> persona}}
+Answer in {{ language }}.
+<|placeholder:history|>
+<|user|>
+{{ question }}
+`,
+	})
+
+	r, err := NewRegistry(ctx, &Config{Dir: dir})
+	assert.NoError(t, err)
+	defer r.Close()
+	assert.Equal(t, []string{"chat", "qa"}, r.Names())
+	assert.Equal(t, []string{"v2", "v10"}, r.Versions("qa"))
+
+	vs := map[string]any{"language": "English", "question": "why"}
+
+	t.Run("yaml", func(t *testing.T) {
+		tpl, err := r.Get("qa")
+		assert.NoError(t, err)
+		msgs, err := tpl.Format(ctx, vs)
+		assert.NoError(t, err)
+		assert.Equal(t, []*schema.Message{schema.UserMessage("why?")}, msgs)
+
+		msgs, err = tpl.Format(ctx, vs, WithVersion("v2"))
+		assert.NoError(t, err)
+		assert.Equal(t, []*schema.Message{
+			schema.SystemMessage("You are a helpful assistant. Be brief. Answer in English."),
+			schema.UserMessage("why"),
+		}, msgs)
+
+		_, err = tpl.Format(ctx, vs, WithVersion("v3"))
+		assert.ErrorContains(t, err, "version v3 of prompt qa not found")
+	})
+
+	t.Run("jinja", func(t *testing.T) {
+		tpl, err := r.GetVersion("chat", "v1")
+		assert.NoError(t, err)
+		msgs, err := tpl.Format(ctx, map[string]any{
+			"language": "English",
+			"question": "why",
+			"history":  []*schema.Message{schema.UserMessage("hi"), schema.AssistantMessage("hello", nil)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []*schema.Message{
+			schema.SystemMessage("You are a helpful assistant.\nAnswer in English."),
+			schema.UserMessage("hi"),
+			schema.AssistantMessage("hello", nil),
+			schema.UserMessage("why"),
+		}, msgs)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := r.Get("unknown")
+		assert.ErrorContains(t, err, "prompt unknown not found")
+
+		_, err = NewRegistry(ctx, &Config{})
+		assert.Error(t, err)
+
+		for name, content := range map[string]string{
+			"include":     "messages:\n  - role: user\n    content: \"{{> missing}}\"\n",
+			"circular":    "messages:\n  - role: user\n    content: \"{{> a}}\"\n",
+			"role":        "messages:\n  - role: tool\n    content: hi\n",
+			"duplicate":   "name: qa\nversion: v2\nmessages:\n  - role: user\n    content: hi\n",
+			"unversioned": "name: qa\nmessages:\n  - role: user\n    content: hi\n",
+		} {
+			d := t.TempDir()
+			writeFiles(t, d, map[string]string{
+				"partials/a.txt": "{{> b}}",
+				"partials/b.txt": "{{> a}}",
+				"qa.yaml":        "name: qa\nversion: v2\nmessages:\n  - role: user\n    content: hi\n",
+				name + ".yaml":   content,
+			})
+			_, err = NewRegistry(ctx, &Config{Dir: d})
+			assert.Error(t, err, name)
+		}
+
+		d := t.TempDir()
+		writeFiles(t, d, map[string]string{
+			"qa.yaml":    "messages:\n  - role: user\n    content: hi\n",
+			"qa_v1.yaml": "name: qa\nversion: v1\nmessages:\n  - role: user\n    content: hi\n",
+		})
+		_, err = NewRegistry(ctx, &Config{Dir: d})
+		assert.ErrorContains(t, err, "prompt qa has both versioned and unversioned files")
+	})
+}
+
+func TestRegistryReload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"greet.yaml": "version: v1\nmessages:\n  - role: user\n    content: hello {name}\n",
+	})
+
+	reloadErrs := make(chan error, 10)
+	r, err := NewRegistry(ctx, &Config{
+		Dir:            dir,
+		ReloadInterval: 10 * time.Millisecond,
+		OnReloadError:  func(err error) { reloadErrs <- err },
+	})
+	assert.NoError(t, err)
+	defer r.Close()
+
+	tpl, err := r.Get("greet")
+	assert.NoError(t, err)
+
+	writeFiles(t, dir, map[string]string{
+		"greet_v2.yaml": "version: v2\nname: greet\nmessages:\n  - role: user\n    content: hi {name}\n",
+	})
+	assert.Eventually(t, func() bool {
+		return len(r.Versions("greet")) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	msgs, err := tpl.Format(ctx, map[string]any{"name": "eino"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hi eino", msgs[0].Content)
+
+	// the invalid file is reported, and the prompts loaded before are kept
+	writeFiles(t, dir, map[string]string{"broken.yaml": "messages: ["})
+	select {
+	case err = <-reloadErrs:
+		assert.ErrorContains(t, err, "broken.yaml")
+	case <-time.After(time.Second):
+		t.Fatal("reload error not reported")
+	}
+	assert.Equal(t, []string{"v1", "v2"}, r.Versions("greet"))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.True(t, compareVersions("v2", "v10") < 0)
+	assert.True(t, compareVersions("v1.10", "v1.9") > 0)
+	assert.True(t, compareVersions("", "v1") < 0)
+	assert.True(t, compareVersions("v1-beta", "v1") > 0)
+	assert.Equal(t, 0, compareVersions("v3", "v3"))
+}
@@ -41,3 +41,23 @@ type StreamableTool interface {
 
 	StreamableRun(ctx context.Context, argumentsInJSON string, opts ...Option) (*schema.StreamReader[string], error)
 }
+
+// MultimodalTool the tool returning multimodal content for ToolsNode execution, e.g. a chart rendered or an image fetched by the tool.
+// ToolsNode prefers MultimodalRun to InvokableRun and StreamableRun, and sets the parts as the MultiContent of the tool message.
+type MultimodalTool interface {
+	BaseTool
+
+	MultimodalRun(ctx context.Context, argumentsInJSON string, opts ...Option) ([]schema.ChatMessagePart, error)
+}
+
+// AsyncTool the long-running tool for ToolsNode execution, e.g. report generation or CI runs taking minutes.
+// ToolsNode starts the job by StartJob and interrupts the graph with the pending job, and gets the result by JobResult when the graph is resumed,
+// or uses the result passed by compose.WithToolJobResults.
+type AsyncTool interface {
+	BaseTool
+
+	// StartJob starts the work and returns the ID of the job.
+	StartJob(ctx context.Context, argumentsInJSON string, opts ...Option) (jobID string, err error)
+	// JobResult returns the result of the job, done is false if the job is still running.
+	JobResult(ctx context.Context, jobID string) (result string, done bool, err error)
+}
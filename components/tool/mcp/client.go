@@ -0,0 +1,346 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package mcp connects tools to the Model Context Protocol (https://modelcontextprotocol.io).
+// the Client uses the tools of an MCP server as InvokableTools, and the Server serves eino tools to MCP clients.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/mrh997/eino/components"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+const (
+	defaultClientName = "eino"
+	defaultVersion    = "1.0.0"
+)
+
+// StdioConfig is the config to start an MCP server as a sub process, and talk to it over its stdin and stdout.
+type StdioConfig struct {
+	// Command is the executable of the server.
+	Command string
+	// Args are the arguments of the command.
+	// Optional.
+	Args []string
+	// Env is appended to the environment of the current process.
+	// Optional.
+	Env []string
+	// Dir is the working directory of the server.
+	// Optional. By default, the working directory of the current process is used.
+	Dir string
+	// Stderr receives the logs of the server.
+	// Optional. By default, the logs are discarded.
+	Stderr io.Writer
+}
+
+// HTTPConfig is the config to connect to an MCP server over streamable HTTP.
+type HTTPConfig struct {
+	// URL is the MCP endpoint of the server, e.g. http://localhost:8080/mcp.
+	URL string
+	// HTTPClient is the client used to send requests.
+	// Optional. By default, http.DefaultClient is used.
+	HTTPClient *http.Client
+	// Headers are added to every request, e.g. {"Authorization": "Bearer xxx"}.
+	// Optional.
+	Headers map[string]string
+}
+
+var _ components.Closer = (*Client)(nil)
+
+// Client is a connection to an MCP server.
+// it is safe for concurrent use.
+type Client struct {
+	t      transport
+	nextID int64
+
+	serverName    string
+	serverVersion string
+}
+
+// NewStdioClient starts the server command and initializes the session.
+// the sub process is killed when the client is closed.
+func NewStdioClient(ctx context.Context, conf *StdioConfig) (*Client, error) {
+	if conf == nil || conf.Command == "" {
+		return nil, errors.New("command is required")
+	}
+
+	cmd := exec.Command(conf.Command, conf.Args...)
+	cmd.Dir = conf.Dir
+	if len(conf.Env) > 0 {
+		cmd.Env = append(os.Environ(), conf.Env...)
+	}
+	cmd.Stderr = conf.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start mcp server: %w", err)
+	}
+
+	t := newStreamTransport(stdout, stdin, func() error {
+		_ = stdin.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil
+	})
+
+	return newClient(ctx, t)
+}
+
+// NewHTTPClient connects to a streamable HTTP server and initializes the session.
+func NewHTTPClient(ctx context.Context, conf *HTTPConfig) (*Client, error) {
+	if conf == nil || conf.URL == "" {
+		return nil, errors.New("url is required")
+	}
+
+	client := conf.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return newClient(ctx, &httpTransport{
+		url:     conf.URL,
+		client:  client,
+		headers: conf.Headers,
+	})
+}
+
+// NewClient initializes a session over an established byte stream with an MCP server,
+// which exchanges newline delimited JSON-RPC messages in the same way as the stdio transport.
+func NewClient(ctx context.Context, r io.Reader, w io.WriteCloser) (*Client, error) {
+	return newClient(ctx, newStreamTransport(r, w, w.Close))
+}
+
+func newClient(ctx context.Context, t transport) (*Client, error) {
+	c := &Client{t: t}
+
+	result := &initializeResult{}
+	err := c.call(ctx, methodInitialize, &initializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      implementation{Name: defaultClientName, Version: defaultVersion},
+	}, result)
+	if err != nil {
+		_ = t.close()
+		return nil, fmt.Errorf("failed to initialize mcp session: %w", err)
+	}
+
+	if ht, ok := t.(*httpTransport); ok {
+		ht.setVersion(result.ProtocolVersion)
+	}
+	c.serverName = result.ServerInfo.Name
+	c.serverVersion = result.ServerInfo.Version
+
+	if err = t.notify(ctx, &jsonrpcMessage{JSONRPC: "2.0", Method: methodInitialized}); err != nil {
+		_ = t.close()
+		return nil, fmt.Errorf("failed to initialize mcp session: %w", err)
+	}
+
+	return c, nil
+}
+
+// ServerInfo returns the name and version reported by the server.
+func (c *Client) ServerInfo() (name, version string) {
+	return c.serverName, c.serverVersion
+}
+
+// ListTools lists all the tools of the server.
+func (c *Client) ListTools(ctx context.Context) ([]*Tool, error) {
+	var tools []*Tool
+	params := &listToolsParams{}
+	for {
+		result := &listToolsResult{}
+		if err := c.call(ctx, methodListTools, params, result); err != nil {
+			return nil, err
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		params.Cursor = result.NextCursor
+	}
+}
+
+// CallTool calls a tool of the server, arguments is a JSON object.
+// a tool failure is reported by CallToolResult.IsError rather than the error.
+func (c *Client) CallTool(ctx context.Context, name string, arguments string) (*CallToolResult, error) {
+	params := &callToolParams{Name: name}
+	if strings.TrimSpace(arguments) != "" {
+		params.Arguments = json.RawMessage(arguments)
+	}
+
+	result := &CallToolResult{}
+	if err := c.call(ctx, methodCallTool, params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetTools returns the tools of the server as InvokableTools.
+// toolNames selects the tools to return, by default all the tools are returned.
+// e.g.
+//
+//	cli, err := mcp.NewStdioClient(ctx, &mcp.StdioConfig{Command: "my-mcp-server"})
+//	defer cli.Close(ctx)
+//	tools, err := cli.GetTools(ctx)
+func (c *Client) GetTools(ctx context.Context, toolNames ...string) ([]tool.InvokableTool, error) {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mcp tools: %w", err)
+	}
+
+	byName := make(map[string]*Tool, len(tools))
+	for _, t := range tools {
+		byName[t.Name] = t
+	}
+	if len(toolNames) > 0 {
+		selected := make([]*Tool, 0, len(toolNames))
+		for _, name := range toolNames {
+			t, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("mcp tool not found: %s", name)
+			}
+			selected = append(selected, t)
+		}
+		tools = selected
+	}
+
+	ret := make([]tool.InvokableTool, 0, len(tools))
+	for _, t := range tools {
+		info, err := toToolInfo(t)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &mcpTool{cli: c, info: info})
+	}
+	return ret, nil
+}
+
+// Close closes the session, and kills the server process of the stdio transport.
+// it implements components.Closer.
+func (c *Client) Close(_ context.Context) error {
+	return c.t.close()
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	req := &jsonrpcMessage{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(strconv.FormatInt(atomic.AddInt64(&c.nextID, 1), 10)),
+		Method:  method,
+	}
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = b
+	}
+
+	resp, err := c.t.call(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to call mcp method[%s]: %w", method, err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("failed to call mcp method[%s]: %w", method, resp.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	if err = json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("failed to decode result of mcp method[%s]: %w", method, err)
+	}
+	return nil
+}
+
+func toToolInfo(t *Tool) (*schema.ToolInfo, error) {
+	js := &jsonschema.Schema{}
+	if len(t.InputSchema) > 0 {
+		if err := json.Unmarshal(t.InputSchema, js); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal input schema of mcp tool[%s]: %w", t.Name, err)
+		}
+	}
+
+	return &schema.ToolInfo{
+		Name:        t.Name,
+		Desc:        t.Description,
+		ParamsOneOf: schema.NewParamsOneOfByJSONSchema(js),
+	}, nil
+}
+
+// mcpTool calls a tool of an MCP server.
+type mcpTool struct {
+	cli  *Client
+	info *schema.ToolInfo
+}
+
+func (m *mcpTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return m.info, nil
+}
+
+// InvokableRun returns the text contents of the result, joined by new lines,
+// or the JSON of all the contents if there are contents other than text.
+// a result with IsError returns an error carrying the contents.
+func (m *mcpTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	result, err := m.cli.CallTool(ctx, m.info.Name, argumentsInJSON)
+	if err != nil {
+		return "", fmt.Errorf("[MCPTool] failed to call tool, toolName=%s, err=%w", m.info.Name, err)
+	}
+
+	output, err := formatContents(result.Content)
+	if err != nil {
+		return "", fmt.Errorf("[MCPTool] failed to marshal result, toolName=%s, err=%w", m.info.Name, err)
+	}
+	if result.IsError {
+		return "", fmt.Errorf("[MCPTool] tool returned error, toolName=%s, result=%s", m.info.Name, output)
+	}
+	return output, nil
+}
+
+func formatContents(contents []*Content) (string, error) {
+	texts := make([]string, 0, len(contents))
+	for _, c := range contents {
+		if c.Type != ContentTypeText {
+			b, err := json.Marshal(contents)
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		}
+		texts = append(texts, c.Text)
+	}
+	return strings.Join(texts, "\n"), nil
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const stubServerEnv = "EINO_MCP_STUB_SERVER"
+
+// stubHandle answers the requests of the tests, tools are listed in two pages.
+func stubHandle(req *jsonrpcMessage) *jsonrpcMessage {
+	resp := &jsonrpcMessage{JSONRPC: "2.0", ID: req.ID}
+	var result any
+	switch req.Method {
+	case methodInitialize:
+		result = &initializeResult{ProtocolVersion: ProtocolVersion, ServerInfo: implementation{Name: "stub", Version: "0.1"}}
+	case methodListTools:
+		params := &listToolsParams{}
+		_ = json.Unmarshal(req.Params, params)
+		if params.Cursor == "" {
+			result = &listToolsResult{
+				Tools: []*Tool{{
+					Name:        "echo",
+					Description: "echo the text",
+					InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string","description":"the text"}},"required":["text"]}`),
+				}},
+				NextCursor: "page2",
+			}
+		} else {
+			result = &listToolsResult{Tools: []*Tool{
+				{Name: "fail", InputSchema: json.RawMessage(`{"type":"object"}`)},
+				{Name: "image", InputSchema: json.RawMessage(`{"type":"object"}`)},
+			}}
+		}
+	case methodCallTool:
+		params := &callToolParams{}
+		_ = json.Unmarshal(req.Params, params)
+		switch params.Name {
+		case "echo":
+			args := map[string]string{}
+			_ = json.Unmarshal(params.Arguments, &args)
+			result = &CallToolResult{Content: []*Content{{Type: ContentTypeText, Text: args["text"]}, {Type: ContentTypeText, Text: "done"}}}
+		case "fail":
+			result = &CallToolResult{Content: []*Content{{Type: ContentTypeText, Text: "boom"}}, IsError: true}
+		case "image":
+			result = &CallToolResult{Content: []*Content{{Type: ContentTypeImage, Data: "aGk=", MimeType: "image/png"}}}
+		default:
+			resp.Error = &jsonrpcError{Code: codeInvalidParams, Message: "unknown tool: " + params.Name}
+			return resp
+		}
+	default:
+		resp.Error = &jsonrpcError{Code: codeMethodNotFound, Message: "method not found"}
+		return resp
+	}
+	resp.Result, _ = json.Marshal(result)
+	return resp
+}
+
+// TestStdioStubServer is the stub server started by the stdio tests as a sub process, it does nothing in normal runs.
+func TestStdioStubServer(t *testing.T) {
+	if os.Getenv(stubServerEnv) != "1" {
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		req := &jsonrpcMessage{}
+		if err := json.Unmarshal(scanner.Bytes(), req); err != nil || !req.isRequest() {
+			continue
+		}
+		b, _ := json.Marshal(stubHandle(req))
+		fmt.Fprintf(os.Stdout, "%s\n", b)
+	}
+	os.Exit(0)
+}
+
+func testClientTools(t *testing.T, cli *Client) {
+	ctx := context.Background()
+
+	name, version := cli.ServerInfo()
+	assert.Equal(t, "stub", name)
+	assert.Equal(t, "0.1", version)
+
+	tools, err := cli.GetTools(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tools, 3)
+
+	info, err := tools[0].Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "echo", info.Name)
+	assert.Equal(t, "echo the text", info.Desc)
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"text"}, js.Required)
+	assert.Equal(t, "the text", js.Properties.Value("text").Description)
+
+	out, err := tools[0].InvokableRun(ctx, `{"text":"hello"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\ndone", out)
+
+	_, err = tools[1].InvokableRun(ctx, `{}`)
+	assert.ErrorContains(t, err, "boom")
+
+	out, err = tools[2].InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"type":"image","data":"aGk=","mimeType":"image/png"}]`, out)
+
+	selected, err := cli.GetTools(ctx, "image")
+	assert.NoError(t, err)
+	assert.Len(t, selected, 1)
+	_, err = cli.GetTools(ctx, "unknown")
+	assert.ErrorContains(t, err, "mcp tool not found: unknown")
+
+	_, err = cli.CallTool(ctx, "unknown", "")
+	assert.ErrorContains(t, err, "unknown tool")
+}
+
+func TestStdioClient(t *testing.T) {
+	ctx := context.Background()
+
+	cli, err := NewStdioClient(ctx, &StdioConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestStdioStubServer$"},
+		Env:     []string{stubServerEnv + "=1"},
+	})
+	assert.NoError(t, err)
+
+	testClientTools(t, cli)
+
+	assert.NoError(t, cli.Close(context.Background()))
+	_, err = cli.ListTools(ctx)
+	assert.Error(t, err)
+}
+
+func TestHTTPClient(t *testing.T) {
+	var deleted bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = r.Header.Get(headerSessionID) == "s1"
+			return
+		}
+
+		req := &jsonrpcMessage{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.Method != methodInitialize && r.Header.Get(headerSessionID) != "s1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.isNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		b, _ := json.Marshal(stubHandle(req))
+		w.Header().Set(headerSessionID, "s1")
+		if req.Method == methodCallTool {
+			// respond in server sent events, with a notification before the response
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+			_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", b)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	cli, err := NewHTTPClient(context.Background(), &HTTPConfig{URL: srv.URL})
+	assert.NoError(t, err)
+
+	testClientTools(t, cli)
+
+	assert.NoError(t, cli.Close(context.Background()))
+	assert.True(t, deleted)
+}
@@ -0,0 +1,369 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/components/tool/utils"
+	"github.com/mrh997/eino/schema"
+)
+
+// ServerConfig is the config of an MCP server serving eino tools.
+type ServerConfig struct {
+	// Name is the server name reported to clients.
+	// Optional. Default "eino".
+	Name string
+	// Version is the server version reported to clients.
+	// Optional. Default "1.0.0".
+	Version string
+	// Tools are the tools to serve, each of them must be an InvokableTool or a StreamableTool.
+	// the output stream of a StreamableTool is concatenated into one text content.
+	// tools wrapped by utils.WrapToolWithErrorHandler return the handled error as a normal result.
+	Tools []tool.BaseTool
+	// ErrorHandler converts the error of a tool to the text of the error result, which has isError set.
+	// Optional. By default, err.Error() is used.
+	ErrorHandler utils.ErrorHandler
+}
+
+// Server serves eino tools to MCP clients, over stdio with Serve / ServeStdio, or over streamable HTTP as an http.Handler.
+type Server struct {
+	name         string
+	version      string
+	tools        []*Tool
+	impls        map[string]tool.BaseTool
+	errorHandler utils.ErrorHandler
+
+	sessions sync.Map // session id -> struct{}
+}
+
+// NewServer creates an MCP server serving the given tools.
+// e.g.
+//
+//	srv, err := mcp.NewServer(ctx, &mcp.ServerConfig{Tools: []tool.BaseTool{searchTool}})
+//	err = srv.ServeStdio(ctx)
+//	// or over HTTP
+//	http.Handle("/mcp", srv)
+func NewServer(ctx context.Context, conf *ServerConfig) (*Server, error) {
+	if conf == nil {
+		return nil, errors.New("config is required")
+	}
+
+	s := &Server{
+		name:         conf.Name,
+		version:      conf.Version,
+		impls:        make(map[string]tool.BaseTool, len(conf.Tools)),
+		errorHandler: conf.ErrorHandler,
+	}
+	if s.name == "" {
+		s.name = defaultClientName
+	}
+	if s.version == "" {
+		s.version = defaultVersion
+	}
+	if s.errorHandler == nil {
+		s.errorHandler = func(_ context.Context, err error) string {
+			return err.Error()
+		}
+	}
+
+	for _, t := range conf.Tools {
+		_, invokable := t.(tool.InvokableTool)
+		_, streamable := t.(tool.StreamableTool)
+		if !invokable && !streamable {
+			return nil, errors.New("tool must be an InvokableTool or a StreamableTool")
+		}
+
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tool info: %w", err)
+		}
+		if _, ok := s.impls[info.Name]; ok {
+			return nil, fmt.Errorf("duplicate tool name: %s", info.Name)
+		}
+
+		inputSchema, err := toInputSchema(info)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert params of tool[%s]: %w", info.Name, err)
+		}
+		s.tools = append(s.tools, &Tool{Name: info.Name, Description: info.Desc, InputSchema: inputSchema})
+		s.impls[info.Name] = t
+	}
+
+	return s, nil
+}
+
+func toInputSchema(info *schema.ToolInfo) (json.RawMessage, error) {
+	if info.ParamsOneOf == nil {
+		return json.RawMessage(`{"type":"object"}`), nil
+	}
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	if err != nil {
+		return nil, err
+	}
+	if js == nil {
+		return json.RawMessage(`{"type":"object"}`), nil
+	}
+	return json.Marshal(js)
+}
+
+// ServeStdio serves a client over the stdin and stdout of the current process, see Serve.
+func (s *Server) ServeStdio(ctx context.Context) error {
+	return s.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// Serve serves a client over a byte stream of newline delimited JSON-RPC messages,
+// until the input ends or ctx is done. requests are handled concurrently.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		writeMu sync.Mutex
+		wg      sync.WaitGroup
+	)
+	write := func(msg *jsonrpcMessage) {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, _ = w.Write(append(b, '\n'))
+	}
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case line := <-lines:
+			msg := &jsonrpcMessage{}
+			if err := json.Unmarshal(line, msg); err != nil {
+				write(&jsonrpcMessage{JSONRPC: "2.0", ID: json.RawMessage("null"),
+					Error: &jsonrpcError{Code: codeParseError, Message: err.Error()}})
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp := s.handle(ctx, msg); resp != nil {
+					write(resp)
+				}
+			}()
+		}
+	}
+}
+
+// ServeHTTP implements the streamable HTTP transport, responding to each request in JSON.
+// a session id is assigned on initialization, and the session is terminated by a DELETE request.
+// server initiated streams over GET are not supported.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		sid := r.Header.Get(headerSessionID)
+		if _, ok := s.sessions.LoadAndDelete(sid); !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	msg := &jsonrpcMessage{}
+	if err := json.NewDecoder(r.Body).Decode(msg); err != nil {
+		writeHTTPResponse(w, http.StatusBadRequest, &jsonrpcMessage{JSONRPC: "2.0", ID: json.RawMessage("null"),
+			Error: &jsonrpcError{Code: codeParseError, Message: err.Error()}})
+		return
+	}
+
+	if msg.Method == methodInitialize {
+		sid, err := newSessionID()
+		if err != nil {
+			writeHTTPResponse(w, http.StatusInternalServerError, &jsonrpcMessage{JSONRPC: "2.0", ID: msg.ID,
+				Error: &jsonrpcError{Code: codeInternalError, Message: err.Error()}})
+			return
+		}
+		s.sessions.Store(sid, struct{}{})
+		w.Header().Set(headerSessionID, sid)
+	} else {
+		sid := r.Header.Get(headerSessionID)
+		if sid == "" {
+			writeHTTPResponse(w, http.StatusBadRequest, &jsonrpcMessage{JSONRPC: "2.0", ID: msg.ID,
+				Error: &jsonrpcError{Code: codeInvalidRequest, Message: "missing session id"}})
+			return
+		}
+		if _, ok := s.sessions.Load(sid); !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+
+	resp := s.handle(r.Context(), msg)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeHTTPResponse(w, http.StatusOK, resp)
+}
+
+func writeHTTPResponse(w http.ResponseWriter, status int, msg *jsonrpcMessage) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(msg)
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// handle returns the response of a request, or nil for notifications and responses.
+func (s *Server) handle(ctx context.Context, msg *jsonrpcMessage) *jsonrpcMessage {
+	if !msg.isRequest() {
+		return nil
+	}
+
+	resp := &jsonrpcMessage{JSONRPC: "2.0", ID: msg.ID}
+	var result any
+	switch msg.Method {
+	case methodInitialize:
+		result = &initializeResult{
+			ProtocolVersion: ProtocolVersion,
+			Capabilities:    map[string]any{"tools": map[string]any{}},
+			ServerInfo:      implementation{Name: s.name, Version: s.version},
+		}
+	case methodPing:
+		result = struct{}{}
+	case methodListTools:
+		result = &listToolsResult{Tools: s.tools}
+	case methodCallTool:
+		params := &callToolParams{}
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			resp.Error = &jsonrpcError{Code: codeInvalidParams, Message: err.Error()}
+			return resp
+		}
+		t, ok := s.impls[params.Name]
+		if !ok {
+			resp.Error = &jsonrpcError{Code: codeInvalidParams, Message: "unknown tool: " + params.Name}
+			return resp
+		}
+		result = s.callTool(ctx, t, string(params.Arguments))
+	default:
+		resp.Error = &jsonrpcError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+		return resp
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		resp.Error = &jsonrpcError{Code: codeInternalError, Message: err.Error()}
+		return resp
+	}
+	resp.Result = b
+	return resp
+}
+
+// callTool runs the tool, tool errors are returned as the error result rather than a JSON-RPC error.
+func (s *Server) callTool(ctx context.Context, t tool.BaseTool, arguments string) *CallToolResult {
+	if arguments == "" || arguments == "null" {
+		arguments = "{}"
+	}
+
+	output, err := runTool(ctx, t, arguments)
+	if err != nil {
+		return &CallToolResult{
+			Content: []*Content{{Type: ContentTypeText, Text: s.errorHandler(ctx, err)}},
+			IsError: true,
+		}
+	}
+	return &CallToolResult{Content: []*Content{{Type: ContentTypeText, Text: output}}}
+}
+
+func runTool(ctx context.Context, t tool.BaseTool, arguments string) (output string, err error) {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			err = fmt.Errorf("tool panic: %v", panicErr)
+		}
+	}()
+
+	if it, ok := t.(tool.InvokableTool); ok {
+		return it.InvokableRun(ctx, arguments)
+	}
+
+	sr, err := t.(tool.StreamableTool).StreamableRun(ctx, arguments)
+	if err != nil {
+		return "", err
+	}
+	defer sr.Close()
+
+	var sb strings.Builder
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(chunk)
+	}
+}
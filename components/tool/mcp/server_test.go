@@ -0,0 +1,168 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/components/tool/utils"
+	"github.com/mrh997/eino/schema"
+)
+
+type greetInput struct {
+	Name string `json:"name" jsonschema:"description=who to greet"`
+}
+
+func newTestServer(t *testing.T) *Server {
+	greet, err := utils.InferTool("greet", "greet someone", func(ctx context.Context, in *greetInput) (string, error) {
+		return "hello " + in.Name, nil
+	})
+	assert.NoError(t, err)
+
+	fail, err := utils.InferTool("fail", "always fails", func(ctx context.Context, in *greetInput) (string, error) {
+		return "", errors.New("boom")
+	})
+	assert.NoError(t, err)
+
+	handled, err := utils.InferTool("handled", "fails with handled error", func(ctx context.Context, in *greetInput) (string, error) {
+		return "", errors.New("boom")
+	})
+	assert.NoError(t, err)
+
+	spell, err := utils.InferStreamTool("spell", "spell the name", func(ctx context.Context, in *greetInput) (*schema.StreamReader[string], error) {
+		return schema.StreamReaderFromArray(strings.Split(in.Name, "")), nil
+	})
+	assert.NoError(t, err)
+
+	srv, err := NewServer(context.Background(), &ServerConfig{
+		Name: "test",
+		Tools: []tool.BaseTool{
+			greet,
+			fail,
+			utils.WrapToolWithErrorHandler(handled, func(ctx context.Context, err error) string {
+				return "handled: " + err.Error()
+			}),
+			spell,
+		},
+		ErrorHandler: func(ctx context.Context, err error) string {
+			return "error result: " + err.Error()
+		},
+	})
+	assert.NoError(t, err)
+	return srv
+}
+
+func testServerTools(t *testing.T, cli *Client) {
+	ctx := context.Background()
+
+	name, _ := cli.ServerInfo()
+	assert.Equal(t, "test", name)
+
+	tools, err := cli.GetTools(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tools, 4)
+
+	info, err := tools[0].Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "greet", info.Name)
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, "who to greet", js.Properties.Value("name").Description)
+
+	out, err := tools[0].InvokableRun(ctx, `{"name":"eino"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello eino", out)
+
+	result, err := cli.CallTool(ctx, "fail", `{"name":"eino"}`)
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "error result: ")
+	assert.Contains(t, result.Content[0].Text, "boom")
+
+	result, err = cli.CallTool(ctx, "handled", `{"name":"eino"}`)
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].Text, "handled: ")
+
+	out, err = tools[3].InvokableRun(ctx, `{"name":"eino"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "eino", out)
+
+	_, err = cli.CallTool(ctx, "unknown", "")
+	assert.ErrorContains(t, err, "unknown tool: unknown")
+}
+
+func TestServeStream(t *testing.T) {
+	srv := newTestServer(t)
+
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(context.Background(), serverIn, serverOut)
+		_ = serverOut.Close()
+	}()
+
+	cli, err := NewClient(context.Background(), clientIn, clientOut)
+	assert.NoError(t, err)
+
+	testServerTools(t, cli)
+
+	assert.NoError(t, cli.Close(context.Background()))
+	assert.NoError(t, <-done)
+}
+
+func TestServeHTTP(t *testing.T) {
+	srv := newTestServer(t)
+	hs := httptest.NewServer(srv)
+	defer hs.Close()
+
+	cli, err := NewHTTPClient(context.Background(), &HTTPConfig{URL: hs.URL})
+	assert.NoError(t, err)
+
+	testServerTools(t, cli)
+	assert.NoError(t, cli.Close(context.Background()))
+
+	// the session is terminated
+	_, err = cli.ListTools(context.Background())
+	assert.ErrorContains(t, err, "404")
+
+	resp, err := http.Post(hs.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestNewServer(t *testing.T) {
+	_, err := NewServer(context.Background(), &ServerConfig{Tools: []tool.BaseTool{&baseOnlyTool{}}})
+	assert.ErrorContains(t, err, "must be an InvokableTool or a StreamableTool")
+}
+
+type baseOnlyTool struct{}
+
+func (b *baseOnlyTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "base"}, nil
+}
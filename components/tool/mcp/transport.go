@@ -0,0 +1,336 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrClosed is returned when calling a closed client.
+var ErrClosed = errors.New("mcp connection closed")
+
+// transport sends JSON-RPC messages to the server.
+type transport interface {
+	// call sends the request and waits for the response of the same id.
+	call(ctx context.Context, req *jsonrpcMessage) (*jsonrpcMessage, error)
+	// notify sends the notification.
+	notify(ctx context.Context, msg *jsonrpcMessage) error
+	close() error
+}
+
+// streamTransport exchanges newline delimited JSON-RPC messages over a byte stream, e.g. the stdio of a sub process.
+type streamTransport struct {
+	w      io.Writer
+	closer func() error
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan *jsonrpcMessage
+	err     error
+	done    chan struct{}
+}
+
+func newStreamTransport(r io.Reader, w io.Writer, closer func() error) *streamTransport {
+	t := &streamTransport{
+		w:       w,
+		closer:  closer,
+		pending: make(map[string]chan *jsonrpcMessage),
+		done:    make(chan struct{}),
+	}
+	go t.readLoop(r)
+	return t
+}
+
+func (t *streamTransport) readLoop(r io.Reader) {
+	br := bufio.NewReader(r)
+	var err error
+	for {
+		var line []byte
+		line, err = br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			t.dispatch(line)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if err == io.EOF {
+		err = ErrClosed
+	}
+	t.mu.Lock()
+	t.err = err
+	t.pending = nil
+	t.mu.Unlock()
+	close(t.done)
+}
+
+func (t *streamTransport) dispatch(line []byte) {
+	msg := &jsonrpcMessage{}
+	if err := json.Unmarshal(line, msg); err != nil {
+		return
+	}
+
+	switch {
+	case msg.isResponse():
+		t.mu.Lock()
+		ch, ok := t.pending[string(msg.ID)]
+		delete(t.pending, string(msg.ID))
+		t.mu.Unlock()
+		if ok {
+			ch <- msg
+		}
+	case msg.isRequest():
+		// the client declares no capabilities, only ping is answered
+		resp := &jsonrpcMessage{JSONRPC: "2.0", ID: msg.ID}
+		if msg.Method == methodPing {
+			resp.Result = json.RawMessage("{}")
+		} else {
+			resp.Error = &jsonrpcError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+		}
+		_ = t.write(resp)
+	}
+}
+
+func (t *streamTransport) write(msg *jsonrpcMessage) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.w.Write(b)
+	return err
+}
+
+func (t *streamTransport) call(ctx context.Context, req *jsonrpcMessage) (*jsonrpcMessage, error) {
+	ch := make(chan *jsonrpcMessage, 1)
+	t.mu.Lock()
+	if t.pending == nil {
+		err := t.err
+		t.mu.Unlock()
+		return nil, err
+	}
+	t.pending[string(req.ID)] = ch
+	t.mu.Unlock()
+
+	if err := t.write(req); err != nil {
+		t.removePending(req.ID)
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-t.done:
+		return nil, t.err
+	case <-ctx.Done():
+		t.removePending(req.ID)
+		return nil, ctx.Err()
+	}
+}
+
+func (t *streamTransport) removePending(id json.RawMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending != nil {
+		delete(t.pending, string(id))
+	}
+}
+
+func (t *streamTransport) notify(_ context.Context, msg *jsonrpcMessage) error {
+	return t.write(msg)
+}
+
+func (t *streamTransport) close() error {
+	if t.closer == nil {
+		return nil
+	}
+	return t.closer()
+}
+
+// httpTransport sends each JSON-RPC message in a POST request to the endpoint of a streamable HTTP server,
+// the response is either a JSON object or a stream of server sent events.
+type httpTransport struct {
+	url     string
+	client  *http.Client
+	headers map[string]string
+
+	mu        sync.Mutex
+	sessionID string
+	version   string
+}
+
+func (t *httpTransport) call(ctx context.Context, req *jsonrpcMessage) (*jsonrpcMessage, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status code, status=%s, body=%s", resp.Status, body)
+	}
+	if sid := resp.Header.Get(headerSessionID); sid != "" {
+		t.mu.Lock()
+		t.sessionID = sid
+		t.mu.Unlock()
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		msg := &jsonrpcMessage{}
+		if err = json.NewDecoder(resp.Body).Decode(msg); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return msg, nil
+	}
+
+	var found *jsonrpcMessage
+	err = readEvents(resp.Body, func(data []byte) bool {
+		msg := &jsonrpcMessage{}
+		if json.Unmarshal(data, msg) != nil || !msg.isResponse() || !bytes.Equal(msg.ID, req.ID) {
+			return true
+		}
+		found = msg
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("event stream ended without response")
+	}
+	return found, nil
+}
+
+func (t *httpTransport) notify(ctx context.Context, msg *jsonrpcMessage) error {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code, status=%s", resp.Status)
+	}
+	return nil
+}
+
+func (t *httpTransport) post(ctx context.Context, msg *jsonrpcMessage) (*http.Response, error) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setHeaders(req)
+
+	return t.client.Do(req)
+}
+
+func (t *httpTransport) setHeaders(req *http.Request) {
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		req.Header.Set(headerSessionID, t.sessionID)
+	}
+	if t.version != "" {
+		req.Header.Set(headerProtocolVersion, t.version)
+	}
+}
+
+func (t *httpTransport) setVersion(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.version = version
+}
+
+// close terminates the session if the server has assigned one.
+func (t *httpTransport) close() error {
+	t.mu.Lock()
+	sid := t.sessionID
+	t.mu.Unlock()
+	if sid == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(req)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// readEvents reads the data of server sent events, until onData returns false or the stream ends.
+func readEvents(r io.Reader, onData func(data []byte) bool) error {
+	br := bufio.NewReader(r)
+	var data []byte
+	for {
+		line, err := br.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				if !onData(data) {
+					return nil
+				}
+				data = nil
+			}
+		case strings.HasPrefix(line, "data:"):
+			if len(data) > 0 {
+				data = append(data, '\n')
+			}
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")...)
+		}
+
+		if err == io.EOF {
+			if len(data) > 0 {
+				onData(data)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
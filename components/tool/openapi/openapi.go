@@ -0,0 +1,259 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package openapi generates tools from an OpenAPI 3 specification, one InvokableTool per operation.
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+// BodyParamName is the name of the tool parameter holding the JSON request body of the operation.
+const BodyParamName = "body"
+
+const defaultMaxResponseBytes = 1 << 20
+
+// ErrResponseTooLarge is returned when the response body exceeds Config.MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// Config is the config to generate tools from an OpenAPI 3 specification.
+type Config struct {
+	// Spec is the OpenAPI 3 document, in JSON or YAML. Either Spec or Doc is required.
+	Spec []byte
+	// Doc is the loaded OpenAPI 3 document, takes priority over Spec.
+	Doc *openapi3.T
+
+	// BaseURL is the url that operation paths are appended to.
+	// Optional. By default, the url of the first server in the document is used.
+	BaseURL string
+	// HTTPClient is the client used to call the operations.
+	// Optional. By default, http.DefaultClient is used.
+	HTTPClient *http.Client
+	// Headers are added to every request, e.g. {"Authorization": "Bearer xxx"},
+	// which replace the header parameters of the same names in the arguments of the model.
+	// Optional.
+	Headers map[string]string
+	// RequestModifier is called before every request is sent, to inject credentials computed at request time.
+	// Optional.
+	RequestModifier func(ctx context.Context, req *http.Request) error
+	// MaxResponseBytes is the maximum size of the response body, ErrResponseTooLarge is returned when exceeded.
+	// Optional. Default 1MB.
+	MaxResponseBytes int64
+	// OperationFilter selects the operations to generate tools for.
+	// Optional. By default, all operations are used.
+	OperationFilter func(method, path string, op *openapi3.Operation) bool
+}
+
+// NewTools loads the OpenAPI 3 document, and creates one InvokableTool per operation.
+// the tool name is the operationId, or '{method}_{path}' if operationId is absent,
+// the tool description is the summary and description of the operation.
+// path, query, header and cookie parameters become the tool parameters of the same names,
+// and the application/json request body becomes the parameter named BodyParamName.
+// the tool returns the response body as is, and an error if the response status is not 2xx.
+// e.g.
+//
+//	tools, err := openapi.NewTools(ctx, &openapi.Config{
+//		Spec:    specBytes,
+//		Headers: map[string]string{"Authorization": "Bearer " + token},
+//	})
+func NewTools(ctx context.Context, conf *Config) ([]tool.InvokableTool, error) {
+	if conf == nil {
+		return nil, errors.New("config is required")
+	}
+
+	doc := conf.Doc
+	if doc == nil {
+		if len(conf.Spec) == 0 {
+			return nil, errors.New("either Spec or Doc is required")
+		}
+		loader := openapi3.NewLoader()
+		loader.Context = ctx
+		var err error
+		doc, err = loader.LoadFromData(conf.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load openapi spec: %w", err)
+		}
+	}
+
+	baseURL := conf.BaseURL
+	if baseURL == "" {
+		if len(doc.Servers) == 0 || doc.Servers[0] == nil {
+			return nil, errors.New("base url is not set and no server is defined in spec")
+		}
+		baseURL = doc.Servers[0].URL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	client := conf.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	maxResponseBytes := conf.MaxResponseBytes
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = defaultMaxResponseBytes
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var tools []tool.InvokableTool
+	names := make(map[string]string)
+	for _, path := range paths {
+		item := doc.Paths[path]
+		ops := item.Operations()
+		methods := make([]string, 0, len(ops))
+		for method := range ops {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			op := ops[method]
+			if conf.OperationFilter != nil && !conf.OperationFilter(method, path, op) {
+				continue
+			}
+
+			t, err := newOperationTool(method, path, item.Parameters, op)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create tool for operation[%s %s]: %w", method, path, err)
+			}
+			if prev, ok := names[t.info.Name]; ok {
+				return nil, fmt.Errorf("duplicate tool name %s for operations [%s] and [%s %s]", t.info.Name, prev, method, path)
+			}
+			names[t.info.Name] = method + " " + path
+
+			t.baseURL = baseURL
+			t.client = client
+			t.headers = conf.Headers
+			t.requestModifier = conf.RequestModifier
+			t.maxResponseBytes = maxResponseBytes
+			tools = append(tools, t)
+		}
+	}
+
+	return tools, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+func toolName(method, path string, op *openapi3.Operation) string {
+	if op.OperationID != "" {
+		return op.OperationID
+	}
+	name := strings.ToLower(method) + "_" + strings.Trim(invalidNameChars.ReplaceAllString(path, "_"), "_")
+	return strings.TrimSuffix(name, "_")
+}
+
+func toolDesc(op *openapi3.Operation) string {
+	switch {
+	case op.Summary != "" && op.Description != "":
+		return op.Summary + "\n" + op.Description
+	case op.Summary != "":
+		return op.Summary
+	default:
+		return op.Description
+	}
+}
+
+func newOperationTool(method, path string, pathParams openapi3.Parameters, op *openapi3.Operation) (*operationTool, error) {
+	t := &operationTool{
+		method: method,
+		path:   path,
+	}
+
+	// operation parameters override path item parameters of the same name and location
+	params := make(map[string]*openapi3.Parameter)
+	var order []string
+	for _, ps := range []openapi3.Parameters{pathParams, op.Parameters} {
+		for _, ref := range ps {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			key := ref.Value.In + ":" + ref.Value.Name
+			if _, ok := params[key]; !ok {
+				order = append(order, key)
+			}
+			params[key] = ref.Value
+		}
+	}
+
+	root := newObjectSchema()
+	for _, key := range order {
+		p := params[key]
+		if p.Name == BodyParamName && op.RequestBody != nil {
+			return nil, fmt.Errorf("parameter name %s conflicts with request body", BodyParamName)
+		}
+		if _, ok := root.Properties.Get(p.Name); ok {
+			return nil, fmt.Errorf("parameter %s is defined in more than one location", p.Name)
+		}
+
+		var ps *openapi3.Schema
+		if p.Schema != nil {
+			ps = p.Schema.Value
+		}
+		js := openAPIV3ToJSONSchema(ps)
+		if js.Description == "" {
+			js.Description = p.Description
+		}
+		root.Properties.Set(p.Name, js)
+		if p.Required || p.In == openapi3.ParameterInPath {
+			root.Required = append(root.Required, p.Name)
+		}
+		t.params = append(t.params, p)
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		mt := op.RequestBody.Value.Content.Get("application/json")
+		if mt == nil {
+			return nil, errors.New("only application/json request body is supported")
+		}
+		var bs *openapi3.Schema
+		if mt.Schema != nil {
+			bs = mt.Schema.Value
+		}
+		js := openAPIV3ToJSONSchema(bs)
+		if js.Description == "" {
+			js.Description = op.RequestBody.Value.Description
+		}
+		root.Properties.Set(BodyParamName, js)
+		if op.RequestBody.Value.Required {
+			root.Required = append(root.Required, BodyParamName)
+		}
+		t.hasBody = true
+	}
+
+	t.info = &schema.ToolInfo{
+		Name:        toolName(method, path, op),
+		Desc:        toolDesc(op),
+		ParamsOneOf: schema.NewParamsOneOfByJSONSchema(root),
+	}
+
+	return t, nil
+}
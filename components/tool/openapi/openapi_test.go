@@ -0,0 +1,218 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+const petStoreSpec = `
+openapi: 3.0.0
+info:
+  title: pet store
+  version: 1.0.0
+servers:
+  - url: http://unused.example.com/v1
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        description: id of the pet
+        schema:
+          type: integer
+    get:
+      operationId: getPet
+      summary: get a pet
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+        - name: X-Trace
+          in: header
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+    put:
+      summary: update a pet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          minLength: 1
+        tag:
+          type: string
+          enum: [cat, dog]
+          nullable: true
+        parent:
+          $ref: '#/components/schemas/Pet'
+`
+
+func TestNewTools(t *testing.T) {
+	ctx := context.Background()
+
+	var lastReq *http.Request
+	var lastBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastReq = r
+		b, _ := io.ReadAll(r.Body)
+		lastBody = string(b)
+		switch r.URL.Path {
+		case "/v1/pets/1":
+			_, _ = w.Write([]byte(`{"name":"kitty"}`))
+		case "/v1/pets/2":
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+		}
+	}))
+	defer srv.Close()
+
+	tools, err := NewTools(ctx, &Config{
+		Spec:    []byte(petStoreSpec),
+		BaseURL: srv.URL + "/v1/",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		RequestModifier: func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("X-Signed", "yes")
+			return nil
+		},
+		MaxResponseBytes: 50,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, tools, 2)
+
+	getPet, putPet := tools[0], tools[1]
+
+	t.Run("tool info", func(t *testing.T) {
+		info, err := getPet.Info(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "getPet", info.Name)
+		assert.Equal(t, "get a pet", info.Desc)
+		js, err := info.ParamsOneOf.ToJSONSchema()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"petId"}, js.Required)
+		assert.Equal(t, "integer", js.Properties.Value("petId").Type)
+		assert.Equal(t, "id of the pet", js.Properties.Value("petId").Description)
+		assert.Equal(t, "string", js.Properties.Value("fields").Items.Type)
+		assert.NotNil(t, js.Properties.Value("X-Trace"))
+
+		info, err = putPet.Info(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "put_pets_petId", info.Name)
+		js, err = info.ParamsOneOf.ToJSONSchema()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"petId", BodyParamName}, js.Required)
+		body := js.Properties.Value(BodyParamName)
+		assert.Equal(t, []string{"name"}, body.Required)
+		assert.Equal(t, uint64(1), *body.Properties.Value("name").MinLength)
+		assert.Equal(t, []string{"string", "null"}, body.Properties.Value("tag").TypeEnhanced)
+		// recursion is cut
+		assert.Equal(t, "", body.Properties.Value("parent").Type)
+		assert.Nil(t, body.Properties.Value("parent").Properties)
+	})
+
+	t.Run("invoke", func(t *testing.T) {
+		out, err := getPet.InvokableRun(ctx, `{"petId":1,"fields":["name","tag"],"X-Trace":"abc"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"kitty"}`, out)
+		assert.Equal(t, http.MethodGet, lastReq.Method)
+		assert.Equal(t, []string{"name", "tag"}, lastReq.URL.Query()["fields"])
+		assert.Equal(t, "abc", lastReq.Header.Get("X-Trace"))
+		assert.Equal(t, "Bearer token", lastReq.Header.Get("Authorization"))
+		assert.Equal(t, "yes", lastReq.Header.Get("X-Signed"))
+
+		out, err = putPet.InvokableRun(ctx, `{"petId":1,"body":{"name":"kitty","tag":"cat"}}`)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"kitty"}`, out)
+		assert.Equal(t, http.MethodPut, lastReq.Method)
+		assert.Equal(t, "application/json", lastReq.Header.Get("Content-Type"))
+		assert.Equal(t, `{"name":"kitty","tag":"cat"}`, lastBody)
+	})
+
+	t.Run("configured headers can't be replaced", func(t *testing.T) {
+		tools, err := NewTools(ctx, &Config{
+			Spec:    []byte(petStoreSpec),
+			BaseURL: srv.URL + "/v1/",
+			Headers: map[string]string{"X-Trace": "configured"},
+		})
+		assert.NoError(t, err)
+		_, err = tools[0].InvokableRun(ctx, `{"petId":1,"X-Trace":"from model"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"configured"}, lastReq.Header.Values("X-Trace"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := getPet.InvokableRun(ctx, `{}`)
+		assert.ErrorContains(t, err, "missing required path parameter: petId")
+
+		_, err = getPet.InvokableRun(ctx, `{"petId":3}`)
+		assert.ErrorContains(t, err, "404")
+		assert.ErrorContains(t, err, "not found")
+
+		_, err = getPet.InvokableRun(ctx, `{"petId":2}`)
+		assert.True(t, errors.Is(err, ErrResponseTooLarge))
+	})
+}
+
+func TestNewToolsConfig(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewTools(ctx, &Config{})
+	assert.Error(t, err)
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(petStoreSpec))
+	assert.NoError(t, err)
+
+	tools, err := NewTools(ctx, &Config{
+		Doc: doc,
+		OperationFilter: func(method, path string, op *openapi3.Operation) bool {
+			return method == http.MethodGet
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, tools, 1)
+	assert.Equal(t, "http://unused.example.com/v1", tools[0].(*operationTool).baseURL)
+}
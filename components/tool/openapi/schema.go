@@ -0,0 +1,160 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func newObjectSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:       openapi3.TypeObject,
+		Properties: jsonschema.NewProperties(),
+	}
+}
+
+// openAPIV3ToJSONSchema converts an OpenAPI 3 schema to JSON schema.
+// recursive schemas are cut at the first repetition, leaving an unconstrained schema there.
+func openAPIV3ToJSONSchema(s *openapi3.Schema) *jsonschema.Schema {
+	return convertSchema(s, make(map[*openapi3.Schema]bool))
+}
+
+func convertSchema(s *openapi3.Schema, visiting map[*openapi3.Schema]bool) *jsonschema.Schema {
+	js := &jsonschema.Schema{}
+	if s == nil || visiting[s] {
+		return js
+	}
+	visiting[s] = true
+	defer delete(visiting, s)
+
+	js.Type = s.Type
+	if s.Nullable && s.Type != "" {
+		js.Type = ""
+		js.TypeEnhanced = []string{s.Type, "null"}
+	}
+	js.Title = s.Title
+	js.Description = s.Description
+	js.Format = s.Format
+	js.Default = s.Default
+	js.Deprecated = s.Deprecated
+	js.ReadOnly = s.ReadOnly
+	js.WriteOnly = s.WriteOnly
+	js.Pattern = s.Pattern
+	js.UniqueItems = s.UniqueItems
+	if s.Example != nil {
+		js.Examples = []any{s.Example}
+	}
+	if len(s.Enum) > 0 {
+		js.Enum = append([]any{}, s.Enum...)
+	}
+
+	if s.Min != nil {
+		if s.ExclusiveMin {
+			js.ExclusiveMinimum = toNumber(*s.Min)
+		} else {
+			js.Minimum = toNumber(*s.Min)
+		}
+	}
+	if s.Max != nil {
+		if s.ExclusiveMax {
+			js.ExclusiveMaximum = toNumber(*s.Max)
+		} else {
+			js.Maximum = toNumber(*s.Max)
+		}
+	}
+	if s.MultipleOf != nil {
+		js.MultipleOf = toNumber(*s.MultipleOf)
+	}
+
+	if s.MinLength > 0 {
+		js.MinLength = uint64Ptr(s.MinLength)
+	}
+	js.MaxLength = s.MaxLength
+	if s.MinItems > 0 {
+		js.MinItems = uint64Ptr(s.MinItems)
+	}
+	js.MaxItems = s.MaxItems
+	if s.MinProps > 0 {
+		js.MinProperties = uint64Ptr(s.MinProps)
+	}
+	js.MaxProperties = s.MaxProps
+
+	if s.Items != nil {
+		js.Items = convertSchema(s.Items.Value, visiting)
+	}
+
+	if len(s.Properties) > 0 {
+		js.Properties = jsonschema.NewProperties()
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			var ps *openapi3.Schema
+			if ref := s.Properties[name]; ref != nil {
+				ps = ref.Value
+			}
+			js.Properties.Set(name, convertSchema(ps, visiting))
+		}
+	}
+	if len(s.Required) > 0 {
+		js.Required = append([]string{}, s.Required...)
+	}
+	if s.AdditionalProperties.Schema != nil {
+		js.AdditionalProperties = convertSchema(s.AdditionalProperties.Schema.Value, visiting)
+	} else if s.AdditionalProperties.Has != nil && !*s.AdditionalProperties.Has {
+		js.AdditionalProperties = jsonschema.FalseSchema
+	}
+
+	js.OneOf = convertSchemaRefs(s.OneOf, visiting)
+	js.AnyOf = convertSchemaRefs(s.AnyOf, visiting)
+	js.AllOf = convertSchemaRefs(s.AllOf, visiting)
+	if s.Not != nil {
+		js.Not = convertSchema(s.Not.Value, visiting)
+	}
+
+	return js
+}
+
+func convertSchemaRefs(refs openapi3.SchemaRefs, visiting map[*openapi3.Schema]bool) []*jsonschema.Schema {
+	if len(refs) == 0 {
+		return nil
+	}
+	ret := make([]*jsonschema.Schema, 0, len(refs))
+	for _, ref := range refs {
+		var s *openapi3.Schema
+		if ref != nil {
+			s = ref.Value
+		}
+		ret = append(ret, convertSchema(s, visiting))
+	}
+	return ret
+}
+
+func toNumber(f float64) json.Number {
+	return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func uint64Ptr(u uint64) *uint64 {
+	return &u
+}
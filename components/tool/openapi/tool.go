@@ -0,0 +1,211 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+// operationTool calls one operation of the OpenAPI document.
+type operationTool struct {
+	info *schema.ToolInfo
+
+	method  string
+	path    string
+	params  []*openapi3.Parameter
+	hasBody bool
+
+	baseURL          string
+	client           *http.Client
+	headers          map[string]string
+	requestModifier  func(ctx context.Context, req *http.Request) error
+	maxResponseBytes int64
+}
+
+func (o *operationTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return o.info, nil
+}
+
+func (o *operationTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	args := make(map[string]json.RawMessage)
+	if strings.TrimSpace(argumentsInJSON) != "" {
+		if err := json.Unmarshal([]byte(argumentsInJSON), &args); err != nil {
+			return "", fmt.Errorf("[OpenAPITool] failed to unmarshal arguments in json, toolName=%s, err=%w", o.info.Name, err)
+		}
+	}
+
+	req, err := o.buildRequest(ctx, args)
+	if err != nil {
+		return "", fmt.Errorf("[OpenAPITool] failed to build request, toolName=%s, err=%w", o.info.Name, err)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("[OpenAPITool] failed to send request, toolName=%s, err=%w", o.info.Name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, o.maxResponseBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("[OpenAPITool] failed to read response, toolName=%s, err=%w", o.info.Name, err)
+	}
+	if int64(len(body)) > o.maxResponseBytes {
+		return "", fmt.Errorf("[OpenAPITool] toolName=%s, limit=%d bytes: %w", o.info.Name, o.maxResponseBytes, ErrResponseTooLarge)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("[OpenAPITool] unexpected status code, toolName=%s, status=%s, body=%s", o.info.Name, resp.Status, body)
+	}
+
+	return string(body), nil
+}
+
+func (o *operationTool) buildRequest(ctx context.Context, args map[string]json.RawMessage) (*http.Request, error) {
+	path := o.path
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+
+	for _, p := range o.params {
+		raw, ok := args[p.Name]
+		if !ok || string(raw) == "null" {
+			if p.Required || p.In == openapi3.ParameterInPath {
+				return nil, fmt.Errorf("missing required %s parameter: %s", p.In, p.Name)
+			}
+			continue
+		}
+
+		values, err := paramValues(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of parameter %s: %w", p.Name, err)
+		}
+
+		switch p.In {
+		case openapi3.ParameterInPath:
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(strings.Join(values, ",")))
+		case openapi3.ParameterInQuery:
+			if p.Explode != nil && !*p.Explode {
+				query.Set(p.Name, strings.Join(values, ","))
+				continue
+			}
+			for _, v := range values {
+				query.Add(p.Name, v)
+			}
+		case openapi3.ParameterInHeader:
+			header.Set(p.Name, strings.Join(values, ","))
+		case openapi3.ParameterInCookie:
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: strings.Join(values, ",")})
+		}
+	}
+
+	u := o.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	raw, hasBody := args[BodyParamName]
+	if o.hasBody && hasBody && string(raw) != "null" {
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(o.method), u, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	// the configured headers are set last, so that the arguments of the model can't replace the credentials
+	for k, v := range o.headers {
+		req.Header.Set(k, v)
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	if o.requestModifier != nil {
+		if err = o.requestModifier(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// paramValues formats a parameter value, arrays are split into elements, objects are kept in json.
+func paramValues(raw json.RawMessage) ([]string, error) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if arr, ok := v.([]any); ok {
+		ret := make([]string, 0, len(arr))
+		for _, elem := range arr {
+			s, err := formatValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, s)
+		}
+		return ret, nil
+	}
+
+	s, err := formatValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
+
+func formatValue(v any) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case bool:
+		return fmt.Sprint(t), nil
+	case nil:
+		return "", nil
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
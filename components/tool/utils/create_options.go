@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/eino-contrib/jsonschema"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
@@ -37,6 +38,9 @@ type toolOptions struct {
 	um UnmarshalArguments
 	m  MarshalOutput
 	sc SchemaCustomizerFn
+
+	outputSchema      *jsonschema.Schema
+	inferOutputSchema bool
 }
 
 // Option is the option func for the tool.
@@ -58,6 +62,22 @@ func WithMarshalOutput(m MarshalOutput) Option {
 	}
 }
 
+// WithOutputSchema sets the JSON schema of the tool output as ToolInfo.OutputSchema,
+// the output of the InvokableTool is validated against it, and an error is returned for the violations.
+func WithOutputSchema(js *jsonschema.Schema) Option {
+	return func(o *toolOptions) {
+		o.outputSchema = js
+	}
+}
+
+// WithInferredOutputSchema infers ToolInfo.OutputSchema from the output type of the function of InferTool and InferOptionableTool,
+// the same way as the parameters are inferred, see WithOutputSchema.
+func WithInferredOutputSchema() Option {
+	return func(o *toolOptions) {
+		o.inferOutputSchema = true
+	}
+}
+
 // Deprecated. For more information, see https://github.com/mrh997/eino/discussions/397.
 // SchemaCustomizerFn is the schema customizer function for inferring tool parameter from tagged go struct.
 // Within this function, end-user can parse custom go struct tags into corresponding openapi schema field.
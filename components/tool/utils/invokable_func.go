@@ -37,8 +37,9 @@ type OptionableInvokeFunc[T, D any] func(ctx context.Context, input T, opts ...t
 
 // InferTool creates an InvokableTool from a given function by inferring the ToolInfo from the function's request parameters.
 // End-user can pass a SchemaCustomizerFn in opts to customize the go struct tag parsing process, overriding default behavior.
+// if D is []schema.ChatMessagePart, the tool is also a tool.MultimodalTool, with which ToolsNode returns the parts to the model as MultiContent.
 func InferTool[T, D any](toolName, toolDesc string, i InvokeFunc[T, D], opts ...Option) (tool.InvokableTool, error) {
-	ti, err := goStruct2ToolInfo[T](toolName, toolDesc, opts...)
+	ti, err := inferToolInfo[T, D](toolName, toolDesc, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +49,7 @@ func InferTool[T, D any](toolName, toolDesc string, i InvokeFunc[T, D], opts ...
 
 // InferOptionableTool creates an InvokableTool from a given function by inferring the ToolInfo from the function's request parameters, with tool option.
 func InferOptionableTool[T, D any](toolName, toolDesc string, i OptionableInvokeFunc[T, D], opts ...Option) (tool.InvokableTool, error) {
-	ti, err := goStruct2ToolInfo[T](toolName, toolDesc, opts...)
+	ti, err := inferToolInfo[T, D](toolName, toolDesc, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -80,8 +81,40 @@ func goStruct2ToolInfo[T any](toolName, toolDesc string, opts ...Option) (*schem
 	}, nil
 }
 
+// inferToolInfo infers the ToolInfo of the function, with the output schema if required by the options.
+func inferToolInfo[T, D any](toolName, toolDesc string, opts ...Option) (*schema.ToolInfo, error) {
+	ti, err := goStruct2ToolInfo[T](toolName, toolDesc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	to := getToolOptions(opts...)
+	if to.outputSchema == nil && to.inferOutputSchema {
+		ti.OutputSchema, err = goStruct2JSONSchema[D]()
+		if err != nil {
+			return nil, fmt.Errorf("failed to infer output schema: %w", err)
+		}
+	}
+	return ti, nil
+}
+
 func goStruct2ParamsOneOf[T any](_ ...Option) (*schema.ParamsOneOf, error) {
+	rootSchema, err := goStruct2JSONSchema[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	paramsOneOf := schema.NewParamsOneOfByJSONSchema(rootSchema)
+
+	return paramsOneOf, nil
+}
+
+func goStruct2JSONSchema[T any]() (*jsonschema.Schema, error) {
 	s := jsonschema.Reflect(generic.NewInstance[T]())
+	if s.Ref == "" {
+		// not a struct, e.g. string or slice
+		return s, nil
+	}
 
 	rootName := strings.TrimPrefix(s.Ref, "#/$defs/")
 	rootSchema := s.Definitions[rootName]
@@ -89,10 +122,7 @@ func goStruct2ParamsOneOf[T any](_ ...Option) (*schema.ParamsOneOf, error) {
 		return nil, fmt.Errorf("jsonschema '%s' not found", rootName)
 	}
 
-	rootSchema = resolveRef(rootSchema, s.Definitions)
-	paramsOneOf := schema.NewParamsOneOfByJSONSchema(rootSchema)
-
-	return paramsOneOf, nil
+	return resolveRef(rootSchema, s.Definitions), nil
 }
 
 func resolveRef(s *jsonschema.Schema, defs jsonschema.Definitions) *jsonschema.Schema {
@@ -153,13 +183,22 @@ func NewTool[T, D any](desc *schema.ToolInfo, i InvokeFunc[T, D], opts ...Option
 
 func newOptionableTool[T, D any](desc *schema.ToolInfo, i OptionableInvokeFunc[T, D], opts ...Option) tool.InvokableTool {
 	to := getToolOptions(opts...)
+	if to.outputSchema != nil && desc != nil {
+		copied := *desc
+		copied.OutputSchema = to.outputSchema
+		desc = &copied
+	}
 
-	return &invokableTool[T, D]{
+	it := &invokableTool[T, D]{
 		info: desc,
 		um:   to.um,
 		m:    to.m,
 		Fn:   i,
 	}
+	if _, ok := any(generic.NewInstance[D]()).([]schema.ChatMessagePart); ok {
+		return &multimodalTool[T, D]{invokableTool: it}
+	}
+	return it
 }
 
 type invokableTool[T, D any] struct {
@@ -177,17 +216,61 @@ func (i *invokableTool[T, D]) Info(ctx context.Context) (*schema.ToolInfo, error
 
 // InvokableRun invokes the tool with the given arguments.
 func (i *invokableTool[T, D]) InvokableRun(ctx context.Context, arguments string, opts ...tool.Option) (output string, err error) {
+	resp, err := i.run(ctx, arguments, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	if i.m != nil {
+		output, err = i.m(ctx, resp)
+		if err != nil {
+			return "", fmt.Errorf("[LocalFunc] failed to marshal output, toolName=%s, err=%w", i.getToolName(), err)
+		}
+	} else {
+		output, err = marshalString(resp)
+		if err != nil {
+			return "", fmt.Errorf("[LocalFunc] failed to marshal output in json, toolName=%s, err=%w", i.getToolName(), err)
+		}
+	}
+
+	if i.info != nil && i.info.OutputSchema != nil {
+		var violations []*schema.SchemaViolation
+		if s, ok := any(resp).(string); ok && i.m == nil {
+			// the string returned is the output as is, even if it looks like json
+			violations = schema.ValidateJSONSchema(i.info.OutputSchema, s)
+		} else {
+			violations, err = i.info.ValidateOutput(output)
+			if err != nil {
+				return "", fmt.Errorf("[LocalFunc] failed to validate output, toolName=%s, err=%w", i.getToolName(), err)
+			}
+		}
+		if len(violations) > 0 {
+			return "", fmt.Errorf("[LocalFunc] output violates the output schema, toolName=%s, violations=%s", i.getToolName(), violationsString(violations))
+		}
+	}
+
+	return output, nil
+}
 
+func violationsString(violations []*schema.SchemaViolation) string {
+	ss := make([]string, 0, len(violations))
+	for _, v := range violations {
+		ss = append(ss, v.String())
+	}
+	return strings.Join(ss, "; ")
+}
+
+func (i *invokableTool[T, D]) run(ctx context.Context, arguments string, opts ...tool.Option) (resp D, err error) {
 	var inst T
 	if i.um != nil {
 		var val interface{}
 		val, err = i.um(ctx, arguments)
 		if err != nil {
-			return "", fmt.Errorf("[LocalFunc] failed to unmarshal arguments, toolName=%s, err=%w", i.getToolName(), err)
+			return resp, fmt.Errorf("[LocalFunc] failed to unmarshal arguments, toolName=%s, err=%w", i.getToolName(), err)
 		}
 		gt, ok := val.(T)
 		if !ok {
-			return "", fmt.Errorf("[LocalFunc] invalid type, toolName=%s, expected=%T, given=%T", i.getToolName(), inst, val)
+			return resp, fmt.Errorf("[LocalFunc] invalid type, toolName=%s, expected=%T, given=%T", i.getToolName(), inst, val)
 		}
 		inst = gt
 	} else {
@@ -195,28 +278,31 @@ func (i *invokableTool[T, D]) InvokableRun(ctx context.Context, arguments string
 
 		err = sonic.UnmarshalString(arguments, &inst)
 		if err != nil {
-			return "", fmt.Errorf("[LocalFunc] failed to unmarshal arguments in json, toolName=%s, err=%w", i.getToolName(), err)
+			return resp, fmt.Errorf("[LocalFunc] failed to unmarshal arguments in json, toolName=%s, err=%w", i.getToolName(), err)
 		}
 	}
 
-	resp, err := i.Fn(ctx, inst, opts...)
+	resp, err = i.Fn(ctx, inst, opts...)
 	if err != nil {
-		return "", fmt.Errorf("[LocalFunc] failed to invoke tool, toolName=%s, err=%w", i.getToolName(), err)
+		return resp, fmt.Errorf("[LocalFunc] failed to invoke tool, toolName=%s, err=%w", i.getToolName(), err)
 	}
 
-	if i.m != nil {
-		output, err = i.m(ctx, resp)
-		if err != nil {
-			return "", fmt.Errorf("[LocalFunc] failed to marshal output, toolName=%s, err=%w", i.getToolName(), err)
-		}
-	} else {
-		output, err = marshalString(resp)
-		if err != nil {
-			return "", fmt.Errorf("[LocalFunc] failed to marshal output in json, toolName=%s, err=%w", i.getToolName(), err)
-		}
-	}
+	return resp, nil
+}
 
-	return output, nil
+// multimodalTool is the invokableTool whose function returns []schema.ChatMessagePart.
+type multimodalTool[T, D any] struct {
+	*invokableTool[T, D]
+}
+
+// MultimodalRun invokes the tool with the given arguments, and returns the parts returned by the function.
+func (m *multimodalTool[T, D]) MultimodalRun(ctx context.Context, arguments string, opts ...tool.Option) ([]schema.ChatMessagePart, error) {
+	resp, err := m.run(ctx, arguments, opts...)
+	if err != nil {
+		return nil, err
+	}
+	parts, _ := any(resp).([]schema.ChatMessagePart)
+	return parts, nil
 }
 
 func (i *invokableTool[T, D]) GetType() string {
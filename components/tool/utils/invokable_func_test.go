@@ -247,6 +247,92 @@ func TestInferTool(t *testing.T) {
 	})
 }
 
+type chartInput struct {
+	Title string `json:"title"`
+}
+
+func TestInferMultimodalTool(t *testing.T) {
+	ctx := context.Background()
+	tl, err := InferTool("render_chart", "render a chart", func(ctx context.Context, in *chartInput) ([]schema.ChatMessagePart, error) {
+		return []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeText, Text: in.Title},
+			{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: "data:image/png;base64,AAAA"}},
+		}, nil
+	})
+	assert.NoError(t, err)
+
+	mt, ok := tl.(tool.MultimodalTool)
+	assert.True(t, ok)
+	parts, err := mt.MultimodalRun(ctx, `{"title":"sales"}`)
+	assert.NoError(t, err)
+	assert.Len(t, parts, 2)
+	assert.Equal(t, "sales", parts[0].Text)
+	assert.Equal(t, "data:image/png;base64,AAAA", parts[1].ImageURL.URL)
+
+	content, err := tl.InvokableRun(ctx, `{"title":"sales"}`)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"type":"text","text":"sales"},{"type":"image_url","image_url":{"url":"data:image/png;base64,AAAA"}}]`, content)
+
+	tl, err = InferTool("update_user_info", "full update user info", updateUserInfo)
+	assert.NoError(t, err)
+	_, ok = tl.(tool.MultimodalTool)
+	assert.False(t, ok)
+}
+
+func TestToolOutputSchema(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("inferred", func(t *testing.T) {
+		tl, err := InferTool("update_user_info", "full update user info", updateUserInfo, WithInferredOutputSchema())
+		assert.NoError(t, err)
+
+		info, err := tl.Info(ctx)
+		assert.NoError(t, err)
+		assert.NotNil(t, info.OutputSchema)
+		assert.Equal(t, "object", info.OutputSchema.Type)
+		assert.NotNil(t, info.OutputSchema.Properties.Value("code"))
+		assert.NotNil(t, info.OutputSchema.Properties.Value("msg"))
+
+		content, err := tl.InvokableRun(ctx, `{"name": "bruce lee"}`)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"code":200,"msg":"update bruce lee success"}`, content)
+
+		strTool, err := InferTool("echo", "echo", func(ctx context.Context, in *chartInput) (string, error) {
+			return in.Title, nil
+		}, WithInferredOutputSchema())
+		assert.NoError(t, err)
+		content, err = strTool.InvokableRun(ctx, `{"title":"plain text"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "plain text", content)
+
+		// string outputs looking like json are still strings
+		for _, title := range []string{`42`, `true`, `{"a":1}`} {
+			in, _ := json.Marshal(&chartInput{Title: title})
+			content, err = strTool.InvokableRun(ctx, string(in))
+			assert.NoError(t, err)
+			assert.Equal(t, title, content)
+		}
+	})
+
+	t.Run("violation", func(t *testing.T) {
+		js := &jsonschema.Schema{}
+		assert.NoError(t, json.Unmarshal([]byte(`{"type":"object","properties":{"code":{"type":"integer","maximum":299}},"required":["code"]}`), js))
+
+		tl, err := InferTool("update_user_info", "full update user info", func(ctx context.Context, input *User) (*UserResult, error) {
+			return &UserResult{Code: 500, Msg: "failed"}, nil
+		}, WithOutputSchema(js))
+		assert.NoError(t, err)
+
+		info, err := tl.Info(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, js, info.OutputSchema)
+
+		_, err = tl.InvokableRun(ctx, `{"name": "bruce lee"}`)
+		assert.ErrorContains(t, err, "output violates the output schema")
+		assert.ErrorContains(t, err, "/code: value 500 is greater than maximum 299")
+	})
+}
+
 func TestInferOptionableTool(t *testing.T) {
 	ctx := context.Background()
 
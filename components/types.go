@@ -17,6 +17,8 @@
 // components are the basic components supported by eino.
 package components
 
+import "context"
+
 // Typer get the type name of one component's implementation
 // if Typer exists, the full name of the component instance will be {Typer}{Component} by default
 // recommend using Camel Case Naming Style for Typer
@@ -47,6 +49,20 @@ func IsCallbacksEnabled(i any) bool {
 	return false
 }
 
+// Initializer is implemented by components holding resources, such as connections or clients, that must be set up before use.
+// When the component is used in a graph (directly, in a sub graph, or as a tool of ToolsNode),
+// Init is called once when the top level graph is compiled.
+type Initializer interface {
+	Init(ctx context.Context) error
+}
+
+// Closer is implemented by components holding resources that must be released.
+// When the component is used in a graph (directly, in a sub graph, or as a tool of ToolsNode),
+// Close is called when the compiled graph is closed, in the reverse order of initialization.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
 // Component the name of different kinds of components
 type Component string
 
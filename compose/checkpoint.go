@@ -122,6 +122,7 @@ type checkpoint struct {
 	RerunNodes     []string
 
 	ToolsNodeExecutedTools map[string] /*tool node key*/ map[string] /*tool call id*/ string
+	ToolsNodePendingJobs   map[string] /*tool node key*/ map[string] /*tool call id*/ string /*job id*/
 
 	SubGraphs map[string]*checkpoint
 }
@@ -104,6 +104,8 @@ func (g *Graph[I, O]) AddEdge(startNode, endNode string) (err error) {
 }
 
 // Compile take the raw graph and compile it into a form ready to be run.
+// node instances, sub graph nodes and ToolsNode tools implementing components.Initializer are initialized in the order they were added,
+// the returned Runnable implements components.Closer to release them, see CloseRunnable.
 // e.g.
 //
 //	graph, err := compose.NewGraph[string, string]()
@@ -150,5 +152,13 @@ func compileAnyGraph[I, O any](ctx context.Context, g AnyGraph, opts ...GraphCom
 		return nil, err
 	}
 
-	return rp, nil
+	resources := dedupResources(cr.resources)
+	if err = initResources(ctx, resources); err != nil {
+		return nil, err
+	}
+
+	return &closableRunnable[I, O]{
+		runnablePacker: rp,
+		resources:      resources,
+	}, nil
 }
@@ -56,6 +56,7 @@ func (g graphRunType) String() string {
 
 type graph struct {
 	nodes        map[string]*graphNode
+	nodeOrder    []string // node keys in the order they were added
 	controlEdges map[string][]string
 	dataEdges    map[string][]string
 	branches     map[string][]*GraphBranch
@@ -77,7 +78,12 @@ type graph struct {
 
 	fieldMappingRecords map[string][]*FieldMapping
 
-	buildError error
+	// buildError is the first error hit while building the graph, returned by compile,
+	// and buildErrors are all of them reported by Validate.
+	buildError  error
+	buildErrors []error
+	// failedNodes are the keys of the nodes failed to add, the edges and branches of which are skipped.
+	failedNodes map[string]bool
 
 	cmp component
 
@@ -131,6 +137,7 @@ func newGraph(cfg *newGraphConfig) *graph {
 		genericHelper:      cfg.gh,
 
 		fieldMappingRecords: make(map[string][]*FieldMapping),
+		failedNodes:         make(map[string]bool),
 
 		cmp: cfg.cmp,
 
@@ -160,18 +167,17 @@ func isWorkflow(cmp component) bool {
 var ErrGraphCompiled = errors.New("graph has been compiled, cannot be modified")
 
 func (g *graph) addNode(key string, node *graphNode, options *graphAddNodeOpts) (err error) {
-	if g.buildError != nil {
-		return g.buildError
-	}
-
 	if g.compiled {
 		return ErrGraphCompiled
 	}
 
 	defer func() {
-		if err != nil {
-			g.buildError = err
+		if err == nil {
+			delete(g.failedNodes, key)
+		} else if _, ok := g.nodes[key]; !ok {
+			g.failedNodes[key] = true
 		}
+		err = g.recordBuildError(err)
 	}()
 
 	if key == END || key == START {
@@ -185,7 +191,8 @@ func (g *graph) addNode(key string, node *graphNode, options *graphAddNodeOpts)
 	// check options
 	if options.needState {
 		if g.stateGenerator == nil {
-			return fmt.Errorf("node '%s' needs state but graph state is not enabled", key)
+			return newValidationIssueErr(ValidationIssueStateHandler, []string{key},
+				fmt.Errorf("node '%s' needs state but graph state is not enabled", key))
 		}
 	}
 
@@ -197,42 +204,129 @@ func (g *graph) addNode(key string, node *graphNode, options *graphAddNodeOpts)
 	// end: check options
 
 	// check pre- / post-handler type
-	if options.processor != nil {
-		if options.processor.statePreHandler != nil {
-			// check state type
-			if g.stateType != options.processor.preStateType {
-				return fmt.Errorf("node[%s]'s pre handler state type[%v] is different from graph[%v]", key, options.processor.preStateType, g.stateType)
-			}
-			// check input type
-			if node.inputType() == nil && options.processor.statePreHandler.outputType != reflect.TypeOf((*any)(nil)).Elem() {
-				return fmt.Errorf("passthrough node[%s]'s pre handler type isn't any", key)
-			} else if node.inputType() != nil && node.inputType() != options.processor.statePreHandler.outputType {
-				return fmt.Errorf("node[%s]'s pre handler type[%v] is different from its input type[%v]", key, options.processor.statePreHandler.outputType, node.inputType())
-			}
-		}
-		if options.processor.statePostHandler != nil {
-			// check state type
-			if g.stateType != options.processor.postStateType {
-				return fmt.Errorf("node[%s]'s post handler state type[%v] is different from graph[%v]", key, options.processor.postStateType, g.stateType)
-			}
-			// check input type
-			if node.outputType() == nil && options.processor.statePostHandler.inputType != reflect.TypeOf((*any)(nil)).Elem() {
-				return fmt.Errorf("passthrough node[%s]'s post handler type isn't any", key)
-			} else if node.outputType() != nil && node.outputType() != options.processor.statePostHandler.inputType {
-				return fmt.Errorf("node[%s]'s post handler type[%v] is different from its output type[%v]", key, options.processor.statePostHandler.inputType, node.outputType())
-			}
-		}
+	if err = checkStateHandlers(key, node, options.processor, g.stateType); err != nil {
+		return newValidationIssueErr(ValidationIssueStateHandler, []string{key}, err)
 	}
 
 	g.nodes[key] = node
+	g.nodeOrder = append(g.nodeOrder, key)
 
 	return nil
 }
 
-func (g *graph) addEdgeWithMappings(startNode, endNode string, noControl bool, noData bool, mappings ...*FieldMapping) (err error) {
-	if g.buildError != nil {
+func checkStateHandlers(key string, node *graphNode, processor *processorOpts, stateType reflect.Type) error {
+	if processor == nil {
+		return nil
+	}
+
+	if processor.statePreHandler != nil {
+		// check state type
+		if stateType != processor.preStateType {
+			return fmt.Errorf("node[%s]'s pre handler state type[%v] is different from graph[%v]", key, processor.preStateType, stateType)
+		}
+		// check input type
+		if node.inputType() == nil && processor.statePreHandler.outputType != reflect.TypeOf((*any)(nil)).Elem() {
+			return fmt.Errorf("passthrough node[%s]'s pre handler type isn't any", key)
+		} else if node.inputType() != nil && node.inputType() != processor.statePreHandler.outputType {
+			return fmt.Errorf("node[%s]'s pre handler type[%v] is different from its input type[%v]", key, processor.statePreHandler.outputType, node.inputType())
+		}
+	}
+	if processor.statePostHandler != nil {
+		// check state type
+		if stateType != processor.postStateType {
+			return fmt.Errorf("node[%s]'s post handler state type[%v] is different from graph[%v]", key, processor.postStateType, stateType)
+		}
+		// check input type
+		if node.outputType() == nil && processor.statePostHandler.inputType != reflect.TypeOf((*any)(nil)).Elem() {
+			return fmt.Errorf("passthrough node[%s]'s post handler type isn't any", key)
+		} else if node.outputType() != nil && node.outputType() != processor.statePostHandler.inputType {
+			return fmt.Errorf("node[%s]'s post handler type[%v] is different from its output type[%v]", key, processor.statePostHandler.inputType, node.outputType())
+		}
+	}
+
+	return nil
+}
+
+// recordBuildError keeps the error of a failed build step for Validate, and returns the error of the step,
+// or the first build error if the step succeeds on a graph already failed, which can't be compiled anyway.
+func (g *graph) recordBuildError(err error) error {
+	if err == nil {
 		return g.buildError
 	}
+	if g.buildError == nil {
+		g.buildError = err
+	}
+	g.buildErrors = append(g.buildErrors, err)
+	return err
+}
+
+// graphSnapshot is the state changed by adding edges and branches,
+// restored when the step fails, so that the following steps are checked against the graph without the failed one.
+type graphSnapshot struct {
+	controlEdges  map[string][]string
+	dataEdges     map[string][]string
+	branches      map[string][]*GraphBranch
+	startNodes    []string
+	endNodes      []string
+	toValidateMap map[string][]struct {
+		endNode  string
+		mappings []*FieldMapping
+	}
+	fieldMappingRecords map[string][]*FieldMapping
+	handlerOnEdges      map[string]map[string][]handlerPair
+	handlerPreBranch    map[string][][]handlerPair
+	// nodeTypes are the types of the nodes, which are inferred for passthrough nodes by the edges
+	nodeTypes map[string]composableRunnable
+}
+
+func copySliceMap[V any](m map[string][]V) map[string][]V {
+	ret := make(map[string][]V, len(m))
+	for k, v := range m {
+		ret[k] = append([]V(nil), v...)
+	}
+	return ret
+}
+
+func (g *graph) snapshot() *graphSnapshot {
+	s := &graphSnapshot{
+		controlEdges:        copySliceMap(g.controlEdges),
+		dataEdges:           copySliceMap(g.dataEdges),
+		branches:            copySliceMap(g.branches),
+		startNodes:          append([]string(nil), g.startNodes...),
+		endNodes:            append([]string(nil), g.endNodes...),
+		toValidateMap:       copySliceMap(g.toValidateMap),
+		fieldMappingRecords: copySliceMap(g.fieldMappingRecords),
+		handlerOnEdges:      make(map[string]map[string][]handlerPair, len(g.handlerOnEdges)),
+		handlerPreBranch:    copySliceMap(g.handlerPreBranch),
+		nodeTypes:           make(map[string]composableRunnable, len(g.nodes)),
+	}
+	for k, v := range g.handlerOnEdges {
+		s.handlerOnEdges[k] = copySliceMap(v)
+	}
+	for k, n := range g.nodes {
+		if n.cr != nil {
+			s.nodeTypes[k] = *n.cr
+		}
+	}
+	return s
+}
+
+func (g *graph) restore(s *graphSnapshot) {
+	g.controlEdges = s.controlEdges
+	g.dataEdges = s.dataEdges
+	g.branches = s.branches
+	g.startNodes = s.startNodes
+	g.endNodes = s.endNodes
+	g.toValidateMap = s.toValidateMap
+	g.fieldMappingRecords = s.fieldMappingRecords
+	g.handlerOnEdges = s.handlerOnEdges
+	g.handlerPreBranch = s.handlerPreBranch
+	for k, cr := range s.nodeTypes {
+		*g.nodes[k].cr = cr
+	}
+}
+
+func (g *graph) addEdgeWithMappings(startNode, endNode string, noControl bool, noData bool, mappings ...*FieldMapping) (err error) {
 	if g.compiled {
 		return ErrGraphCompiled
 	}
@@ -241,10 +335,16 @@ func (g *graph) addEdgeWithMappings(startNode, endNode string, noControl bool, n
 		return fmt.Errorf("edge[%s]-[%s] cannot be both noDirectDependency and noDataFlow", startNode, endNode)
 	}
 
+	if g.failedNodes[startNode] || g.failedNodes[endNode] {
+		return g.buildError
+	}
+
+	snapshot := g.snapshot()
 	defer func() {
 		if err != nil {
-			g.buildError = err
+			g.restore(snapshot)
 		}
+		err = g.recordBuildError(err)
 	}()
 	if startNode == END {
 		return errors.New("END cannot be a start node")
@@ -433,18 +533,25 @@ func (g *graph) AddBranch(startNode string, branch *GraphBranch) (err error) {
 }
 
 func (g *graph) addBranch(startNode string, branch *GraphBranch, skipData bool) (err error) {
-	if g.buildError != nil {
-		return g.buildError
-	}
-
 	if g.compiled {
 		return ErrGraphCompiled
 	}
 
+	if g.failedNodes[startNode] {
+		return g.buildError
+	}
+	for endNode := range branch.endNodes {
+		if g.failedNodes[endNode] {
+			return g.buildError
+		}
+	}
+
+	snapshot := g.snapshot()
 	defer func() {
 		if err != nil {
-			g.buildError = err
+			g.restore(snapshot)
 		}
+		err = g.recordBuildError(err)
 	}()
 
 	if startNode == END {
@@ -577,7 +684,7 @@ func (g *graph) updateToValidateMap() error {
 					// field mapping check
 					checker, uncheckedSourcePaths, err := validateFieldMapping(g.getNodeOutputType(startNode), g.getNodeInputType(endNode.endNode), endNode.mappings)
 					if err != nil {
-						return err
+						return newValidationIssueErr(ValidationIssueFieldMapping, []string{startNode, endNode.endNode}, err)
 					}
 
 					g.handlerOnEdges[startNode][endNode.endNode] = append(g.handlerOnEdges[startNode][endNode.endNode], handlerPair{
@@ -691,6 +798,14 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		g.handlerPreNode[key] = append(g.handlerPreNode[key], g.getNodeGenericHelper(key).inputFieldMappingConverter)
 	}
 
+	if opt != nil && opt.checkTemplateVariables {
+		// the parallel nodes of a chain are triggered together, so their outputs are merged as in dag
+		report := &ValidationReport{Issues: g.templateVariablesIssues(opt.graphInputKeys, runType == runTypeDAG || isChain(g.cmp))}
+		if err := report.Err(); err != nil {
+			return nil, err
+		}
+	}
+
 	key2SubGraphs := g.beforeChildGraphsCompile(opt)
 	chanSubscribeTo := make(map[string]*chanCall)
 	for name, node := range g.nodes {
@@ -847,7 +962,10 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 
 	g.onCompileFinish(ctx, opt, key2SubGraphs)
 
-	return r.toComposableRunnable(), nil
+	cr := r.toComposableRunnable()
+	cr.resources = g.collectResources()
+
+	return cr, nil
 }
 
 func getSuccessors(c *chanCall) []string {
@@ -1066,14 +1184,14 @@ func validateDAG(chanSubscribeTo map[string]*chanCall, controlPredecessors map[s
 		}
 	}
 	if len(loopStarts) > 0 {
-		return fmt.Errorf("%w: %s", DAGInvalidLoopErr, formatLoops(findLoops(loopStarts, chanSubscribeTo)))
+		return fmt.Errorf("%w: %s", DAGInvalidLoopErr, formatLoops(findLoops(loopStarts, getControlSuccessors(chanSubscribeTo))))
 	}
 	return nil
 }
 
 var DAGInvalidLoopErr = errors.New("DAG is invalid, has loop")
 
-func findLoops(startNodes []string, chanCalls map[string]*chanCall) [][]string {
+func getControlSuccessors(chanCalls map[string]*chanCall) map[string][]string {
 	controlSuccessors := map[string][]string{}
 	for node, ch := range chanCalls {
 		controlSuccessors[node] = append(controlSuccessors[node], ch.controls...)
@@ -1083,7 +1201,10 @@ func findLoops(startNodes []string, chanCalls map[string]*chanCall) [][]string {
 			}
 		}
 	}
+	return controlSuccessors
+}
 
+func findLoops(startNodes []string, controlSuccessors map[string][]string) [][]string {
 	visited := map[string]bool{}
 	var dfs func(path []string) [][]string
 	dfs = func(path []string) [][]string {
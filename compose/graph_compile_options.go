@@ -33,6 +33,9 @@ type graphCompileOptions struct {
 	eagerDisabled bool
 
 	mergeConfigs map[string]FanInMergeConfig
+
+	checkTemplateVariables bool
+	graphInputKeys         []string
 }
 
 func newGraphCompileOptions(opts ...GraphCompileOption) *graphCompileOptions {
@@ -124,6 +127,27 @@ func WithFanInMergeConfig(confs map[string]FanInMergeConfig) GraphCompileOption
 	}
 }
 
+// WithTemplateVariablesCheck verifies when compiling that the variables required by the ChatTemplate nodes
+// are provided by their predecessors, instead of failing when formatting, see prompt.InputVariablesDeclarer.
+// the keys provided by a predecessor are known if it's added with WithOutputKey, or mapped to the fields of the node in Workflow.
+// the keys of all the predecessors are checked together in Workflow, Chain and the graph of NodeTriggerMode(AllPredecessor), as they are merged,
+// otherwise each predecessor is checked separately, as the node may be triggered by any one of them.
+// inputKeys are the keys of the graph input, which are unknown if not set.
+// the nodes with any predecessor providing unknown keys, or added with WithInputKey or WithStatePreHandler, are not checked.
+// Compile fails with a *ValidationReport of all the issues found.
+// e.g.
+//
+//	graph.AddLambdaNode("retrieve", retrieveLambda, compose.WithOutputKey("documents"))
+//	graph.AddChatTemplateNode("prompt", prompt.FromMessages(schema.FString, schema.UserMessage("{documents} {query}")))
+//	// fails as query is not provided by retrieve
+//	r, err := graph.Compile(ctx, compose.WithTemplateVariablesCheck())
+func WithTemplateVariablesCheck(inputKeys ...string) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.checkTemplateVariables = true
+		o.graphInputKeys = inputKeys
+	}
+}
+
 // InitGraphCompileCallbacks set global graph compile callbacks,
 // which ONLY will be added to top level graph compile options
 func InitGraphCompileCallbacks(cbs []GraphCompileCallback) {
@@ -190,7 +190,7 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 
 		ctx, input = onGraphStart(ctx, input, isStream)
 		haveOnStart = true
-		nextTasks, err = r.restoreTasks(ctx, cp.Inputs, cp.SkipPreHandler, cp.ToolsNodeExecutedTools, cp.RerunNodes, isStream, optMap) // should restore after set state to context
+		nextTasks, err = r.restoreTasks(ctx, cp.Inputs, cp.SkipPreHandler, cp.ToolsNodeExecutedTools, cp.ToolsNodePendingJobs, cp.RerunNodes, isStream, optMap) // should restore after set state to context
 		if err != nil {
 			return nil, newGraphRunError(fmt.Errorf("restore tasks fail: %w", err))
 		}
@@ -227,7 +227,7 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 			ctx, input = onGraphStart(ctx, input, isStream)
 			haveOnStart = true
 			// resume graph
-			nextTasks, err = r.restoreTasks(ctx, cp.Inputs, cp.SkipPreHandler, cp.ToolsNodeExecutedTools, cp.RerunNodes, isStream, optMap)
+			nextTasks, err = r.restoreTasks(ctx, cp.Inputs, cp.SkipPreHandler, cp.ToolsNodeExecutedTools, cp.ToolsNodePendingJobs, cp.RerunNodes, isStream, optMap)
 			if err != nil {
 				return nil, newGraphRunError(fmt.Errorf("restore tasks fail: %w", err))
 			}
@@ -385,6 +385,7 @@ func newInterruptTempInfo() *interruptTempInfo {
 		subGraphInterrupts:     map[string]*subGraphInterruptError{},
 		interruptRerunExtra:    map[string]any{},
 		interruptExecutedTools: make(map[string]map[string]string),
+		interruptPendingJobs:   make(map[string]map[string]string),
 	}
 }
 
@@ -395,6 +396,7 @@ type interruptTempInfo struct {
 	interruptAfterNodes    []string
 	interruptRerunExtra    map[string]any
 	interruptExecutedTools map[string]map[string]string
+	interruptPendingJobs   map[string]map[string]string
 }
 
 func (r *runner) resolveInterruptCompletedTasks(tempInfo *interruptTempInfo, completedTasks []*task) (err error) {
@@ -414,6 +416,9 @@ func (r *runner) resolveInterruptCompletedTasks(tempInfo *interruptTempInfo, com
 					if completedTasks[i].call.action.meta.component == ComponentOfToolsNode {
 						if e, ok := extra.(*ToolsInterruptAndRerunExtra); ok {
 							tempInfo.interruptExecutedTools[completedTasks[i].nodeKey] = e.ExecutedTools
+							if len(e.PendingJobs) > 0 {
+								tempInfo.interruptPendingJobs[completedTasks[i].nodeKey] = e.PendingJobs
+							}
 						}
 					}
 				}
@@ -541,6 +546,7 @@ func (r *runner) handleInterruptWithSubGraphAndRerunNodes(
 		Inputs:                 make(map[string]any),
 		SkipPreHandler:         skipPreHandler,
 		ToolsNodeExecutedTools: tempInfo.interruptExecutedTools,
+		ToolsNodePendingJobs:   tempInfo.interruptPendingJobs,
 		SubGraphs:              make(map[string]*checkpoint),
 	}
 	if r.runCtx != nil {
@@ -656,6 +662,7 @@ func (r *runner) restoreTasks(
 	inputs map[string]any,
 	skipPreHandler map[string]bool,
 	toolNodeExecutedTools map[string]map[string]string,
+	toolNodePendingJobs map[string]map[string]string,
 	rerunNodes []string,
 	isStream bool,
 	optMap map[string][]any) ([]*task, error) {
@@ -696,6 +703,9 @@ func (r *runner) restoreTasks(
 		if executedTools, ok := toolNodeExecutedTools[key]; ok {
 			newTask.option = append(newTask.option, withExecutedTools(executedTools))
 		}
+		if pendingJobs, ok := toolNodePendingJobs[key]; ok {
+			newTask.option = append(newTask.option, withPendingJobs(pendingJobs))
+		}
 
 		ret = append(ret, newTask)
 	}
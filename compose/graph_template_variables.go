@@ -0,0 +1,139 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mrh997/eino/components/prompt"
+)
+
+// templateVariablesIssues returns the issues of the ChatTemplate nodes implementing prompt.InputVariablesDeclarer,
+// whose required variables are not provided by their data predecessors, see WithTemplateVariablesCheck.
+// the keys of the predecessors are merged if mergePredecessors, otherwise each predecessor must provide all the variables,
+// as a node of AnyPredecessor may be triggered by any one of them.
+func (g *graph) templateVariablesIssues(inputKeys []string, mergePredecessors bool) []*ValidationIssue {
+	predecessors := make(map[string][]string)
+	for start, ends := range g.dataEdges {
+		for _, end := range ends {
+			predecessors[end] = append(predecessors[end], start)
+		}
+	}
+	for start, branches := range g.branches {
+		for _, branch := range branches {
+			if branch.noDataFlow {
+				continue
+			}
+			for end := range branch.endNodes {
+				predecessors[end] = append(predecessors[end], start)
+			}
+		}
+	}
+
+	var issues []*ValidationIssue
+	for _, key := range sortedKeys(g.nodes) {
+		gn := g.nodes[key]
+		declarer, ok := gn.instance.(prompt.InputVariablesDeclarer)
+		if !ok || len(gn.nodeInfo.inputKey) > 0 || gn.nodeInfo.preProcessor != nil {
+			continue
+		}
+
+		vars, err := declarer.InputVariables()
+		if err != nil {
+			issues = append(issues, &ValidationIssue{
+				Kind:    ValidationIssueTemplateVariables,
+				Nodes:   []string{key},
+				Message: fmt.Sprintf("failed to extract variables of chat template node[%s]: %v", key, err),
+			})
+			continue
+		}
+
+		preds := predecessors[key]
+		sort.Strings(preds)
+		groups := [][]string{preds}
+		if !mergePredecessors {
+			groups = make([][]string, 0, len(preds))
+			for _, pred := range preds {
+				groups = append(groups, []string{pred})
+			}
+		}
+		for _, group := range groups {
+			provided, known := g.providedKeys(key, group, inputKeys)
+			if !known {
+				continue
+			}
+			var missing []string
+			for _, v := range vars {
+				if !v.Optional && !provided[v.Name] {
+					missing = append(missing, v.Name)
+				}
+			}
+			if len(missing) > 0 {
+				issues = append(issues, &ValidationIssue{
+					Kind:  ValidationIssueTemplateVariables,
+					Nodes: append([]string{key}, group...),
+					Message: fmt.Sprintf("chat template node[%s] requires variables %v, which are not provided by its predecessors %v",
+						key, missing, group),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// providedKeys returns the keys of the map input provided by the predecessors of the node,
+// known is false if any predecessor provides unknown keys.
+func (g *graph) providedKeys(node string, preds []string, inputKeys []string) (provided map[string]bool, known bool) {
+	if len(preds) == 0 {
+		return nil, false
+	}
+
+	provided = make(map[string]bool)
+	for _, pred := range preds {
+		mapped := false
+		for _, mapping := range g.fieldMappingRecords[node] {
+			if mapping.fromNodeKey != pred {
+				continue
+			}
+			mapped = true
+			path := mapping.targetPath()
+			if len(path) == 0 {
+				return nil, false
+			}
+			provided[path[0]] = true
+		}
+		if mapped {
+			continue
+		}
+
+		switch {
+		case pred == START:
+			if inputKeys == nil {
+				return nil, false
+			}
+			for _, k := range inputKeys {
+				provided[k] = true
+			}
+		case g.nodes[pred] != nil && len(g.nodes[pred].nodeInfo.outputKey) > 0:
+			provided[g.nodes[pred].nodeInfo.outputKey] = true
+		default:
+			return nil, false
+		}
+	}
+	return provided, true
+}
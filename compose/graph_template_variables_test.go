@@ -0,0 +1,110 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/prompt"
+	"github.com/mrh997/eino/schema"
+)
+
+func TestTemplateVariablesCheck(t *testing.T) {
+	ctx := context.Background()
+	tpl := prompt.FromMessages(schema.FString,
+		schema.SystemMessage("context: {documents}"),
+		schema.MessagesPlaceholder("history", true),
+		schema.UserMessage("{query}"))
+	docsLambda := InvokableLambda(func(ctx context.Context, in map[string]any) (string, error) { return "docs", nil })
+
+	newGraph := func(t *testing.T, startToPrompt bool) *Graph[map[string]any, []*schema.Message] {
+		g := NewGraph[map[string]any, []*schema.Message]()
+		assert.NoError(t, g.AddLambdaNode("retrieve", docsLambda, WithOutputKey("documents")))
+		assert.NoError(t, g.AddChatTemplateNode("prompt", tpl))
+		assert.NoError(t, g.AddEdge(START, "retrieve"))
+		assert.NoError(t, g.AddEdge("retrieve", "prompt"))
+		if startToPrompt {
+			assert.NoError(t, g.AddEdge(START, "prompt"))
+		}
+		assert.NoError(t, g.AddEdge("prompt", END))
+		return g
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		g := newGraph(t, false)
+		_, err := g.Compile(ctx, WithTemplateVariablesCheck())
+		assert.ErrorContains(t, err, "chat template node[prompt] requires variables [query]")
+
+		issues := g.Validate(WithTemplateVariablesCheck()).IssuesOf(ValidationIssueTemplateVariables)
+		assert.Len(t, issues, 1)
+		assert.Equal(t, []string{"prompt", "retrieve"}, issues[0].Nodes)
+
+		// not checked by default
+		_, err = newGraph(t, false).Compile(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("input keys", func(t *testing.T) {
+		// the keys of START are unknown
+		_, err := newGraph(t, true).Compile(ctx, WithTemplateVariablesCheck(), WithNodeTriggerMode(AllPredecessor))
+		assert.NoError(t, err)
+
+		_, err = newGraph(t, true).Compile(ctx, WithTemplateVariablesCheck("question"), WithNodeTriggerMode(AllPredecessor))
+		assert.ErrorContains(t, err, "requires variables [query]")
+
+		// in pregel mode, the node may be triggered by either predecessor alone
+		g := newGraph(t, true)
+		_, err = g.Compile(ctx, WithTemplateVariablesCheck("query"))
+		assert.ErrorContains(t, err, "graph validation found 2 issue(s)")
+		assert.ErrorContains(t, err, "requires variables [query], which are not provided by its predecessors [retrieve]")
+		assert.ErrorContains(t, err, "requires variables [documents]")
+		issues := g.Validate(WithTemplateVariablesCheck("query")).IssuesOf(ValidationIssueTemplateVariables)
+		assert.Len(t, issues, 2)
+		assert.Equal(t, []string{"prompt", "retrieve"}, issues[0].Nodes)
+		assert.Equal(t, []string{"prompt", START}, issues[1].Nodes)
+		assert.Contains(t, issues[1].Message, "requires variables [documents]")
+
+		r, err := newGraph(t, true).Compile(ctx, WithTemplateVariablesCheck("query"), WithNodeTriggerMode(AllPredecessor))
+		assert.NoError(t, err)
+		msgs, err := r.Invoke(ctx, map[string]any{"query": "what"})
+		assert.NoError(t, err)
+		assert.Equal(t, "what", msgs[1].Content)
+	})
+
+	t.Run("workflow", func(t *testing.T) {
+		wf := NewWorkflow[map[string]any, []*schema.Message]()
+		wf.AddLambdaNode("retrieve", docsLambda).AddInput(START)
+		wf.AddChatTemplateNode("prompt", tpl).
+			AddInput("retrieve", ToField("documents")).
+			AddInput(START, MapFields("question", "querry"))
+		wf.End().AddInput("prompt")
+		_, err := wf.Compile(ctx, WithTemplateVariablesCheck())
+		assert.ErrorContains(t, err, "requires variables [query]")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		g := NewGraph[map[string]any, []*schema.Message]()
+		assert.NoError(t, g.AddChatTemplateNode("prompt", prompt.FromMessages(schema.FString, schema.UserMessage("{query"))))
+		assert.NoError(t, g.AddEdge(START, "prompt"))
+		assert.NoError(t, g.AddEdge("prompt", END))
+		_, err := g.Compile(ctx, WithTemplateVariablesCheck())
+		assert.ErrorContains(t, err, "failed to extract variables of chat template node[prompt]")
+	})
+}
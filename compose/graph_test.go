@@ -1919,7 +1919,7 @@ func TestFindLoops(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			loops := findLoops(tt.startNodes, tt.chanCalls)
+			loops := findLoops(tt.startNodes, getControlSuccessors(tt.chanCalls))
 
 			assert.Equal(t, len(tt.expected), len(loops))
 
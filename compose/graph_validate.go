@@ -0,0 +1,405 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationIssueKind is the category of a problem found by Validate.
+type ValidationIssueKind string
+
+const (
+	// ValidationIssueBuild is an error returned while adding nodes, edges or branches, or a compile-time check
+	// that does not belong to any other category, e.g. mismatched edge types or missing START / END edges.
+	ValidationIssueBuild ValidationIssueKind = "Build"
+	// ValidationIssueUnreachableNode is a node that can never be triggered from START.
+	ValidationIssueUnreachableNode ValidationIssueKind = "UnreachableNode"
+	// ValidationIssueDeadEndNode is a node from which END can never be reached.
+	ValidationIssueDeadEndNode ValidationIssueKind = "DeadEndNode"
+	// ValidationIssueDeadEndBranch is a branch that may choose an end node from which END can never be reached.
+	ValidationIssueDeadEndBranch ValidationIssueKind = "DeadEndBranch"
+	// ValidationIssueFieldMapping is a field mapping referring to unknown or incompatible fields, or mapping to the same field twice.
+	ValidationIssueFieldMapping ValidationIssueKind = "FieldMapping"
+	// ValidationIssueStateHandler is a state handler whose state type does not match WithGenLocalState, or a handler used without state.
+	ValidationIssueStateHandler ValidationIssueKind = "StateHandler"
+	// ValidationIssueInterruptNode is a node name passed to WithInterruptBeforeNodes / WithInterruptAfterNodes that does not exist.
+	ValidationIssueInterruptNode ValidationIssueKind = "InterruptNode"
+	// ValidationIssueUnguardedLoop is a loop in a Pregel graph compiled without WithMaxRunSteps.
+	ValidationIssueUnguardedLoop ValidationIssueKind = "UnguardedLoop"
+	// ValidationIssueIllegalLoop is a loop in a graph running in DAG mode, i.e. NodeTriggerMode(AllPredecessor).
+	ValidationIssueIllegalLoop ValidationIssueKind = "IllegalLoop"
+	// ValidationIssueTemplateVariables is a ChatTemplate node requiring variables not provided by its predecessors,
+	// only checked with WithTemplateVariablesCheck.
+	ValidationIssueTemplateVariables ValidationIssueKind = "TemplateVariables"
+)
+
+// ValidationIssue is a single problem found by Validate.
+type ValidationIssue struct {
+	Kind ValidationIssueKind
+	// SubGraphPath is the node keys leading from the validated graph to the sub graph where the issue is found.
+	// empty if the issue belongs to the validated graph itself.
+	SubGraphPath []string
+	// Nodes are the node keys involved in the issue, may be empty.
+	Nodes   []string
+	Message string
+}
+
+// String returns a one-line description of the issue.
+func (i *ValidationIssue) String() string {
+	sb := strings.Builder{}
+	sb.WriteString("[")
+	sb.WriteString(string(i.Kind))
+	sb.WriteString("]")
+	if len(i.SubGraphPath) > 0 {
+		sb.WriteString(" sub graph ")
+		sb.WriteString(strings.Join(i.SubGraphPath, "/"))
+		sb.WriteString(":")
+	}
+	sb.WriteString(" ")
+	sb.WriteString(i.Message)
+	return sb.String()
+}
+
+// ValidationReport contains all the issues found by Validate.
+type ValidationReport struct {
+	Issues []*ValidationIssue
+}
+
+// HasIssues reports whether any issue has been found.
+func (r *ValidationReport) HasIssues() bool {
+	return r != nil && len(r.Issues) > 0
+}
+
+// IssuesOf returns the issues of the given kind.
+func (r *ValidationReport) IssuesOf(kind ValidationIssueKind) []*ValidationIssue {
+	if r == nil {
+		return nil
+	}
+	var ret []*ValidationIssue
+	for _, issue := range r.Issues {
+		if issue.Kind == kind {
+			ret = append(ret, issue)
+		}
+	}
+	return ret
+}
+
+// Err returns nil if there is no issue, otherwise an error listing all the issues.
+// e.g.
+//
+//	if err := graph.Validate().Err(); err != nil {
+//		t.Fatal(err)
+//	}
+func (r *ValidationReport) Err() error {
+	if !r.HasIssues() {
+		return nil
+	}
+	return r
+}
+
+// Error implements error.
+func (r *ValidationReport) Error() string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("graph validation found %d issue(s):", len(r.Issues)))
+	for _, issue := range r.Issues {
+		sb.WriteString("\n\t")
+		sb.WriteString(issue.String())
+	}
+	return sb.String()
+}
+
+// validationIssueErr marks an error returned while building the graph with its issue kind, so that Validate can classify it.
+type validationIssueErr struct {
+	kind  ValidationIssueKind
+	nodes []string
+	err   error
+}
+
+func newValidationIssueErr(kind ValidationIssueKind, nodes []string, err error) error {
+	return &validationIssueErr{kind: kind, nodes: nodes, err: err}
+}
+
+func (e *validationIssueErr) Error() string {
+	return e.err.Error()
+}
+
+func (e *validationIssueErr) Unwrap() error {
+	return e.err
+}
+
+func issueFromError(err error) *ValidationIssue {
+	var ve *validationIssueErr
+	if errors.As(err, &ve) {
+		return &ValidationIssue{Kind: ve.kind, Nodes: ve.nodes, Message: err.Error()}
+	}
+	return &ValidationIssue{Kind: ValidationIssueBuild, Message: err.Error()}
+}
+
+// validatable is implemented by the AnyGraph types that support Validate.
+type validatable interface {
+	validate(opt *graphCompileOptions) []*ValidationIssue
+}
+
+// Validate checks the graph without compiling it, and reports all the problems found at once,
+// instead of stopping at the first one like Compile does.
+// opts are the options that would be passed to Compile, they are used to check trigger mode, max run steps and interrupt nodes.
+// sub graphs are validated with the compile options set by WithGraphCompileOptions.
+// Validate does not modify the graph, so it can be called before Compile, e.g. in CI to check graphs built by factories.
+// e.g.
+//
+//	report := graph.Validate(compose.WithInterruptBeforeNodes([]string{"tools"}))
+//	for _, issue := range report.Issues {
+//		log.Println(issue)
+//	}
+func (g *Graph[I, O]) Validate(opts ...GraphCompileOption) *ValidationReport {
+	return &ValidationReport{Issues: g.graph.validate(newGraphCompileOptions(opts...))}
+}
+
+// Validate checks the chain without compiling it, and reports all the problems found at once.
+// see Graph.Validate for details.
+func (c *Chain[I, O]) Validate(opts ...GraphCompileOption) *ValidationReport {
+	return &ValidationReport{Issues: c.validate(newGraphCompileOptions(opts...))}
+}
+
+func (c *Chain[I, O]) validate(opt *graphCompileOptions) []*ValidationIssue {
+	if c.err != nil {
+		// nodes after the failed one have not been appended, the rest of the chain is meaningless to check.
+		issues := []*ValidationIssue{issueFromError(c.err)}
+		for _, err := range c.gg.buildErrors {
+			if !errors.Is(c.err, err) {
+				issues = append(issues, issueFromError(err))
+			}
+		}
+		return issues
+	}
+
+	var extraEnds []string
+	if !c.hasEnd {
+		if len(c.preNodeKeys) == 0 {
+			return append(c.gg.validateBuildErrors(), &ValidationIssue{
+				Kind:    ValidationIssueBuild,
+				Message: fmt.Sprintf("pre node keys not set, number of nodes in chain= %d", len(c.gg.nodes)),
+			})
+		}
+		// END edges are only added when compiling
+		extraEnds = c.preNodeKeys
+	}
+
+	return c.gg.validateWithEnds(opt, extraEnds)
+}
+
+func (g *graph) validate(opt *graphCompileOptions) []*ValidationIssue {
+	return g.validateWithEnds(opt, nil)
+}
+
+// validateBuildErrors returns the issues of all the errors hit while building the graph.
+// the failed build steps are not applied, and the edges and branches of the nodes failed to add are skipped.
+func (g *graph) validateBuildErrors() []*ValidationIssue {
+	issues := make([]*ValidationIssue, 0, len(g.buildErrors))
+	for _, err := range g.buildErrors {
+		issues = append(issues, issueFromError(err))
+	}
+	return issues
+}
+
+// validateWithEnds runs every check of compile without modifying the graph.
+// extraEnds are the nodes that are going to be connected to END before compiling, such as the last nodes of a chain.
+func (g *graph) validateWithEnds(opt *graphCompileOptions, extraEnds []string) []*ValidationIssue {
+	issues := g.validateBuildErrors()
+	addIssue := func(kind ValidationIssueKind, nodes []string, format string, args ...any) {
+		issues = append(issues, &ValidationIssue{Kind: kind, Nodes: nodes, Message: fmt.Sprintf(format, args...)})
+	}
+
+	isDAG := isWorkflow(g.cmp)
+	if opt.nodeTriggerMode != "" && (isChain(g.cmp) || isWorkflow(g.cmp)) {
+		addIssue(ValidationIssueBuild, nil, "%s doesn't support node trigger mode option", g.cmp)
+	} else if opt.nodeTriggerMode == AllPredecessor {
+		isDAG = true
+	}
+
+	// the structure of the graph is incomplete if any build step failed, e.g. the edges of a node failed to add are missing,
+	// so the checks of the structure are skipped, to avoid reporting the consequences of the build errors.
+	structureBuilt := len(g.buildErrors) == 0
+
+	if structureBuilt && len(g.startNodes) == 0 {
+		addIssue(ValidationIssueBuild, nil, "start node not set")
+	}
+	if structureBuilt && len(g.endNodes) == 0 && len(extraEnds) == 0 {
+		addIssue(ValidationIssueBuild, nil, "end node not set")
+	}
+
+	for _, startNode := range sortedKeys(g.toValidateMap) {
+		for _, v := range g.toValidateMap[startNode] {
+			addIssue(ValidationIssueBuild, []string{startNode, v.endNode},
+				"input or output types of edge[%s]-[%s] cannot be inferred", startNode, v.endNode)
+		}
+	}
+
+	for _, key := range sortedKeys(g.fieldMappingRecords) {
+		toMap := make(map[string]bool)
+		for _, mapping := range g.fieldMappingRecords[key] {
+			if toMap[mapping.to] {
+				addIssue(ValidationIssueFieldMapping, []string{key}, "duplicate mapping target field: %s of node[%s]", mapping.to, key)
+			}
+			toMap[mapping.to] = true
+		}
+	}
+
+	successors := make(map[string][]string, len(g.nodes)+1)
+	for start, ends := range g.controlEdges {
+		successors[start] = append(successors[start], ends...)
+	}
+	for start, branches := range g.branches {
+		for _, branch := range branches {
+			successors[start] = append(successors[start], sortedKeys(branch.endNodes)...)
+		}
+	}
+	for _, node := range extraEnds {
+		successors[node] = append(successors[node], END)
+	}
+	predecessors := make(map[string][]string, len(g.nodes)+1)
+	for start, ends := range successors {
+		for _, end := range ends {
+			predecessors[end] = append(predecessors[end], start)
+		}
+	}
+
+	fromStart := walkGraph(START, successors)
+	toEnd := walkGraph(END, predecessors)
+
+	nodeKeys := sortedKeys(g.nodes)
+	if structureBuilt {
+		branchTargets := make(map[string]bool)
+		for _, start := range sortedKeys(g.branches) {
+			if start != START && !fromStart[start] {
+				continue
+			}
+			for _, branch := range g.branches[start] {
+				for _, end := range sortedKeys(branch.endNodes) {
+					if end == END || toEnd[end] {
+						continue
+					}
+					branchTargets[end] = true
+					addIssue(ValidationIssueDeadEndBranch, []string{start, end},
+						"branch of node[%s] may choose node[%s], which can never reach END", start, end)
+				}
+			}
+		}
+		for _, key := range nodeKeys {
+			if !fromStart[key] {
+				addIssue(ValidationIssueUnreachableNode, []string{key}, "node[%s] can never be reached from START", key)
+			} else if !toEnd[key] && !branchTargets[key] {
+				addIssue(ValidationIssueDeadEndNode, []string{key}, "END can never be reached from node[%s]", key)
+			}
+		}
+	}
+
+	knownNodes := make(map[string]bool, len(g.nodes))
+	for key := range g.nodes {
+		knownNodes[key] = true
+	}
+	for key := range g.failedNodes {
+		knownNodes[key] = true
+	}
+	for _, key := range opt.interruptBeforeNodes {
+		if !knownNodes[key] {
+			addIssue(ValidationIssueInterruptNode, []string{key}, "interrupt before node[%s] doesn't exist in graph", key)
+		}
+	}
+	for _, key := range opt.interruptAfterNodes {
+		if !knownNodes[key] {
+			addIssue(ValidationIssueInterruptNode, []string{key}, "interrupt after node[%s] doesn't exist in graph", key)
+		}
+	}
+
+	loops := findLoops(nodeKeys, successors)
+	if isDAG {
+		if len(loops) > 0 {
+			addIssue(ValidationIssueIllegalLoop, loopNodes(loops), "%s: %s", DAGInvalidLoopErr.Error(), formatLoops(loops))
+		}
+		if opt.maxRunSteps > 0 {
+			addIssue(ValidationIssueBuild, nil, "cannot set max run steps in dag mode")
+		}
+	} else if len(loops) > 0 && opt.maxRunSteps <= 0 {
+		addIssue(ValidationIssueUnguardedLoop, loopNodes(loops),
+			"graph has loops %s but max run steps is not set, use WithMaxRunSteps to bound them", formatLoops(loops))
+	}
+
+	if structureBuilt && opt.checkTemplateVariables {
+		issues = append(issues, g.templateVariablesIssues(opt.graphInputKeys, isDAG || isChain(g.cmp))...)
+	}
+
+	for _, key := range nodeKeys {
+		gn := g.nodes[key]
+		if gn.g == nil {
+			continue
+		}
+		v, ok := gn.g.(validatable)
+		if !ok {
+			continue
+		}
+		for _, issue := range v.validate(gn.nodeInfo.compileOption) {
+			issue.SubGraphPath = append([]string{key}, issue.SubGraphPath...)
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+func walkGraph(from string, next map[string][]string) map[string]bool {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, n := range next[cur] {
+			if !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return visited
+}
+
+func loopNodes(loops [][]string) []string {
+	seen := make(map[string]bool)
+	var ret []string
+	for _, loop := range loops {
+		for _, node := range loop {
+			if !seen[node] {
+				seen[node] = true
+				ret = append(ret, node)
+			}
+		}
+	}
+	return ret
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
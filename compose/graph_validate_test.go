@@ -0,0 +1,172 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphValidateReport(t *testing.T) {
+	ctx := context.Background()
+	strLambda := func() *Lambda {
+		return InvokableLambda(func(ctx context.Context, in string) (string, error) { return in, nil })
+	}
+
+	type fieldStruct struct {
+		A string
+	}
+
+	t.Run("valid graph has no issue", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("1", strLambda()))
+		assert.NoError(t, g.AddEdge(START, "1"))
+		assert.NoError(t, g.AddEdge("1", END))
+
+		report := g.Validate()
+		assert.False(t, report.HasIssues())
+		assert.NoError(t, report.Err())
+
+		_, err := g.Compile(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("all issues reported at once", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("1", strLambda()))
+		assert.NoError(t, g.AddLambdaNode("unreachable", strLambda()))
+		assert.NoError(t, g.AddLambdaNode("dead_end", strLambda()))
+		assert.NoError(t, g.AddLambdaNode("loop", strLambda()))
+		assert.NoError(t, g.AddEdge(START, "1"))
+		assert.NoError(t, g.AddBranch("1", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+			return END, nil
+		}, map[string]bool{END: true, "dead_end": true, "loop": true})))
+		assert.NoError(t, g.AddEdge("loop", "1"))
+		assert.NoError(t, g.AddEdge("unreachable", END))
+
+		report := g.Validate(WithInterruptBeforeNodes([]string{"1", "not_exist"}))
+		assert.True(t, report.HasIssues())
+		assert.Len(t, report.IssuesOf(ValidationIssueDeadEndBranch), 1)
+		assert.Equal(t, []string{"1", "dead_end"}, report.IssuesOf(ValidationIssueDeadEndBranch)[0].Nodes)
+		assert.Len(t, report.IssuesOf(ValidationIssueInterruptNode), 1)
+		assert.Equal(t, []string{"not_exist"}, report.IssuesOf(ValidationIssueInterruptNode)[0].Nodes)
+		assert.Len(t, report.IssuesOf(ValidationIssueUnguardedLoop), 1)
+		assert.ElementsMatch(t, []string{"1", "loop"}, report.IssuesOf(ValidationIssueUnguardedLoop)[0].Nodes)
+		unreachable := report.IssuesOf(ValidationIssueUnreachableNode)
+		assert.Len(t, unreachable, 1)
+		assert.Equal(t, []string{"unreachable"}, unreachable[0].Nodes)
+		assert.ErrorContains(t, report.Err(), "graph validation found")
+
+		// max run steps guards the loop
+		report = g.Validate(WithMaxRunSteps(10))
+		assert.Len(t, report.IssuesOf(ValidationIssueUnguardedLoop), 0)
+	})
+
+	t.Run("build errors", func(t *testing.T) {
+		g := NewGraph[string, string](WithGenLocalState(func(ctx context.Context) (state string) { return "" }))
+		assert.NoError(t, g.AddLambdaNode("1", strLambda()))
+		assert.NoError(t, g.AddLambdaNode("struct", InvokableLambda(func(ctx context.Context, in fieldStruct) (string, error) { return in.A, nil })))
+		// unknown field
+		err := g.graph.addEdgeWithMappings("1", "struct", false, false, ToField("B"))
+		assert.Error(t, err)
+
+		// the following steps are still checked, the failed ones are not applied
+		stateErr := g.AddLambdaNode("bad_state", strLambda(), WithStatePreHandler(func(ctx context.Context, in string, state int) (string, error) {
+			return in, nil
+		}))
+		assert.ErrorContains(t, stateErr, "pre handler state type[int] is different from graph[string]")
+		// the edges of the node failed to add are skipped
+		assert.Equal(t, err, g.AddEdge("1", "bad_state"))
+		// the successful steps return the first error, as the graph can't be compiled
+		assert.Equal(t, err, g.AddEdge(START, "1"))
+		assert.Len(t, g.graph.nodes, 2)
+		assert.Empty(t, g.graph.dataEdges["1"])
+		assert.Empty(t, g.graph.fieldMappingRecords["struct"])
+
+		report := g.Validate(WithInterruptBeforeNodes([]string{"bad_state"}))
+		assert.Len(t, report.IssuesOf(ValidationIssueFieldMapping), 1)
+		assert.Len(t, report.IssuesOf(ValidationIssueStateHandler), 1)
+		// the structure depending on the failed steps is not checked
+		assert.Len(t, report.Issues, 2)
+
+		// compile fails with the first error
+		_, cErr := g.Compile(ctx)
+		assert.Equal(t, err.Error(), cErr.Error())
+	})
+
+	t.Run("dag loop", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("1", strLambda()))
+		assert.NoError(t, g.AddLambdaNode("2", strLambda()))
+		assert.NoError(t, g.AddEdge(START, "1"))
+		assert.NoError(t, g.AddEdge("1", "2"))
+		assert.NoError(t, g.AddEdge("2", "1"))
+		assert.NoError(t, g.AddEdge("2", END))
+
+		report := g.Validate(WithNodeTriggerMode(AllPredecessor))
+		assert.Len(t, report.Issues, 1)
+		assert.Equal(t, ValidationIssueIllegalLoop, report.Issues[0].Kind)
+	})
+
+	t.Run("sub graph", func(t *testing.T) {
+		sub := NewGraph[string, string]()
+		assert.NoError(t, sub.AddLambdaNode("inner", strLambda()))
+		assert.NoError(t, sub.AddLambdaNode("orphan", strLambda()))
+		assert.NoError(t, sub.AddEdge(START, "inner"))
+		assert.NoError(t, sub.AddEdge("inner", END))
+
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddGraphNode("sub", sub))
+		assert.NoError(t, g.AddEdge(START, "sub"))
+		assert.NoError(t, g.AddEdge("sub", END))
+
+		report := g.Validate()
+		assert.Len(t, report.Issues, 1)
+		assert.Equal(t, ValidationIssueUnreachableNode, report.Issues[0].Kind)
+		assert.Equal(t, []string{"sub"}, report.Issues[0].SubGraphPath)
+		assert.Equal(t, []string{"orphan"}, report.Issues[0].Nodes)
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		c := NewChain[string, string]()
+		c.AppendLambda(strLambda()).AppendLambda(strLambda())
+		assert.False(t, c.Validate().HasIssues())
+		// validate doesn't close the chain
+		c.AppendLambda(strLambda())
+		r, err := c.Compile(ctx)
+		assert.NoError(t, err)
+		out, err := r.Invoke(ctx, "hi")
+		assert.NoError(t, err)
+		assert.Equal(t, "hi", out)
+
+		empty := NewChain[string, string]()
+		report := empty.Validate()
+		assert.True(t, report.HasIssues())
+		assert.Equal(t, ValidationIssueBuild, report.Issues[0].Kind)
+
+		// the error of the chain is reported once, though the graph of the chain records it too
+		failed := NewChain[string, string]()
+		failed.AppendLambda(strLambda(), WithStatePreHandler(func(ctx context.Context, in string, state string) (string, error) {
+			return in, nil
+		}))
+		report = failed.Validate()
+		assert.Len(t, report.Issues, 1)
+		assert.Contains(t, report.Issues[0].Message, "needs state but graph state is not enabled")
+	})
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+
+	"github.com/mrh997/eino/callbacks"
+	"github.com/mrh997/eino/schema"
+)
+
+// reportJSONRepair triggers the callbacks of ComponentOfJSONRepair if any repair is made,
+// with the original json as the input and *schema.JSONRepairResult as the output.
+func reportJSONRepair(ctx context.Context, name string, result *schema.JSONRepairResult) {
+	if len(result.Repairs) == 0 {
+		return
+	}
+
+	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
+		Name:      name,
+		Type:      "JSONRepair",
+		Component: ComponentOfJSONRepair,
+	})
+	ctx = callbacks.OnStart(ctx, result.Original)
+	_ = callbacks.OnEnd(ctx, result)
+}
+
+// repairToolArguments returns the repaired arguments, or the original ones if they can't be repaired.
+func repairToolArguments(ctx context.Context, toolName, arguments string) string {
+	result, err := schema.RepairJSON(arguments)
+	if err != nil {
+		return arguments
+	}
+	reportJSONRepair(ctx, toolName, result)
+	return result.Repaired
+}
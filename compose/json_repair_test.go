@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/callbacks"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/components/tool/utils"
+	"github.com/mrh997/eino/schema"
+)
+
+func TestJSONRepairCallbacks(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu      sync.Mutex
+		reports = map[string]*schema.JSONRepairResult{}
+	)
+	handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+		if info.Component == ComponentOfJSONRepair {
+			mu.Lock()
+			reports[info.Name] = output.(*schema.JSONRepairResult)
+			mu.Unlock()
+		}
+		return ctx
+	}).Build()
+
+	t.Run("tool arguments", func(t *testing.T) {
+		weather, err := utils.InferTool("weather", "get weather", func(ctx context.Context, in *weatherInput) (string, error) {
+			return "sunny in " + in.City, nil
+		})
+		assert.NoError(t, err)
+
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:                 []tool.BaseTool{weather},
+			RepairToolArguments:   true,
+			ValidateToolArguments: true,
+		})
+		assert.NoError(t, err)
+
+		g := NewGraph[*schema.Message, []*schema.Message]()
+		assert.NoError(t, g.AddToolsNode("tools", tn))
+		assert.NoError(t, g.AddEdge(START, "tools"))
+		assert.NoError(t, g.AddEdge("tools", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		msgs, err := r.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+			{ID: "1", Function: schema.FunctionCall{Name: "weather", Arguments: `{city: 'paris',`}},
+		}), WithCallbacks(handler))
+		assert.NoError(t, err)
+		assert.Equal(t, "sunny in paris", msgs[0].Content)
+		assert.Equal(t, []schema.JSONRepairKind{schema.JSONRepairUnquotedKey, schema.JSONRepairSingleQuotes, schema.JSONRepairMissingClose},
+			reports["weather"].Repairs)
+	})
+
+	t.Run("message parser", func(t *testing.T) {
+		parser := schema.NewMessageJSONParser[*weatherInput](&schema.MessageJSONParseConfig{RepairJSON: true})
+		chain := NewChain[*schema.Message, *weatherInput]()
+		chain.AppendLambda(MessageParser(parser))
+		r, err := chain.Compile(ctx)
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, schema.AssistantMessage(`Sure: {"city": "rome"}`, nil), WithCallbacks(handler))
+		assert.NoError(t, err)
+		assert.Equal(t, "rome", out.City)
+		assert.Equal(t, []schema.JSONRepairKind{schema.JSONRepairStrayText}, reports["MessageParser"].Repairs)
+	})
+	t.Run("control characters", func(t *testing.T) {
+		parser := schema.NewMessageJSONParser[*weatherInput](&schema.MessageJSONParseConfig{RepairJSON: true})
+		chain := NewChain[*schema.Message, *weatherInput]()
+		chain.AppendLambda(MessageParser(parser))
+		r, err := chain.Compile(ctx)
+		assert.NoError(t, err)
+
+		delete(reports, "MessageParser")
+		out, err := r.Invoke(ctx, schema.AssistantMessage("{\"city\": \"new\tyork\"}", nil), WithCallbacks(handler))
+		assert.NoError(t, err)
+		assert.Equal(t, "new\tyork", out.City)
+		assert.Equal(t, []schema.JSONRepairKind{schema.JSONRepairControlChar}, reports["MessageParser"].Repairs)
+	})
+}
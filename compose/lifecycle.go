@@ -0,0 +1,200 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/mrh997/eino/components"
+)
+
+// resource is a node instance or tool implementing components.Initializer and / or components.Closer.
+type resource struct {
+	name     string // node key path, and tool name for tools of ToolsNode
+	instance any
+}
+
+func isResource(instance any) bool {
+	if instance == nil {
+		return false
+	}
+	if _, ok := instance.(components.Initializer); ok {
+		return true
+	}
+	_, ok := instance.(components.Closer)
+	return ok
+}
+
+// collectResources returns the resources of the graph nodes in the order they were added.
+// resources of sub graphs have been collected when compiling the sub graph, and are prefixed by the node key.
+func (g *graph) collectResources() []*resource {
+	var ret []*resource
+	for _, key := range g.nodeOrder {
+		gn := g.nodes[key]
+		if gn.g != nil {
+			if gn.cr != nil {
+				for _, res := range gn.cr.resources {
+					ret = append(ret, &resource{name: key + "/" + res.name, instance: res.instance})
+				}
+			}
+			continue
+		}
+
+		if tn, ok := gn.instance.(*ToolsNode); ok {
+			for i, t := range tn.tuple.tools {
+				if isResource(t) {
+					ret = append(ret, &resource{name: key + "/" + tn.tuple.names[i], instance: t})
+				}
+			}
+			continue
+		}
+
+		if isResource(gn.instance) {
+			ret = append(ret, &resource{name: key, instance: gn.instance})
+		}
+	}
+	return ret
+}
+
+// dedupResources removes instances used by more than one node, so that each of them is initialized and closed only once.
+// instances are identified by pointers, as comparing other values may panic, e.g. a struct with an interface field holding a map.
+func dedupResources(resources []*resource) []*resource {
+	seen := make(map[any]bool, len(resources))
+	ret := make([]*resource, 0, len(resources))
+	for _, res := range resources {
+		if reflect.TypeOf(res.instance).Kind() == reflect.Ptr {
+			if seen[res.instance] {
+				continue
+			}
+			seen[res.instance] = true
+		}
+		ret = append(ret, res)
+	}
+	return ret
+}
+
+// initResources initializes resources in order.
+// if one of them fails, the ones already initialized are closed in reverse order.
+func initResources(ctx context.Context, resources []*resource) error {
+	for i, res := range resources {
+		initializer, ok := res.instance.(components.Initializer)
+		if !ok {
+			continue
+		}
+		if err := initializer.Init(ctx); err != nil {
+			err = fmt.Errorf("failed to init node[%s]: %w", res.name, err)
+			if closeErr := closeResources(ctx, resources[:i]); closeErr != nil {
+				return &lifecycleError{errs: []error{err, closeErr}}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// closeResources closes resources in reverse order, all of them are closed even if some fail.
+func closeResources(ctx context.Context, resources []*resource) error {
+	var errs []error
+	for i := len(resources) - 1; i >= 0; i-- {
+		closer, ok := resources[i].instance.(components.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close node[%s]: %w", resources[i].name, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &lifecycleError{errs: errs}
+}
+
+// lifecycleError aggregates the errors of initializing or closing several resources.
+type lifecycleError struct {
+	errs []error
+}
+
+func (e *lifecycleError) Error() string {
+	msgs := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the aggregated errors matches target.
+func (e *lifecycleError) Is(target error) bool {
+	for _, err := range e.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first aggregated error that matches target.
+func (e *lifecycleError) As(target any) bool {
+	for _, err := range e.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// closableRunnable is the Runnable returned by compiling a graph,
+// it owns the resources of the graph nodes and implements components.Closer.
+type closableRunnable[I, O any] struct {
+	*runnablePacker[I, O, Option]
+
+	mu        sync.Mutex
+	closed    bool
+	resources []*resource
+}
+
+// Close closes all the node instances, sub graph nodes and ToolsNode tools implementing components.Closer,
+// in the reverse order of initialization, and returns the aggregated errors.
+// Close is idempotent, only the first call takes effect.
+func (cr *closableRunnable[I, O]) Close(ctx context.Context) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if cr.closed {
+		return nil
+	}
+	cr.closed = true
+
+	return closeResources(ctx, cr.resources)
+}
+
+// CloseRunnable closes the resources held by a Runnable returned by Compile, see components.Closer.
+// it does nothing for Runnables not implementing components.Closer.
+func CloseRunnable[I, O any](ctx context.Context, r Runnable[I, O]) error {
+	closer, ok := r.(components.Closer)
+	if !ok {
+		return nil
+	}
+	return closer.Close(ctx)
+}
+
+var _ components.Closer = (*closableRunnable[any, any])(nil)
@@ -0,0 +1,180 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/retriever"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+type lifecycleRecorder struct {
+	events []string
+}
+
+type lifecycleRetriever struct {
+	name     string
+	rec      *lifecycleRecorder
+	initErr  error
+	closeErr error
+}
+
+func (l *lifecycleRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	return nil, nil
+}
+
+func (l *lifecycleRetriever) Init(ctx context.Context) error {
+	l.rec.events = append(l.rec.events, "init "+l.name)
+	return l.initErr
+}
+
+func (l *lifecycleRetriever) Close(ctx context.Context) error {
+	l.rec.events = append(l.rec.events, "close "+l.name)
+	return l.closeErr
+}
+
+type lifecycleTool struct {
+	lifecycleRetriever
+}
+
+func (l *lifecycleTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: l.name}, nil
+}
+
+func (l *lifecycleTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return "", nil
+}
+
+// valueRetriever is a resource used by value, with an interface field which makes comparing it panic when holding a map.
+type valueRetriever struct {
+	rec  *lifecycleRecorder
+	meta any
+}
+
+func (v valueRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	return nil, nil
+}
+
+func (v valueRetriever) Init(ctx context.Context) error {
+	v.rec.events = append(v.rec.events, "init value")
+	return nil
+}
+
+func TestGraphLifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	buildGraph := func(rec *lifecycleRecorder, r1Init, r2Close error) (*Graph[string, []*schema.Document], *lifecycleRetriever) {
+		r1 := &lifecycleRetriever{name: "r1", rec: rec, initErr: r1Init}
+		r2 := &lifecycleRetriever{name: "r2", rec: rec, closeErr: r2Close}
+		tl := &lifecycleTool{lifecycleRetriever{name: "tool", rec: rec}}
+
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{tl}})
+		assert.NoError(t, err)
+
+		sub := NewGraph[*schema.Message, []*schema.Message]()
+		assert.NoError(t, sub.AddToolsNode("tools", tn))
+		assert.NoError(t, sub.AddEdge(START, "tools"))
+		assert.NoError(t, sub.AddEdge("tools", END))
+
+		g := NewGraph[string, []*schema.Document]()
+		assert.NoError(t, g.AddRetrieverNode("r2", r2))
+		assert.NoError(t, g.AddGraphNode("sub", sub))
+		assert.NoError(t, g.AddRetrieverNode("r1", r1))
+		// the same instance in two nodes is only initialized and closed once
+		assert.NoError(t, g.AddRetrieverNode("r1_again", r1))
+		assert.NoError(t, g.AddEdge(START, "r2"))
+		assert.NoError(t, g.AddEdge("r2", END))
+		assert.NoError(t, g.AddLambdaNode("to_msg", InvokableLambda(func(ctx context.Context, in string) (*schema.Message, error) {
+			return schema.AssistantMessage("", nil), nil
+		})))
+		assert.NoError(t, g.AddLambdaNode("from_msgs", InvokableLambda(func(ctx context.Context, in []*schema.Message) (string, error) {
+			return "", nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "to_msg"))
+		assert.NoError(t, g.AddEdge("to_msg", "sub"))
+		assert.NoError(t, g.AddEdge("sub", "from_msgs"))
+		assert.NoError(t, g.AddEdge("from_msgs", "r1"))
+		assert.NoError(t, g.AddEdge("r1", END))
+		assert.NoError(t, g.AddEdge("from_msgs", "r1_again"))
+		assert.NoError(t, g.AddEdge("r1_again", END))
+		return g, r1
+	}
+
+	t.Run("init on compile and close in reverse order", func(t *testing.T) {
+		rec := &lifecycleRecorder{}
+		g, _ := buildGraph(rec, nil, nil)
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"init r2", "init tool", "init r1"}, rec.events)
+
+		rec.events = nil
+		assert.NoError(t, CloseRunnable(ctx, r))
+		assert.Equal(t, []string{"close r1", "close tool", "close r2"}, rec.events)
+
+		// idempotent
+		rec.events = nil
+		assert.NoError(t, CloseRunnable(ctx, r))
+		assert.Empty(t, rec.events)
+	})
+
+	t.Run("init failure closes initialized resources", func(t *testing.T) {
+		rec := &lifecycleRecorder{}
+		initErr := errors.New("init fail")
+		g, _ := buildGraph(rec, initErr, nil)
+		_, err := g.Compile(ctx)
+		assert.ErrorIs(t, err, initErr)
+		assert.ErrorContains(t, err, "failed to init node[r1]")
+		assert.Equal(t, []string{"init r2", "init tool", "init r1", "close tool", "close r2"}, rec.events)
+	})
+
+	t.Run("close errors are aggregated", func(t *testing.T) {
+		rec := &lifecycleRecorder{}
+		closeErr := errors.New("close fail")
+		g, r1 := buildGraph(rec, nil, closeErr)
+		r1.closeErr = errors.New("another close fail")
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		rec.events = nil
+		err = CloseRunnable(ctx, r)
+		assert.ErrorIs(t, err, closeErr)
+		assert.ErrorIs(t, err, r1.closeErr)
+		assert.ErrorContains(t, err, "failed to close node[r2]")
+		assert.Equal(t, []string{"close r1", "close tool", "close r2"}, rec.events)
+	})
+	t.Run("resources used by value", func(t *testing.T) {
+		rec := &lifecycleRecorder{}
+		r := valueRetriever{rec: rec, meta: map[string]any{"k": "v"}}
+		g := NewGraph[string, map[string]any]()
+		assert.NoError(t, g.AddRetrieverNode("a", r, WithOutputKey("a")))
+		assert.NoError(t, g.AddRetrieverNode("b", r, WithOutputKey("b")))
+		assert.NoError(t, g.AddEdge(START, "a"))
+		assert.NoError(t, g.AddEdge(START, "b"))
+		assert.NoError(t, g.AddEdge("a", END))
+		assert.NoError(t, g.AddEdge("b", END))
+		_, err := g.Compile(ctx)
+		assert.NoError(t, err)
+		// values can't be identified, so each node initializes its own copy
+		assert.Equal(t, []string{"init value", "init value"}, rec.events)
+	})
+}
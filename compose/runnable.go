@@ -60,6 +60,9 @@ type composableRunnable struct {
 	// only available when in Graph node
 	// if composableRunnable not in Graph node, this field would be nil
 	nodeInfo *nodeInfo
+
+	// only available when compiled from a graph, the node instances implementing components.Initializer or components.Closer
+	resources []*resource
 }
 
 func runnableLambda[I, O, TOption any](i Invoke[I, O, TOption], s Stream[I, O, TOption], c Collect[I, O, TOption],
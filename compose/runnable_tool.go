@@ -0,0 +1,73 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/components/tool/utils"
+	"github.com/mrh997/eino/schema"
+)
+
+type runnableToolOptions struct {
+	callOptions []Option
+}
+
+// WithRunnableCallOptions forwards graph call options to the Runnable wrapped by InferRunnableTool or InferStreamableRunnableTool.
+// e.g.
+//
+//	// pass callbacks to the sub agent exposed as a tool of a ReAct agent
+//	agent.Generate(ctx, msgs, react.WithToolOptions(compose.WithRunnableCallOptions(compose.WithCallbacks(handler))))
+func WithRunnableCallOptions(opts ...Option) tool.Option {
+	return tool.WrapImplSpecificOptFn(func(o *runnableToolOptions) {
+		o.callOptions = append(o.callOptions, opts...)
+	})
+}
+
+func getRunnableCallOptions(opts ...tool.Option) []Option {
+	return tool.GetImplSpecificOptions(&runnableToolOptions{}, opts...).callOptions
+}
+
+// InferRunnableTool exposes a Runnable, such as a compiled sub agent or RAG pipeline, as an InvokableTool.
+// the ToolInfo is inferred from the input type I in the same way as utils.GoStruct2ToolInfo,
+// the arguments are unmarshalled into I, and the output O is marshalled to JSON unless it is a string.
+// use utils.WithUnmarshalArguments / utils.WithMarshalOutput to customize the conversions,
+// and WithRunnableCallOptions to pass graph call options when the tool is called.
+// e.g.
+//
+//	type ragInput struct {
+//		Query string `json:"query" jsonschema:"description=the question to search for"`
+//	}
+//
+//	rag, err := ragGraph.Compile(ctx) // Runnable[*ragInput, string]
+//	ragTool, err := compose.InferRunnableTool("search_docs", "search the documents", rag)
+//	toolsNode, err := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{Tools: []tool.BaseTool{ragTool}})
+func InferRunnableTool[I, O any](toolName, toolDesc string, r Runnable[I, O], opts ...utils.Option) (tool.InvokableTool, error) {
+	return utils.InferOptionableTool(toolName, toolDesc, func(ctx context.Context, input I, opts ...tool.Option) (O, error) {
+		return r.Invoke(ctx, input, getRunnableCallOptions(opts...)...)
+	}, opts...)
+}
+
+// InferStreamableRunnableTool exposes a Runnable as a StreamableTool, the output stream of the Runnable is forwarded chunk by chunk.
+// each chunk of O is marshalled to JSON unless it is a string, so string output is the most common choice.
+// see InferRunnableTool for details.
+func InferStreamableRunnableTool[I, O any](toolName, toolDesc string, r Runnable[I, O], opts ...utils.Option) (tool.StreamableTool, error) {
+	return utils.InferOptionableStreamTool(toolName, toolDesc, func(ctx context.Context, input I, opts ...tool.Option) (*schema.StreamReader[O], error) {
+		return r.Stream(ctx, input, getRunnableCallOptions(opts...)...)
+	}, opts...)
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/callbacks"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+type runnableToolInput struct {
+	Query string `json:"query" jsonschema:"description=the query"`
+	TopK  int    `json:"top_k,omitempty"`
+}
+
+type runnableToolOutput struct {
+	Answer string `json:"answer"`
+}
+
+func TestInferRunnableTool(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[*runnableToolInput, *runnableToolOutput]()
+	assert.NoError(t, g.AddLambdaNode("answer", InvokableLambda(func(ctx context.Context, in *runnableToolInput) (*runnableToolOutput, error) {
+		return &runnableToolOutput{Answer: strings.Repeat(in.Query, in.TopK)}, nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "answer"))
+	assert.NoError(t, g.AddEdge("answer", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	it, err := InferRunnableTool("answer", "answer the query", r)
+	assert.NoError(t, err)
+
+	info, err := it.Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "answer", info.Name)
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"query"}, js.Required)
+	assert.Equal(t, "the query", js.Properties.Value("query").Description)
+
+	var started bool
+	handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		started = true
+		return ctx
+	}).Build()
+
+	out, err := it.InvokableRun(ctx, `{"query":"a","top_k":2}`, WithRunnableCallOptions(WithCallbacks(handler)))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"answer":"aa"}`, out)
+	assert.True(t, started)
+
+	// used by ToolsNode
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{it}})
+	assert.NoError(t, err)
+	msgs, err := tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{{
+		ID:       "1",
+		Function: schema.FunctionCall{Name: "answer", Arguments: `{"query":"b","top_k":3}`},
+	}}))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"answer":"bbb"}`, msgs[0].Content)
+}
+
+func TestInferStreamableRunnableTool(t *testing.T) {
+	ctx := context.Background()
+
+	g := NewGraph[*runnableToolInput, string]()
+	assert.NoError(t, g.AddLambdaNode("answer", StreamableLambda(func(ctx context.Context, in *runnableToolInput) (*schema.StreamReader[string], error) {
+		chunks := make([]string, in.TopK)
+		for i := range chunks {
+			chunks[i] = in.Query
+		}
+		return schema.StreamReaderFromArray(chunks), nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "answer"))
+	assert.NoError(t, g.AddEdge("answer", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	st, err := InferStreamableRunnableTool("answer", "answer the query", r)
+	assert.NoError(t, err)
+
+	sr, err := st.StreamableRun(ctx, `{"query":"a","top_k":3}`)
+	assert.NoError(t, err)
+	var chunks []string
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	assert.Equal(t, []string{"a", "a", "a"}, chunks)
+}
@@ -34,6 +34,10 @@ type toolsNodeOptions struct {
 	ToolOptions   []tool.Option
 	ToolList      []tool.BaseTool
 	executedTools map[string]string
+	pendingJobs   map[string]string
+
+	approvalDecisions map[string]*ToolApprovalDecision
+	jobResults        map[string]string
 }
 
 // ToolsNodeOption is the option func type for ToolsNode.
@@ -59,6 +63,12 @@ func withExecutedTools(executedTools map[string]string) ToolsNodeOption {
 	}
 }
 
+func withPendingJobs(pendingJobs map[string]string) ToolsNodeOption {
+	return func(o *toolsNodeOptions) {
+		o.pendingJobs = pendingJobs
+	}
+}
+
 // ToolsNode a node that can run tools in a graph. the interface in Graph Node as below:
 //
 //	Invoke(ctx context.Context, input *schema.Message, opts ...ToolsNodeOption) ([]*schema.Message, error)
@@ -68,11 +78,23 @@ type ToolsNode struct {
 	unknownToolHandler   func(ctx context.Context, name, input string) (string, error)
 	executeSequentially  bool
 	toolArgumentsHandler func(ctx context.Context, name, input string) (string, error)
+
+	repairArguments           bool
+	validateArguments         bool
+	argumentsViolationHandler func(ctx context.Context, name, arguments string, violations []*schema.SchemaViolation) (string, error)
+
+	approvalTools    map[string]bool
+	rejectionHandler func(ctx context.Context, name, arguments, reason string) (string, error)
+
+	policies    *toolPolicies
+	outputLimit *ToolOutputLimit
+
+	allowedToolsGetter func(ctx context.Context) ([]string, error)
 }
 
 // ToolsNodeConfig is the config for ToolsNode.
 type ToolsNodeConfig struct {
-	// Tools specify the list of tools can be called which are BaseTool but must implement InvokableTool or StreamableTool.
+	// Tools specify the list of tools can be called which are BaseTool but must implement InvokableTool, StreamableTool or MultimodalTool.
 	Tools []tool.BaseTool
 
 	// UnknownToolsHandler handles tool calls for non-existent tools when LLM hallucinates.
@@ -103,6 +125,67 @@ type ToolsNodeConfig struct {
 	//   - string: The processed arguments string to be used for tool execution
 	//   - error: Any error that occurred during preprocessing
 	ToolArgumentsHandler func(ctx context.Context, name, arguments string) (string, error)
+
+	// RepairToolArguments enables repairing the common mistakes in tool call arguments before ToolArgumentsHandler,
+	// e.g. code fences, trailing commas, single quotes, unquoted keys and truncated brackets, see schema.RepairJSON.
+	// each repair is reported by callbacks with RunInfo.Component ComponentOfJSONRepair and RunInfo.Name the tool name,
+	// the input of OnStart is the original arguments, and the output of OnEnd is *schema.JSONRepairResult.
+	// arguments that can't be repaired are passed to the tool as is.
+	RepairToolArguments bool
+
+	// ValidateToolArguments enables validating the arguments of each tool call against the ParamsOneOf of the tool before execution,
+	// whether it is defined by ParameterInfo or JSON schema. tools without ParamsOneOf are not validated.
+	// When the arguments are not valid JSON or violate the schema, the tool is not executed,
+	// and a tool message describing the violations is returned instead of an error, so that the model can correct the arguments.
+	// Validation happens after ToolArgumentsHandler.
+	ValidateToolArguments bool
+
+	// ToolArgumentsViolationHandler builds the content of the tool message returned for invalid arguments.
+	// This field is optional, only works when ValidateToolArguments is true.
+	// By default, the content is a JSON object like:
+	//	{"error":"invalid arguments of tool get_weather","violations":[{"path":"/city","message":"required property is missing"}]}
+	ToolArgumentsViolationHandler func(ctx context.Context, name, arguments string, violations []*schema.SchemaViolation) (string, error)
+
+	// ToolsRequiringApproval are the names of the tools whose calls must be approved by a human before execution.
+	// When the model calls one of them, the ToolsNode executes the other calls and interrupts the graph before executing it,
+	// the pending call is a *ToolApprovalRequest in InterruptInfo, see ExtractToolApprovalRequests.
+	// The graph must be compiled with a CheckPointStore to be resumed, pass the decisions by WithToolApprovalDecisions when resuming,
+	// the decision can approve the call, approve it with edited arguments, or reject it with a reason.
+	// Calls executed before the interrupt are not executed again.
+	ToolsRequiringApproval []string
+
+	// ToolRejectionHandler builds the content of the tool message returned for a rejected tool call.
+	// This field is optional, only works with ToolsRequiringApproval.
+	// By default, the content tells the model that the call is rejected by the user, with the reason if any.
+	ToolRejectionHandler func(ctx context.Context, name, arguments, reason string) (string, error)
+
+	// ToolPolicies are the execution policies of the tools keyed by tool name, including timeout, retries with backoff,
+	// concurrency limit and the result returned to the model when the retries are exhausted, see ToolPolicy.
+	// e.g.
+	//	ToolPolicies: map[string]*ToolPolicy{
+	//		"search": {Timeout: 5 * time.Second, MaxRetries: 2, MaxConcurrency: 2},
+	//	}
+	ToolPolicies map[string]*ToolPolicy
+
+	// DefaultToolPolicy is the policy of the tools not in ToolPolicies.
+	// Optional. Default no policy.
+	DefaultToolPolicy *ToolPolicy
+
+	// MaxConcurrentToolCalls limits the number of concurrent tool calls of all tools, shared by all executions of the ToolsNode.
+	// Optional. Default 0, no limit.
+	MaxConcurrentToolCalls int
+
+	// ToolOutputLimit limits the size of the tool outputs returned to the model, by characters or estimated tokens.
+	// the output exceeding the limit is truncated, kept by head and tail, or saved in a store to read more, see ToolOutputLimit.
+	// the streamed outputs of tools are concatenated to be limited, so they are no longer streamed chunk by chunk.
+	// Optional. Default no limit.
+	ToolOutputLimit *ToolOutputLimit
+
+	// AllowedToolsGetter returns the names of the tools allowed to be called in the current execution,
+	// e.g. the tools selected and bound to the model in the current turn when there are too many tools to bind all of them.
+	// calls to the other tools are handled as calls to non-existent tools, see UnknownToolsHandler.
+	// Optional. By default or when it returns nil, all tools are allowed.
+	AllowedToolsGetter func(ctx context.Context) ([]string, error)
 }
 
 // NewToolNode creates a new ToolsNode.
@@ -117,12 +200,35 @@ func NewToolNode(ctx context.Context, conf *ToolsNodeConfig) (*ToolsNode, error)
 	if err != nil {
 		return nil, err
 	}
+	if err = validateToolOutputLimit(conf.ToolOutputLimit); err != nil {
+		return nil, err
+	}
+
+	var approvalTools map[string]bool
+	if len(conf.ToolsRequiringApproval) > 0 {
+		approvalTools = make(map[string]bool, len(conf.ToolsRequiringApproval))
+		for _, name := range conf.ToolsRequiringApproval {
+			approvalTools[name] = true
+		}
+	}
 
 	return &ToolsNode{
 		tuple:                tuple,
 		unknownToolHandler:   conf.UnknownToolsHandler,
 		executeSequentially:  conf.ExecuteSequentially,
 		toolArgumentsHandler: conf.ToolArgumentsHandler,
+
+		repairArguments:           conf.RepairToolArguments,
+		validateArguments:         conf.ValidateToolArguments,
+		argumentsViolationHandler: conf.ToolArgumentsViolationHandler,
+
+		approvalTools:    approvalTools,
+		rejectionHandler: conf.ToolRejectionHandler,
+
+		policies:    newToolPolicies(conf),
+		outputLimit: conf.ToolOutputLimit,
+
+		allowedToolsGetter: conf.AllowedToolsGetter,
 	}, nil
 }
 
@@ -131,19 +237,44 @@ type ToolsInterruptAndRerunExtra struct {
 	ExecutedTools map[string]string
 	RerunTools    []string
 	RerunExtraMap map[string]any
+	// PendingJobs are the IDs of the jobs started by tool.AsyncTool and not done, keyed by tool call ID.
+	PendingJobs map[string]string
+}
+
+func (e *ToolsInterruptAndRerunExtra) addRerunTool(callID string, extra any) {
+	e.RerunTools = append(e.RerunTools, callID)
+	e.RerunExtraMap[callID] = extra
+	if job, ok := extra.(*PendingToolJob); ok {
+		if e.PendingJobs == nil {
+			e.PendingJobs = make(map[string]string)
+		}
+		e.PendingJobs[callID] = job.JobID
+	}
 }
 
 type toolsTuple struct {
 	indexes map[string]int
+	names   []string
+	tools   []tool.BaseTool
+	infos   []*schema.ToolInfo
 	meta    []*executorMeta
 	rps     []*runnablePacker[string, string, tool.Option]
+	// multimodal tools output the JSON of []schema.ChatMessagePart
+	multimodal []bool
+	async      []tool.AsyncTool
 }
 
 func convTools(ctx context.Context, tools []tool.BaseTool) (*toolsTuple, error) {
 	ret := &toolsTuple{
 		indexes: make(map[string]int),
+		names:   make([]string, len(tools)),
+		tools:   tools,
+		infos:   make([]*schema.ToolInfo, len(tools)),
 		meta:    make([]*executorMeta, len(tools)),
 		rps:     make([]*runnablePacker[string, string, tool.Option], len(tools)),
+
+		multimodal: make([]bool, len(tools)),
+		async:      make([]tool.AsyncTool, len(tools)),
 	}
 	for idx, bt := range tools {
 		tl, err := bt.Info(ctx)
@@ -163,25 +294,40 @@ func convTools(ctx context.Context, tools []tool.BaseTool) (*toolsTuple, error)
 			meta *executorMeta
 		)
 
-		if st, ok = bt.(tool.StreamableTool); ok {
-			streamable = st.StreamableRun
-		}
+		if at, isAsync := bt.(tool.AsyncTool); isAsync {
+			// the task of an async tool is built for each call, see newAsyncToolTask
+			ret.async[idx] = at
+			invokable = func(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+				return "", fmt.Errorf("async tool %s must be run by ToolsNode", toolName)
+			}
+			meta = parseExecutorInfoFromComponent(components.ComponentOfTool, at)
+		} else if mt, isMultimodal := bt.(tool.MultimodalTool); isMultimodal {
+			ret.multimodal[idx] = true
+			invokable = multimodalRunToInvokable(mt)
+			meta = parseExecutorInfoFromComponent(components.ComponentOfTool, mt)
+		} else {
+			if st, ok = bt.(tool.StreamableTool); ok {
+				streamable = st.StreamableRun
+			}
 
-		if it, ok = bt.(tool.InvokableTool); ok {
-			invokable = it.InvokableRun
-		}
+			if it, ok = bt.(tool.InvokableTool); ok {
+				invokable = it.InvokableRun
+			}
 
-		if st == nil && it == nil {
-			return nil, fmt.Errorf("tool %s is not invokable or streamable", toolName)
-		}
+			if st == nil && it == nil {
+				return nil, fmt.Errorf("tool %s is not invokable or streamable", toolName)
+			}
 
-		if st != nil {
-			meta = parseExecutorInfoFromComponent(components.ComponentOfTool, st)
-		} else {
-			meta = parseExecutorInfoFromComponent(components.ComponentOfTool, it)
+			if st != nil {
+				meta = parseExecutorInfoFromComponent(components.ComponentOfTool, st)
+			} else {
+				meta = parseExecutorInfoFromComponent(components.ComponentOfTool, it)
+			}
 		}
 
 		ret.indexes[toolName] = idx
+		ret.names[idx] = toolName
+		ret.infos[idx] = tl
 		ret.meta[idx] = meta
 		ret.rps[idx] = newRunnablePacker(invokable, streamable,
 			nil, nil, !meta.isComponentCallbackEnabled)
@@ -196,6 +342,8 @@ type toolCallTask struct {
 	name   string
 	arg    string
 	callID string
+	// multimodal means the output is the JSON of []schema.ChatMessagePart
+	multimodal bool
 
 	// out
 	executed bool
@@ -204,7 +352,7 @@ type toolCallTask struct {
 	err      error
 }
 
-func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple, input *schema.Message, executedTools map[string]string, isStream bool) ([]toolCallTask, error) {
+func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple, input *schema.Message, opt *toolsNodeOptions, isStream bool) ([]toolCallTask, error) {
 	if input.Role != schema.Assistant {
 		return nil, fmt.Errorf("expected message role is Assistant, got %s", input.Role)
 	}
@@ -214,15 +362,32 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple, in
 		return nil, errors.New("no tool call found in input message")
 	}
 
+	var allowed map[string]bool
+	if tn.allowedToolsGetter != nil {
+		names, err := tn.allowedToolsGetter(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get allowed tools: %w", err)
+		}
+		if names != nil {
+			allowed = make(map[string]bool, len(names))
+			for _, name := range names {
+				allowed[name] = true
+			}
+		}
+	}
+
 	toolCallTasks := make([]toolCallTask, n)
 
 	for i := 0; i < n; i++ {
 		toolCall := input.ToolCalls[i]
-		if result, executed := executedTools[toolCall.ID]; executed {
+		if result, executed := opt.executedTools[toolCall.ID]; executed {
 			toolCallTasks[i].name = toolCall.Function.Name
 			toolCallTasks[i].arg = toolCall.Function.Arguments
 			toolCallTasks[i].callID = toolCall.ID
 			toolCallTasks[i].executed = true
+			if index, ok := tuple.indexes[toolCall.Function.Name]; ok {
+				toolCallTasks[i].multimodal = tuple.multimodal[index]
+			}
 			if isStream {
 				toolCallTasks[i].sOutput = schema.StreamReaderFromArray([]string{result})
 			} else {
@@ -231,6 +396,9 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple, in
 			continue
 		}
 		index, ok := tuple.indexes[toolCall.Function.Name]
+		if ok && allowed != nil {
+			ok = allowed[toolCall.Function.Name]
+		}
 		if !ok {
 			if tn.unknownToolHandler == nil {
 				return nil, fmt.Errorf("tool %s not found in toolsNode indexes", toolCall.Function.Name)
@@ -241,14 +409,46 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple, in
 			toolCallTasks[i].meta = tuple.meta[index]
 			toolCallTasks[i].name = toolCall.Function.Name
 			toolCallTasks[i].callID = toolCall.ID
+			toolCallTasks[i].multimodal = tuple.multimodal[index]
+			arg := toolCall.Function.Arguments
+			if tn.repairArguments {
+				arg = repairToolArguments(ctx, toolCall.Function.Name, arg)
+			}
 			if tn.toolArgumentsHandler != nil {
-				arg, err := tn.toolArgumentsHandler(ctx, toolCall.Function.Name, toolCall.Function.Arguments)
+				var err error
+				arg, err = tn.toolArgumentsHandler(ctx, toolCall.Function.Name, arg)
 				if err != nil {
 					return nil, fmt.Errorf("failed to executed tool[name:%s arguments:%s] arguments handler: %w", toolCall.Function.Name, toolCall.Function.Arguments, err)
 				}
-				toolCallTasks[i].arg = arg
-			} else {
-				toolCallTasks[i].arg = toolCall.Function.Arguments
+			}
+			toolCallTasks[i].arg = arg
+
+			jobID, started := opt.pendingJobs[toolCall.ID]
+			// a started job has been approved
+			if tn.approvalTools != nil && !started {
+				task, gated, err := tn.gateToolCall(ctx, &toolCallTasks[i], opt.approvalDecisions)
+				if err != nil {
+					return nil, err
+				}
+				if gated {
+					toolCallTasks[i] = task
+					continue
+				}
+			}
+
+			if tn.validateArguments {
+				task, invalid, err := tn.validateToolArguments(ctx, tuple.infos[index], &toolCallTasks[i])
+				if err != nil {
+					return nil, err
+				}
+				if invalid {
+					toolCallTasks[i] = task
+					continue
+				}
+			}
+
+			if at := tuple.async[index]; at != nil {
+				toolCallTasks[i] = newAsyncToolTask(&toolCallTasks[i], at, jobID, opt.jobResults)
 			}
 		}
 	}
@@ -290,6 +490,9 @@ func runToolCallTaskByInvoke(ctx context.Context, task *toolCallTask, opts ...to
 }
 
 func runToolCallTaskByStream(ctx context.Context, task *toolCallTask, opts ...tool.Option) {
+	if task.executed {
+		return
+	}
 	ctx = callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
 		Name:      task.name,
 		Type:      task.meta.componentImplType,
@@ -357,15 +560,22 @@ func (tn *ToolsNode) Invoke(ctx context.Context, input *schema.Message,
 		}
 	}
 
-	tasks, err := tn.genToolCallTasks(ctx, tuple, input, opt.executedTools, false)
+	tasks, err := tn.genToolCallTasks(ctx, tuple, input, opt, false)
 	if err != nil {
 		return nil, err
 	}
 
+	run := runToolCallTaskByInvoke
+	if tn.policies != nil {
+		run = tn.policies.wrap(run, false)
+	}
+	if tn.outputLimit != nil {
+		run = tn.outputLimit.wrap(run, false)
+	}
 	if tn.executeSequentially {
-		sequentialRunToolCall(ctx, runToolCallTaskByInvoke, tasks, opt.ToolOptions...)
+		sequentialRunToolCall(ctx, run, tasks, opt.ToolOptions...)
 	} else {
-		parallelRunToolCall(ctx, runToolCallTaskByInvoke, tasks, opt.ToolOptions...)
+		parallelRunToolCall(ctx, run, tasks, opt.ToolOptions...)
 	}
 
 	n := len(tasks)
@@ -383,15 +593,17 @@ func (tn *ToolsNode) Invoke(ctx context.Context, input *schema.Message,
 				return nil, fmt.Errorf("failed to invoke tool[name:%s id:%s]: %w", tasks[i].name, tasks[i].callID, tasks[i].err)
 			}
 			rerun = true
-			rerunExtra.RerunTools = append(rerunExtra.RerunTools, tasks[i].callID)
-			rerunExtra.RerunExtraMap[tasks[i].callID] = extra
+			rerunExtra.addRerunTool(tasks[i].callID, extra)
 			continue
 		}
 		if tasks[i].executed {
 			rerunExtra.ExecutedTools[tasks[i].callID] = tasks[i].output
 		}
 		if !rerun {
-			output[i] = schema.ToolMessage(tasks[i].output, tasks[i].callID, schema.WithToolName(tasks[i].name))
+			output[i], err = newToolMessage(&tasks[i], tasks[i].output)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	if rerun {
@@ -416,15 +628,22 @@ func (tn *ToolsNode) Stream(ctx context.Context, input *schema.Message,
 		}
 	}
 
-	tasks, err := tn.genToolCallTasks(ctx, tuple, input, opt.executedTools, true)
+	tasks, err := tn.genToolCallTasks(ctx, tuple, input, opt, true)
 	if err != nil {
 		return nil, err
 	}
 
+	run := runToolCallTaskByStream
+	if tn.policies != nil {
+		run = tn.policies.wrap(run, true)
+	}
+	if tn.outputLimit != nil {
+		run = tn.outputLimit.wrap(run, true)
+	}
 	if tn.executeSequentially {
-		sequentialRunToolCall(ctx, runToolCallTaskByStream, tasks, opt.ToolOptions...)
+		sequentialRunToolCall(ctx, run, tasks, opt.ToolOptions...)
 	} else {
-		parallelRunToolCall(ctx, runToolCallTaskByStream, tasks, opt.ToolOptions...)
+		parallelRunToolCall(ctx, run, tasks, opt.ToolOptions...)
 	}
 
 	n := len(tasks)
@@ -444,8 +663,7 @@ func (tn *ToolsNode) Stream(ctx context.Context, input *schema.Message,
 				return nil, fmt.Errorf("failed to stream tool call %s: %w", tasks[i].callID, tasks[i].err)
 			}
 			rerun = true
-			rerunExtra.RerunTools = append(rerunExtra.RerunTools, tasks[i].callID)
-			rerunExtra.RerunExtraMap[tasks[i].callID] = extra
+			rerunExtra.addRerunTool(tasks[i].callID, extra)
 			continue
 		}
 	}
@@ -477,6 +695,22 @@ func (tn *ToolsNode) Stream(ctx context.Context, input *schema.Message,
 			return ret, nil
 		}
 
+		if tasks[i].multimodal {
+			// the parts can't be decoded chunk by chunk
+			o, err := concatStreamReader(tasks[i].sOutput)
+			if err != nil {
+				return nil, fmt.Errorf("failed to concat tool[name:%s id:%s]'s stream output: %w", callName, callID, err)
+			}
+			msg, err := newToolMessage(&tasks[i], o)
+			if err != nil {
+				return nil, err
+			}
+			ret := make([]*schema.Message, n)
+			ret[index] = msg
+			sOutput[i] = schema.StreamReaderFromArray([][]*schema.Message{ret})
+			continue
+		}
+
 		sOutput[i] = schema.StreamReaderWithConvert(tasks[i].sOutput, convert)
 	}
 	return schema.MergeStreamReaders(sOutput), nil
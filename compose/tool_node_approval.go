@@ -0,0 +1,152 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mrh997/eino/components"
+	"github.com/mrh997/eino/components/tool"
+)
+
+// ToolApprovalRequest is a tool call waiting for approval, see ToolsNodeConfig.ToolsRequiringApproval.
+// it's the value of ToolsInterruptAndRerunExtra.RerunExtraMap for the call, use ExtractToolApprovalRequests to get them from the InterruptInfo.
+type ToolApprovalRequest struct {
+	CallID    string
+	Name      string
+	Arguments string
+}
+
+// ToolApprovalDecision is the decision on a ToolApprovalRequest, passed by WithToolApprovalDecisions when resuming.
+type ToolApprovalDecision struct {
+	// Approved executes the tool call if true, otherwise the call is rejected and the model gets a tool message with RejectReason instead.
+	Approved bool
+	// RejectReason is the reason of the rejection told to the model.
+	RejectReason string
+	// EditedArguments replaces the arguments of the approved call if not empty.
+	EditedArguments string
+}
+
+// WithToolApprovalDecisions sets the decisions on the tool calls waiting for approval, keyed by call ID.
+// tool calls requiring approval without a decision interrupt again.
+// e.g.
+//
+//	_, err = r.Invoke(ctx, nil, compose.WithCheckPointID(id), compose.WithToolsNodeOption(
+//		compose.WithToolApprovalDecisions(map[string]*compose.ToolApprovalDecision{
+//			"call_1": {Approved: true},
+//			"call_2": {RejectReason: "not allowed to delete files"},
+//		}),
+//	))
+func WithToolApprovalDecisions(decisions map[string]*ToolApprovalDecision) ToolsNodeOption {
+	return func(o *toolsNodeOptions) {
+		o.approvalDecisions = decisions
+	}
+}
+
+// ExtractToolApprovalRequests returns the tool calls waiting for approval in the interrupt info, including those of subgraphs.
+func ExtractToolApprovalRequests(info *InterruptInfo) []*ToolApprovalRequest {
+	if info == nil {
+		return nil
+	}
+
+	var ret []*ToolApprovalRequest
+	for _, node := range info.RerunNodes {
+		extra, ok := info.RerunNodesExtra[node].(*ToolsInterruptAndRerunExtra)
+		if !ok {
+			continue
+		}
+		for _, callID := range extra.RerunTools {
+			if req, ok := extra.RerunExtraMap[callID].(*ToolApprovalRequest); ok {
+				ret = append(ret, req)
+			}
+		}
+	}
+
+	subGraphs := make([]string, 0, len(info.SubGraphs))
+	for key := range info.SubGraphs {
+		subGraphs = append(subGraphs, key)
+	}
+	sort.Strings(subGraphs)
+	for _, key := range subGraphs {
+		ret = append(ret, ExtractToolApprovalRequests(info.SubGraphs[key])...)
+	}
+
+	return ret
+}
+
+func defaultToolRejectionHandler(_ context.Context, name, _, reason string) (string, error) {
+	if reason == "" {
+		return fmt.Sprintf("the call of tool %s is rejected by the user", name), nil
+	}
+	return fmt.Sprintf("the call of tool %s is rejected by the user, reason: %s", name, reason), nil
+}
+
+// gateToolCall checks the task of a tool requiring approval against the decisions,
+// it returns a task interrupting for approval if there is no decision, a task outputting the rejection if rejected,
+// and false if approved, in which case the arguments of the task may have been edited.
+func (tn *ToolsNode) gateToolCall(ctx context.Context, task *toolCallTask, decisions map[string]*ToolApprovalDecision) (toolCallTask, bool, error) {
+	if !tn.approvalTools[task.name] {
+		return toolCallTask{}, false, nil
+	}
+
+	decision, ok := decisions[task.callID]
+	if !ok || decision == nil {
+		req := &ToolApprovalRequest{
+			CallID:    task.callID,
+			Name:      task.name,
+			Arguments: task.arg,
+		}
+		return newToolGateTask(task, "ApprovalRequired", func(ctx context.Context, input string, opts ...tool.Option) (string, error) {
+			return "", NewInterruptAndRerunErr(req)
+		}), true, nil
+	}
+
+	if decision.Approved {
+		if decision.EditedArguments != "" {
+			task.arg = decision.EditedArguments
+		}
+		return toolCallTask{}, false, nil
+	}
+
+	handler := tn.rejectionHandler
+	if handler == nil {
+		handler = defaultToolRejectionHandler
+	}
+	output, err := handler(ctx, task.name, task.arg, decision.RejectReason)
+	if err != nil {
+		return toolCallTask{}, false, fmt.Errorf("failed to handle rejection of tool[name:%s arguments:%s]: %w", task.name, task.arg, err)
+	}
+	return newToolGateTask(task, "Rejected", func(ctx context.Context, input string, opts ...tool.Option) (string, error) {
+		return output, nil
+	}), true, nil
+}
+
+func newToolGateTask(task *toolCallTask, implType string, run func(ctx context.Context, input string, opts ...tool.Option) (string, error)) toolCallTask {
+	return toolCallTask{
+		r: newRunnablePacker(run, nil, nil, nil, false),
+		meta: &executorMeta{
+			component:                  components.ComponentOfTool,
+			isComponentCallbackEnabled: false,
+			componentImplType:          implType,
+		},
+		name:   task.name,
+		arg:    task.arg,
+		callID: task.callID,
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+type countingTool struct {
+	name  string
+	calls int
+}
+
+func (c *countingTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: c.name}, nil
+}
+
+func (c *countingTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	c.calls++
+	return c.name + ": " + argumentsInJSON + ";", nil
+}
+
+func TestToolApproval(t *testing.T) {
+	type approvalState struct {
+		In *schema.Message
+	}
+	assert.NoError(t, RegisterSerializableType[approvalState]("_tool_approval_state"))
+
+	tc := []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "search", Arguments: "a"}},
+		{ID: "2", Function: schema.FunctionCall{Name: "delete", Arguments: "b"}},
+		{ID: "3", Function: schema.FunctionCall{Name: "pay", Arguments: "c"}},
+	}
+
+	for _, stream := range []bool{false, true} {
+		search, del, pay := &countingTool{name: "search"}, &countingTool{name: "delete"}, &countingTool{name: "pay"}
+
+		ctx := context.Background()
+		g := NewGraph[*schema.Message, string](WithGenLocalState(func(ctx context.Context) *approvalState {
+			return &approvalState{}
+		}))
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:                  []tool.BaseTool{search, del, pay},
+			ToolsRequiringApproval: []string{"delete", "pay"},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, g.AddToolsNode("tools", tn, WithStatePreHandler(func(ctx context.Context, in *schema.Message, state *approvalState) (*schema.Message, error) {
+			if in != nil {
+				state.In = in
+			}
+			return state.In, nil
+		})))
+		assert.NoError(t, g.AddLambdaNode("concat", InvokableLambda(func(ctx context.Context, input []*schema.Message) (string, error) {
+			sb := strings.Builder{}
+			for _, m := range input {
+				sb.WriteString(m.Content)
+			}
+			return sb.String(), nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "tools"))
+		assert.NoError(t, g.AddEdge("tools", "concat"))
+		assert.NoError(t, g.AddEdge("concat", END))
+
+		r, err := g.Compile(ctx, WithCheckPointStore(&inMemoryStore{m: map[string][]byte{}}))
+		assert.NoError(t, err)
+
+		run := func(in *schema.Message, opts ...Option) (string, error) {
+			if !stream {
+				return r.Invoke(ctx, in, opts...)
+			}
+			sr, err := r.Stream(ctx, in, opts...)
+			if err != nil {
+				return "", err
+			}
+			return concatStreamReader(sr)
+		}
+
+		_, err = run(&schema.Message{Role: schema.Assistant, ToolCalls: tc}, WithCheckPointID("1"))
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, []*ToolApprovalRequest{
+			{CallID: "2", Name: "delete", Arguments: "b"},
+			{CallID: "3", Name: "pay", Arguments: "c"},
+		}, ExtractToolApprovalRequests(info))
+		assert.Equal(t, map[string]string{"1": "search: a;"}, info.RerunNodesExtra["tools"].(*ToolsInterruptAndRerunExtra).ExecutedTools)
+
+		// the call without decision interrupts again
+		_, err = run(nil, WithCheckPointID("1"), WithToolsNodeOption(WithToolApprovalDecisions(map[string]*ToolApprovalDecision{
+			"2": {RejectReason: "not allowed"},
+		})))
+		info, ok = ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, []*ToolApprovalRequest{{CallID: "3", Name: "pay", Arguments: "c"}}, ExtractToolApprovalRequests(info))
+
+		result, err := run(nil, WithCheckPointID("1"), WithToolsNodeOption(WithToolApprovalDecisions(map[string]*ToolApprovalDecision{
+			"3": {Approved: true, EditedArguments: "d"},
+		})))
+		assert.NoError(t, err)
+		assert.Equal(t, "search: a;the call of tool delete is rejected by the user, reason: not allowedpay: d;", result)
+		assert.Equal(t, 1, search.calls)
+		assert.Equal(t, 0, del.calls)
+		assert.Equal(t, 1, pay.calls)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mrh997/eino/components/tool"
+)
+
+// PendingToolJob is a job started by a tool.AsyncTool and not done yet.
+// it's the value of ToolsInterruptAndRerunExtra.RerunExtraMap for the call, use ExtractPendingToolJobs to get them from the InterruptInfo.
+// the job IDs are saved in the checkpoint, resume the graph with the same checkpoint ID when the jobs are done,
+// the ToolsNode gets the results by tool.AsyncTool.JobResult, or uses the results passed by WithToolJobResults.
+type PendingToolJob struct {
+	CallID    string
+	Name      string
+	Arguments string
+	JobID     string
+}
+
+// WithToolJobResults sets the results of the jobs started by tool.AsyncTool, keyed by job ID,
+// e.g. passed by an external callback notified when the jobs complete.
+// the results are used as the tool messages without calling tool.AsyncTool.JobResult.
+// e.g.
+//
+//	_, err = r.Invoke(ctx, nil, compose.WithCheckPointID(id), compose.WithToolsNodeOption(
+//		compose.WithToolJobResults(map[string]string{"job_1": report}),
+//	))
+func WithToolJobResults(results map[string]string) ToolsNodeOption {
+	return func(o *toolsNodeOptions) {
+		o.jobResults = results
+	}
+}
+
+// ExtractPendingToolJobs returns the pending jobs of async tools in the interrupt info, including those of subgraphs.
+func ExtractPendingToolJobs(info *InterruptInfo) []*PendingToolJob {
+	if info == nil {
+		return nil
+	}
+
+	var ret []*PendingToolJob
+	for _, node := range info.RerunNodes {
+		extra, ok := info.RerunNodesExtra[node].(*ToolsInterruptAndRerunExtra)
+		if !ok {
+			continue
+		}
+		for _, callID := range extra.RerunTools {
+			if job, ok := extra.RerunExtraMap[callID].(*PendingToolJob); ok {
+				ret = append(ret, job)
+			}
+		}
+	}
+
+	subGraphs := make([]string, 0, len(info.SubGraphs))
+	for key := range info.SubGraphs {
+		subGraphs = append(subGraphs, key)
+	}
+	sort.Strings(subGraphs)
+	for _, key := range subGraphs {
+		ret = append(ret, ExtractPendingToolJobs(info.SubGraphs[key])...)
+	}
+
+	return ret
+}
+
+// ToolJobPollerConfig is the config of PollToolJobs.
+type ToolJobPollerConfig struct {
+	// Interval is the time waited before each resume.
+	// Optional. Default 1 second.
+	Interval time.Duration
+
+	// MaxPolls is the max number of resumes, the last interrupt error is returned when the jobs are still pending after them.
+	// Optional. Default 0, polling until the jobs are done or ctx is done.
+	MaxPolls int
+}
+
+const defaultToolJobPollInterval = time.Second
+
+// PollToolJobs polls the pending jobs of async tools by resuming the graph interrupted by them with the checkpoint ID,
+// the ToolsNode gets the status of the jobs by tool.AsyncTool.JobResult, and interrupts again if any of them is not done,
+// in which case the graph is resumed again after the interval, until it completes or interrupts for other reasons.
+// the graph is resumed with the zero value of I as input, and opts are passed to every resume, besides WithCheckPointID.
+// e.g.
+//
+//	_, err := r.Invoke(ctx, input, compose.WithCheckPointID(id))
+//	if info, ok := compose.ExtractInterruptInfo(err); ok && len(compose.ExtractPendingToolJobs(info)) > 0 {
+//		output, err = compose.PollToolJobs(ctx, r, id, &compose.ToolJobPollerConfig{Interval: 5 * time.Second})
+//	}
+func PollToolJobs[I, O any](ctx context.Context, r Runnable[I, O], checkPointID string, conf *ToolJobPollerConfig, opts ...Option) (output O, err error) {
+	interval, maxPolls := defaultToolJobPollInterval, 0
+	if conf != nil {
+		if conf.Interval > 0 {
+			interval = conf.Interval
+		}
+		maxPolls = conf.MaxPolls
+	}
+	opts = append(opts, WithCheckPointID(checkPointID))
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for polls := 1; ; polls++ {
+		select {
+		case <-ctx.Done():
+			return output, ctx.Err()
+		case <-timer.C:
+		}
+
+		var input I
+		output, err = r.Invoke(ctx, input, opts...)
+		info, ok := ExtractInterruptInfo(err)
+		if !ok || len(ExtractPendingToolJobs(info)) == 0 {
+			return output, err
+		}
+		if maxPolls > 0 && polls >= maxPolls {
+			return output, err
+		}
+		timer.Reset(interval)
+	}
+}
+
+// newAsyncToolTask returns the task of an async tool call, which starts the job if jobID is empty,
+// and returns the job result if done, or interrupts with the PendingToolJob otherwise.
+func newAsyncToolTask(task *toolCallTask, at tool.AsyncTool, jobID string, results map[string]string) toolCallTask {
+	name, callID := task.name, task.callID
+	run := func(ctx context.Context, input string, opts ...tool.Option) (string, error) {
+		job := &PendingToolJob{
+			CallID:    callID,
+			Name:      name,
+			Arguments: input,
+			JobID:     jobID,
+		}
+		if job.JobID == "" {
+			id, err := at.StartJob(ctx, input, opts...)
+			if err != nil {
+				return "", fmt.Errorf("failed to start job of tool[name:%s arguments:%s]: %w", name, input, err)
+			}
+			job.JobID = id
+			return "", NewInterruptAndRerunErr(job)
+		}
+
+		if result, ok := results[job.JobID]; ok {
+			return result, nil
+		}
+		result, done, err := at.JobResult(ctx, job.JobID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get job result of tool[name:%s job:%s]: %w", name, job.JobID, err)
+		}
+		if !done {
+			return "", NewInterruptAndRerunErr(job)
+		}
+		return result, nil
+	}
+
+	return toolCallTask{
+		r:      newRunnablePacker(run, nil, nil, nil, false),
+		meta:   task.meta,
+		name:   task.name,
+		arg:    task.arg,
+		callID: task.callID,
+	}
+}
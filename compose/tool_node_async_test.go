@@ -0,0 +1,203 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+type asyncTestTool struct {
+	name    string
+	started int
+	polls   int
+	done    map[string]string
+	// doneAfterPolls makes the jobs done after the number of polls if positive
+	doneAfterPolls int
+}
+
+func (a *asyncTestTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: a.name}, nil
+}
+
+func (a *asyncTestTool) StartJob(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	a.started++
+	return fmt.Sprintf("%s-job-%d", a.name, a.started), nil
+}
+
+func (a *asyncTestTool) JobResult(ctx context.Context, jobID string) (string, bool, error) {
+	a.polls++
+	if a.doneAfterPolls > 0 && a.polls >= a.doneAfterPolls {
+		return jobID + " done", true, nil
+	}
+	result, ok := a.done[jobID]
+	return result, ok, nil
+}
+
+func TestAsyncTool(t *testing.T) {
+	type asyncState struct {
+		In *schema.Message
+	}
+	assert.NoError(t, RegisterSerializableType[asyncState]("_tool_async_state"))
+
+	tc := []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "search", Arguments: "a"}},
+		{ID: "2", Function: schema.FunctionCall{Name: "report", Arguments: "b"}},
+		{ID: "3", Function: schema.FunctionCall{Name: "ci", Arguments: "c"}},
+	}
+
+	for _, stream := range []bool{false, true} {
+		search := &countingTool{name: "search"}
+		report := &asyncTestTool{name: "report", done: map[string]string{}}
+		ci := &asyncTestTool{name: "ci", done: map[string]string{}}
+
+		ctx := context.Background()
+		g := NewGraph[*schema.Message, string](WithGenLocalState(func(ctx context.Context) *asyncState {
+			return &asyncState{}
+		}))
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{search, report, ci}})
+		assert.NoError(t, err)
+		assert.NoError(t, g.AddToolsNode("tools", tn, WithStatePreHandler(func(ctx context.Context, in *schema.Message, state *asyncState) (*schema.Message, error) {
+			if in != nil {
+				state.In = in
+			}
+			return state.In, nil
+		})))
+		assert.NoError(t, g.AddLambdaNode("concat", InvokableLambda(func(ctx context.Context, input []*schema.Message) (string, error) {
+			sb := strings.Builder{}
+			for _, m := range input {
+				sb.WriteString(m.Content + ";")
+			}
+			return sb.String(), nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "tools"))
+		assert.NoError(t, g.AddEdge("tools", "concat"))
+		assert.NoError(t, g.AddEdge("concat", END))
+
+		r, err := g.Compile(ctx, WithCheckPointStore(&inMemoryStore{m: map[string][]byte{}}))
+		assert.NoError(t, err)
+
+		run := func(in *schema.Message, opts ...Option) (string, error) {
+			if !stream {
+				return r.Invoke(ctx, in, opts...)
+			}
+			sr, err := r.Stream(ctx, in, opts...)
+			if err != nil {
+				return "", err
+			}
+			return concatStreamReader(sr)
+		}
+
+		_, err = run(&schema.Message{Role: schema.Assistant, ToolCalls: tc}, WithCheckPointID("1"))
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, []*PendingToolJob{
+			{CallID: "2", Name: "report", Arguments: "b", JobID: "report-job-1"},
+			{CallID: "3", Name: "ci", Arguments: "c", JobID: "ci-job-1"},
+		}, ExtractPendingToolJobs(info))
+		assert.Equal(t, map[string]string{"2": "report-job-1", "3": "ci-job-1"}, info.RerunNodesExtra["tools"].(*ToolsInterruptAndRerunExtra).PendingJobs)
+
+		// the jobs not done interrupt again without restarting
+		report.done["report-job-1"] = "report done"
+		_, err = run(nil, WithCheckPointID("1"))
+		info, ok = ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, []*PendingToolJob{{CallID: "3", Name: "ci", Arguments: "c", JobID: "ci-job-1"}}, ExtractPendingToolJobs(info))
+		assert.Equal(t, 1, report.started)
+		assert.Equal(t, 1, ci.started)
+
+		// the result passed by the external callback is used without polling
+		result, err := run(nil, WithCheckPointID("1"), WithToolsNodeOption(WithToolJobResults(map[string]string{"ci-job-1": "ci passed"})))
+		assert.NoError(t, err)
+		assert.Equal(t, "search: a;;report done;ci passed;", result)
+		assert.Equal(t, 1, search.calls)
+		assert.Equal(t, 1, report.polls)
+		assert.Equal(t, 1, ci.polls)
+	}
+}
+
+func TestPollToolJobs(t *testing.T) {
+	ctx := context.Background()
+	tc := []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "report", Arguments: "a"}}}
+
+	type pollState struct {
+		In *schema.Message
+	}
+	assert.NoError(t, RegisterSerializableType[pollState]("_tool_poll_state"))
+
+	newRunnable := func(at *asyncTestTool) Runnable[*schema.Message, []*schema.Message] {
+		g := NewGraph[*schema.Message, []*schema.Message](WithGenLocalState(func(ctx context.Context) *pollState {
+			return &pollState{}
+		}))
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{at}})
+		assert.NoError(t, err)
+		assert.NoError(t, g.AddToolsNode("tools", tn, WithStatePreHandler(func(ctx context.Context, in *schema.Message, state *pollState) (*schema.Message, error) {
+			if in != nil {
+				state.In = in
+			}
+			return state.In, nil
+		})))
+		assert.NoError(t, g.AddEdge(START, "tools"))
+		assert.NoError(t, g.AddEdge("tools", END))
+		r, err := g.Compile(ctx, WithCheckPointStore(&inMemoryStore{m: map[string][]byte{}}))
+		assert.NoError(t, err)
+		return r
+	}
+
+	t.Run("done", func(t *testing.T) {
+		report := &asyncTestTool{name: "report", doneAfterPolls: 3}
+		r := newRunnable(report)
+		_, err := r.Invoke(ctx, &schema.Message{Role: schema.Assistant, ToolCalls: tc}, WithCheckPointID("1"))
+		_, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+
+		out, err := PollToolJobs(ctx, r, "1", &ToolJobPollerConfig{Interval: time.Millisecond})
+		assert.NoError(t, err)
+		assert.Len(t, out, 1)
+		assert.Equal(t, "report-job-1 done", out[0].Content)
+		assert.Equal(t, 3, report.polls)
+		assert.Equal(t, 1, report.started)
+	})
+
+	t.Run("max polls", func(t *testing.T) {
+		report := &asyncTestTool{name: "report", done: map[string]string{}}
+		r := newRunnable(report)
+		_, err := r.Invoke(ctx, &schema.Message{Role: schema.Assistant, ToolCalls: tc}, WithCheckPointID("1"))
+		_, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+
+		_, err = PollToolJobs(ctx, r, "1", &ToolJobPollerConfig{Interval: time.Millisecond, MaxPolls: 2})
+		info, ok := ExtractInterruptInfo(err)
+		assert.True(t, ok)
+		assert.Equal(t, []*PendingToolJob{{CallID: "1", Name: "report", Arguments: "a", JobID: "report-job-1"}}, ExtractPendingToolJobs(info))
+		assert.Equal(t, 2, report.polls)
+
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err = PollToolJobs(cctx, r, "1", nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 2, report.polls)
+	})
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+// multimodalRunToInvokable runs the MultimodalTool as an invokable tool outputting the JSON of the parts,
+// so that the parts go through callbacks and checkpoints like the outputs of other tools.
+func multimodalRunToInvokable(mt tool.MultimodalTool) func(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return func(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+		parts, err := mt.MultimodalRun(ctx, argumentsInJSON, opts...)
+		if err != nil {
+			return "", err
+		}
+		return sonic.MarshalString(parts)
+	}
+}
+
+func newToolMessage(task *toolCallTask, output string) (*schema.Message, error) {
+	if !task.multimodal {
+		return schema.ToolMessage(output, task.callID, schema.WithToolName(task.name)), nil
+	}
+
+	var parts []schema.ChatMessagePart
+	if err := sonic.UnmarshalString(output, &parts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal multimodal output of tool[name:%s id:%s]: %w", task.name, task.callID, err)
+	}
+	msg := schema.ToolMessage("", task.callID, schema.WithToolName(task.name))
+	msg.MultiContent = parts
+	return msg, nil
+}
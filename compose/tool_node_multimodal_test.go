@@ -0,0 +1,79 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/callbacks"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/components/tool/utils"
+	"github.com/mrh997/eino/schema"
+)
+
+type chartInput struct {
+	Title string `json:"title"`
+}
+
+func TestMultimodalToolResult(t *testing.T) {
+	ctx := context.Background()
+	parts := []schema.ChatMessagePart{
+		{Type: schema.ChatMessagePartTypeText, Text: "sales"},
+		{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: "data:image/png;base64,AAAA"}},
+	}
+	chart, err := utils.InferTool("render_chart", "render a chart", func(ctx context.Context, in *chartInput) ([]schema.ChatMessagePart, error) {
+		return parts, nil
+	})
+	assert.NoError(t, err)
+	text := &policyTestTool{name: "text", run: func(ctx context.Context, calls int) (string, error) {
+		return "plain", nil
+	}}
+
+	var callbackOutput string
+	handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+		if info.Name == "render_chart" {
+			callbackOutput = tool.ConvCallbackOutput(output).Response
+		}
+		return ctx
+	}).Build()
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{chart, text}})
+	assert.NoError(t, err)
+
+	input := &schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "render_chart", Arguments: `{"title":"sales"}`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "text", Arguments: `{}`}},
+	}}
+	expected := []*schema.Message{
+		{Role: schema.Tool, ToolCallID: "1", ToolName: "render_chart", MultiContent: parts},
+		schema.ToolMessage("plain", "2", schema.WithToolName("text")),
+	}
+
+	msgs, err := tn.Invoke(callbacks.InitCallbacks(ctx, nil, handler), input)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, msgs)
+	assert.JSONEq(t, `[{"type":"text","text":"sales"},{"type":"image_url","image_url":{"url":"data:image/png;base64,AAAA"}}]`, callbackOutput)
+
+	sr, err := tn.Stream(ctx, input)
+	assert.NoError(t, err)
+	msgs, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, msgs)
+}
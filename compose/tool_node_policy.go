@@ -0,0 +1,283 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/internal/safe"
+	"github.com/mrh997/eino/schema"
+)
+
+// ErrToolTimeout is the error of a tool call that doesn't finish within ToolPolicy.Timeout.
+var ErrToolTimeout = errors.New("tool call timeout")
+
+const (
+	defaultToolBackoff    = 100 * time.Millisecond
+	defaultToolMaxBackoff = 10 * time.Second
+)
+
+// ToolPolicy is the execution policy of a tool in ToolsNode, see ToolsNodeConfig.ToolPolicies.
+type ToolPolicy struct {
+	// Timeout limits the time of each attempt of the tool call, the attempt fails with ErrToolTimeout when exceeded.
+	// for a StreamableTool, it limits the time to get the stream, rather than to read it.
+	// Optional. Default no timeout.
+	Timeout time.Duration
+
+	// MaxRetries is the max number of retries after the tool call fails or times out.
+	// interrupt errors are never retried.
+	// Optional. Default 0, no retry.
+	MaxRetries int
+
+	// Backoff returns the time to wait before the attempt-th retry, attempt starts from 1.
+	// Optional. Default exponential backoff starting from 100ms, at most 10s.
+	Backoff func(ctx context.Context, attempt int) time.Duration
+
+	// MaxConcurrency limits the number of concurrent calls of the tool, shared by all executions of the ToolsNode.
+	// calls exceeding the limit wait for others to finish, for a StreamableTool, a call finishes when the stream is got.
+	// Optional. Default 0, no limit.
+	MaxConcurrency int
+
+	// ExhaustedHandler builds the content of the tool message returned to the model when the tool call still fails after all retries,
+	// so that the model can go on without the result, e.g. "the weather service is busy, try again later".
+	// Optional. By default, the error is returned and the ToolsNode fails.
+	ExhaustedHandler func(ctx context.Context, name, arguments string, err error) (string, error)
+}
+
+type toolPolicies struct {
+	policies      map[string]*ToolPolicy
+	defaultPolicy *ToolPolicy
+	overallSem    chan struct{}
+
+	mu       sync.Mutex
+	toolSems map[string]chan struct{}
+}
+
+func newToolPolicies(conf *ToolsNodeConfig) *toolPolicies {
+	if len(conf.ToolPolicies) == 0 && conf.DefaultToolPolicy == nil && conf.MaxConcurrentToolCalls <= 0 {
+		return nil
+	}
+
+	tp := &toolPolicies{
+		policies:      conf.ToolPolicies,
+		defaultPolicy: conf.DefaultToolPolicy,
+		toolSems:      make(map[string]chan struct{}),
+	}
+	if conf.MaxConcurrentToolCalls > 0 {
+		tp.overallSem = make(chan struct{}, conf.MaxConcurrentToolCalls)
+	}
+	return tp
+}
+
+func (tp *toolPolicies) policyOf(name string) *ToolPolicy {
+	if p, ok := tp.policies[name]; ok && p != nil {
+		return p
+	}
+	if tp.defaultPolicy != nil {
+		return tp.defaultPolicy
+	}
+	return &ToolPolicy{}
+}
+
+// semaphoreOf returns the semaphore limiting the concurrency of the tool, nil if unlimited.
+// it's created on first use, as tools can also be set by WithToolList.
+func (tp *toolPolicies) semaphoreOf(name string, p *ToolPolicy) chan struct{} {
+	if p.MaxConcurrency <= 0 {
+		return nil
+	}
+
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	sem, ok := tp.toolSems[name]
+	if !ok {
+		sem = make(chan struct{}, p.MaxConcurrency)
+		tp.toolSems[name] = sem
+	}
+	return sem
+}
+
+func acquireToolSemaphore(ctx context.Context, sem chan struct{}) error {
+	if sem == nil {
+		return nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func releaseToolSemaphore(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+}
+
+func defaultToolBackoffFunc(_ context.Context, attempt int) time.Duration {
+	d := defaultToolBackoff
+	for i := 1; i < attempt && d < defaultToolMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > defaultToolMaxBackoff {
+		d = defaultToolMaxBackoff
+	}
+	return d
+}
+
+// wrap returns the run function applying the policies to each tool call task.
+func (tp *toolPolicies) wrap(run func(ctx context.Context, task *toolCallTask, opts ...tool.Option), isStream bool) func(ctx context.Context, task *toolCallTask, opts ...tool.Option) {
+	return func(ctx context.Context, task *toolCallTask, opts ...tool.Option) {
+		if task.executed {
+			return
+		}
+
+		p := tp.policyOf(task.name)
+		toolSem := tp.semaphoreOf(task.name, p)
+
+		err := tp.runWithRetry(ctx, p, toolSem, run, task, isStream, opts...)
+		if err == nil {
+			return
+		}
+		if _, ok := IsInterruptRerunError(err); ok || p.ExhaustedHandler == nil {
+			task.err = err
+			return
+		}
+
+		output, hErr := p.ExhaustedHandler(ctx, task.name, task.arg, err)
+		if hErr != nil {
+			task.err = fmt.Errorf("failed to handle exhausted tool[name:%s arguments:%s]: %w", task.name, task.arg, hErr)
+			return
+		}
+		task.err = nil
+		task.executed = true
+		task.multimodal = false
+		if isStream {
+			task.sOutput = schema.StreamReaderFromArray([]string{output})
+		} else {
+			task.output = output
+		}
+	}
+}
+
+func (tp *toolPolicies) runWithRetry(ctx context.Context, p *ToolPolicy, toolSem chan struct{},
+	run func(ctx context.Context, task *toolCallTask, opts ...tool.Option), task *toolCallTask, isStream bool, opts ...tool.Option) error {
+
+	backoff := p.Backoff
+	if backoff == nil {
+		backoff = defaultToolBackoffFunc
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff(ctx, attempt))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+
+		err := tp.runOnce(ctx, p, toolSem, run, task, isStream, opts...)
+		if err == nil {
+			return nil
+		}
+		if _, ok := IsInterruptRerunError(err); ok || attempt >= p.MaxRetries || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+func (tp *toolPolicies) runOnce(ctx context.Context, p *ToolPolicy, toolSem chan struct{},
+	run func(ctx context.Context, task *toolCallTask, opts ...tool.Option), task *toolCallTask, isStream bool, opts ...tool.Option) error {
+
+	if err := acquireToolSemaphore(ctx, tp.overallSem); err != nil {
+		return err
+	}
+	if err := acquireToolSemaphore(ctx, toolSem); err != nil {
+		releaseToolSemaphore(tp.overallSem)
+		return err
+	}
+	release := func() {
+		releaseToolSemaphore(toolSem)
+		releaseToolSemaphore(tp.overallSem)
+	}
+
+	if p.Timeout <= 0 {
+		defer release()
+		task.err = nil
+		run(ctx, task, opts...)
+		return task.err
+	}
+
+	// the attempt runs on a copy of the task, so that a tool ignoring the context can be abandoned safely
+	attempt := *task
+	attempt.err = nil
+	// the context of a stream is not canceled, as the stream is read after the attempt returns
+	attemptCtx := ctx
+	cancel := func() {}
+	if !isStream {
+		attemptCtx, cancel = context.WithTimeout(ctx, p.Timeout)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				attempt.err = safe.NewPanicErr(panicErr, debug.Stack())
+			}
+		}()
+		run(attemptCtx, &attempt, opts...)
+	}()
+
+	// an abandoned attempt keeps its slots of the concurrency limits until it actually returns,
+	// and the stream it returns late is closed as nobody reads it.
+	abandon := func() {
+		go func() {
+			<-done
+			if attempt.sOutput != nil {
+				attempt.sOutput.Close()
+			}
+			release()
+		}()
+	}
+
+	timer := time.NewTimer(p.Timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		cancel()
+		release()
+		*task = attempt
+		return task.err
+	case <-timer.C:
+		cancel()
+		abandon()
+		return fmt.Errorf("%w: tool[name:%s] exceeds %s", ErrToolTimeout, task.name, p.Timeout)
+	case <-ctx.Done():
+		cancel()
+		abandon()
+		return ctx.Err()
+	}
+}
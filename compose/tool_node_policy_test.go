@@ -0,0 +1,259 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+type policyTestTool struct {
+	name string
+	run  func(ctx context.Context, calls int) (string, error)
+
+	mu      sync.Mutex
+	calls   int
+	running int
+	peak    int
+}
+
+func (p *policyTestTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: p.name}, nil
+}
+
+func (p *policyTestTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	p.mu.Lock()
+	p.calls++
+	calls := p.calls
+	p.running++
+	if p.running > p.peak {
+		p.peak = p.running
+	}
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.running--
+		p.mu.Unlock()
+	}()
+	return p.run(ctx, calls)
+}
+
+type lateStreamTool struct {
+	closed chan bool
+}
+
+func (l *lateStreamTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "late"}, nil
+}
+
+func (l *lateStreamTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	// ignores the context
+	time.Sleep(30 * time.Millisecond)
+	sr, sw := schema.Pipe[string](1)
+	go func() {
+		defer sw.Close()
+		for i := 0; i < 100; i++ {
+			if sw.Send("chunk", nil) {
+				l.closed <- true
+				return
+			}
+		}
+		l.closed <- false
+	}()
+	return sr, nil
+}
+
+func toolCallsMessage(names ...string) *schema.Message {
+	msg := &schema.Message{Role: schema.Assistant}
+	for i, name := range names {
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			ID:       string(rune('a' + i)),
+			Function: schema.FunctionCall{Name: name, Arguments: "{}"},
+		})
+	}
+	return msg
+}
+
+func TestToolPolicies(t *testing.T) {
+	ctx := context.Background()
+	noBackoff := func(ctx context.Context, attempt int) time.Duration { return 0 }
+
+	t.Run("retry", func(t *testing.T) {
+		flaky := &policyTestTool{name: "flaky", run: func(ctx context.Context, calls int) (string, error) {
+			if calls < 3 {
+				return "", errors.New("unavailable")
+			}
+			return "ok", nil
+		}}
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:        []tool.BaseTool{flaky},
+			ToolPolicies: map[string]*ToolPolicy{"flaky": {MaxRetries: 2, Backoff: noBackoff}},
+		})
+		assert.NoError(t, err)
+
+		out, err := tn.Invoke(ctx, toolCallsMessage("flaky"))
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", out[0].Content)
+		assert.Equal(t, 3, flaky.calls)
+
+		// the error is returned when the retries are exhausted without ExhaustedHandler
+		tn, err = NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:             []tool.BaseTool{flaky},
+			DefaultToolPolicy: &ToolPolicy{MaxRetries: 1, Backoff: noBackoff},
+		})
+		assert.NoError(t, err)
+		flaky.calls = 0
+		_, err = tn.Invoke(ctx, toolCallsMessage("flaky"))
+		assert.ErrorContains(t, err, "unavailable")
+		assert.Equal(t, 2, flaky.calls)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		slow := &policyTestTool{name: "slow", run: func(ctx context.Context, calls int) (string, error) {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Second):
+				return "late", nil
+			}
+		}}
+		var handled error
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools: []tool.BaseTool{slow},
+			ToolPolicies: map[string]*ToolPolicy{"slow": {
+				Timeout:    10 * time.Millisecond,
+				MaxRetries: 1,
+				Backoff:    noBackoff,
+				ExhaustedHandler: func(ctx context.Context, name, arguments string, err error) (string, error) {
+					handled = err
+					return name + " is busy", nil
+				},
+			}},
+		})
+		assert.NoError(t, err)
+
+		out, err := tn.Invoke(ctx, toolCallsMessage("slow"))
+		assert.NoError(t, err)
+		assert.Equal(t, "slow is busy", out[0].Content)
+		assert.True(t, errors.Is(handled, ErrToolTimeout))
+		assert.Equal(t, 2, slow.calls)
+
+		sr, err := tn.Stream(ctx, toolCallsMessage("slow"))
+		assert.NoError(t, err)
+		msgs, err := concatStreamReader(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "slow is busy", msgs[0].Content)
+	})
+
+	t.Run("abandoned attempts keep the concurrency slots", func(t *testing.T) {
+		stubborn := &policyTestTool{name: "stubborn", run: func(ctx context.Context, calls int) (string, error) {
+			// ignores the context
+			time.Sleep(30 * time.Millisecond)
+			return "late", nil
+		}}
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools: []tool.BaseTool{stubborn},
+			ToolPolicies: map[string]*ToolPolicy{"stubborn": {
+				Timeout:        10 * time.Millisecond,
+				MaxRetries:     1,
+				Backoff:        noBackoff,
+				MaxConcurrency: 1,
+			}},
+		})
+		assert.NoError(t, err)
+
+		_, err = tn.Invoke(ctx, toolCallsMessage("stubborn"))
+		assert.True(t, errors.Is(err, ErrToolTimeout))
+		stubborn.mu.Lock()
+		defer stubborn.mu.Unlock()
+		// the retry starts after the abandoned attempt returns
+		assert.Equal(t, 2, stubborn.calls)
+		assert.Equal(t, 1, stubborn.peak)
+	})
+
+	t.Run("late streams are closed", func(t *testing.T) {
+		closed := make(chan bool, 1)
+		late := &lateStreamTool{closed: closed}
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:        []tool.BaseTool{late},
+			ToolPolicies: map[string]*ToolPolicy{"late": {Timeout: 10 * time.Millisecond}},
+		})
+		assert.NoError(t, err)
+
+		_, err = tn.Stream(ctx, toolCallsMessage("late"))
+		assert.True(t, errors.Is(err, ErrToolTimeout))
+		select {
+		case c := <-closed:
+			assert.True(t, c)
+		case <-time.After(time.Second):
+			t.Fatal("the late stream is not closed")
+		}
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		var mu sync.Mutex
+		running, peak := 0, 0
+		run := func(ctx context.Context, calls int) (string, error) {
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return "done", nil
+		}
+		limited := &policyTestTool{name: "limited", run: run}
+		other := &policyTestTool{name: "other", run: run}
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:                  []tool.BaseTool{limited, other},
+			ToolPolicies:           map[string]*ToolPolicy{"limited": {MaxConcurrency: 1}},
+			MaxConcurrentToolCalls: 3,
+		})
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := tn.Invoke(ctx, toolCallsMessage("limited", "limited", "other", "other", "other"))
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 4, limited.calls)
+		assert.Equal(t, 1, limited.peak)
+		assert.Equal(t, 6, other.calls)
+		assert.LessOrEqual(t, peak, 3)
+	})
+}
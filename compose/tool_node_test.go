@@ -804,3 +804,69 @@ func (m *myTool4) StreamableRun(ctx context.Context, argumentsInJSON string, opt
 	m.times++
 	return schema.StreamReaderFromArray([]string{"tool4 input: ", argumentsInJSON}), nil
 }
+
+type weatherInput struct {
+	City string `json:"city" jsonschema:"required"`
+	Unit string `json:"unit,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+}
+
+func TestToolArgumentsValidation(t *testing.T) {
+	ctx := context.Background()
+
+	var called int
+	weather, err := utils.InferTool("weather", "get weather", func(ctx context.Context, in *weatherInput) (string, error) {
+		called++
+		return "sunny in " + in.City, nil
+	})
+	assert.NoError(t, err)
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "weather", Arguments: `{"city":"paris"}`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "weather", Arguments: `{"unit":"kelvin"}`}},
+		{ID: "3", Function: schema.FunctionCall{Name: "weather", Arguments: `{"city":`}},
+	})
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:                 []tool.BaseTool{weather},
+		ValidateToolArguments: true,
+	})
+	assert.NoError(t, err)
+
+	msgs, err := tn.Invoke(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, called)
+	assert.Equal(t, "sunny in paris", msgs[0].Content)
+	assert.Equal(t, `{"error":"invalid arguments of tool weather","violations":[`+
+		`{"path":"/city","message":"required property is missing"},`+
+		`{"path":"/unit","message":"value \"kelvin\" is not one of the allowed values [\"celsius\",\"fahrenheit\"]"}]}`, msgs[1].Content)
+	assert.Equal(t, "2", msgs[1].ToolCallID)
+	assert.Contains(t, msgs[2].Content, "invalid arguments json")
+
+	tn, err = NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:                 []tool.BaseTool{weather},
+		ValidateToolArguments: true,
+		ToolArgumentsViolationHandler: func(ctx context.Context, name, arguments string, violations []*schema.SchemaViolation) (string, error) {
+			return fmt.Sprintf("%s: %d violations", name, len(violations)), nil
+		},
+	})
+	assert.NoError(t, err)
+
+	sr, err := tn.Stream(ctx, input)
+	assert.NoError(t, err)
+	msgs = make([]*schema.Message, 3)
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		for i := range chunk {
+			if chunk[i] != nil {
+				msgs[i] = chunk[i]
+			}
+		}
+	}
+	assert.Equal(t, 2, called)
+	assert.Equal(t, "weather: 2 violations", msgs[1].Content)
+	assert.Equal(t, "weather: 1 violations", msgs[2].Content)
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/mrh997/eino/components"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+type argumentsViolationOutput struct {
+	Error      string                    `json:"error"`
+	Violations []*schema.SchemaViolation `json:"violations"`
+}
+
+func defaultArgumentsViolationHandler(_ context.Context, name, _ string, violations []*schema.SchemaViolation) (string, error) {
+	return sonic.MarshalString(&argumentsViolationOutput{
+		Error:      fmt.Sprintf("invalid arguments of tool %s", name),
+		Violations: violations,
+	})
+}
+
+// validateToolArguments validates the arguments of the task against the tool's ParamsOneOf,
+// and returns a task outputting the violations instead of executing the tool if the arguments are invalid.
+func (tn *ToolsNode) validateToolArguments(ctx context.Context, info *schema.ToolInfo, task *toolCallTask) (toolCallTask, bool, error) {
+	if info == nil || info.ParamsOneOf == nil {
+		return toolCallTask{}, false, nil
+	}
+
+	violations, err := info.ParamsOneOf.ValidateArguments(task.arg)
+	if err != nil {
+		violations = []*schema.SchemaViolation{{Message: err.Error()}}
+	}
+	if len(violations) == 0 {
+		return toolCallTask{}, false, nil
+	}
+
+	handler := tn.argumentsViolationHandler
+	if handler == nil {
+		handler = defaultArgumentsViolationHandler
+	}
+	output, err := handler(ctx, task.name, task.arg, violations)
+	if err != nil {
+		return toolCallTask{}, false, fmt.Errorf("failed to handle invalid arguments of tool[name:%s arguments:%s]: %w", task.name, task.arg, err)
+	}
+
+	return toolCallTask{
+		r: newRunnablePacker(func(ctx context.Context, input string, opts ...tool.Option) (string, error) {
+			return output, nil
+		}, nil, nil, nil, false),
+		meta: &executorMeta{
+			component:                  components.ComponentOfTool,
+			isComponentCallbackEnabled: false,
+			componentImplType:          "InvalidArguments",
+		},
+		name:   task.name,
+		arg:    task.arg,
+		callID: task.callID,
+	}, true, nil
+}
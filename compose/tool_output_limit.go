@@ -0,0 +1,281 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+// ToolOutputLimitStrategy is how ToolsNode shortens a tool output exceeding ToolOutputLimit.
+type ToolOutputLimitStrategy string
+
+const (
+	// ToolOutputLimitTruncate keeps the head of the output, followed by a marker telling how much is truncated.
+	ToolOutputLimitTruncate ToolOutputLimitStrategy = "truncate"
+	// ToolOutputLimitHeadTail keeps the head and the tail of the output, with a marker in the middle.
+	ToolOutputLimitHeadTail ToolOutputLimitStrategy = "head_tail"
+	// ToolOutputLimitStore saves the full output in ToolOutputLimit.Store, and keeps the head of the output,
+	// followed by the handle of the output to read more by the tool of NewReadToolOutputTool.
+	ToolOutputLimitStore ToolOutputLimitStrategy = "store"
+)
+
+// ReadToolOutputToolName is the name of the tool created by NewReadToolOutputTool.
+const ReadToolOutputToolName = "read_tool_output"
+
+// ToolOutputLimit limits the size of the tool outputs returned to the model by ToolsNode, see ToolsNodeConfig.ToolOutputLimit.
+// tool callbacks always get the full output.
+// the output of a StreamableTool is concatenated before limiting, so the model gets it as a single chunk when limited.
+// the outputs of tool.MultimodalTool are not limited.
+type ToolOutputLimit struct {
+	// MaxChars is the max number of characters of an output.
+	// Optional. Default 0, no limit by characters.
+	MaxChars int
+
+	// MaxTokens is the max number of tokens of an output estimated by TokenEstimator.
+	// Optional. Default 0, no limit by tokens.
+	MaxTokens int
+
+	// TokenEstimator estimates the number of tokens of the text.
+	// Optional. Default one token per 4 characters.
+	TokenEstimator func(text string) int
+
+	// Strategy is how to shorten the output exceeding the limit.
+	// Optional. Default ToolOutputLimitTruncate.
+	Strategy ToolOutputLimitStrategy
+
+	// Store saves the full outputs when Strategy is ToolOutputLimitStore, required by ToolOutputLimitStore.
+	// add the tool of NewReadToolOutputTool with the same Store to the ToolsNode and the model, so that the model can read the rest of the output.
+	Store ToolOutputStore
+}
+
+// ToolOutputStore saves the full tool outputs for ToolOutputLimitStore, see NewInMemoryToolOutputStore.
+type ToolOutputStore interface {
+	// Save saves the output of the tool call, and returns the handle to load it.
+	Save(ctx context.Context, name, callID, output string) (handle string, err error)
+	// Load loads the output saved with the handle, ok is false if not found.
+	Load(ctx context.Context, handle string) (output string, ok bool, err error)
+}
+
+const defaultInMemoryToolOutputs = 100
+
+// NewInMemoryToolOutputStore creates a ToolOutputStore keeping the latest maxOutputs outputs in memory,
+// the oldest outputs are evicted when it is full, and can't be read any more.
+// maxOutputs defaults to 100 if not positive.
+func NewInMemoryToolOutputStore(maxOutputs int) ToolOutputStore {
+	if maxOutputs <= 0 {
+		maxOutputs = defaultInMemoryToolOutputs
+	}
+	return &inMemoryToolOutputStore{maxOutputs: maxOutputs, outputs: make(map[string]string)}
+}
+
+type inMemoryToolOutputStore struct {
+	mu         sync.RWMutex
+	seq        int
+	maxOutputs int
+	// handles are in the order saved, for eviction
+	handles []string
+	outputs map[string]string
+}
+
+func (s *inMemoryToolOutputStore) Save(_ context.Context, name, callID, output string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	handle := fmt.Sprintf("%s-%d", name, s.seq)
+	s.outputs[handle] = output
+	s.handles = append(s.handles, handle)
+	for len(s.handles) > s.maxOutputs {
+		delete(s.outputs, s.handles[0])
+		s.handles = s.handles[1:]
+	}
+	return handle, nil
+}
+
+func (s *inMemoryToolOutputStore) Load(_ context.Context, handle string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	output, ok := s.outputs[handle]
+	return output, ok, nil
+}
+
+type readToolOutputInput struct {
+	Handle string `json:"handle"`
+	Offset int    `json:"offset"`
+}
+
+// NewReadToolOutputTool creates the tool to read the rest of the tool outputs saved by ToolOutputLimitStore, named ReadToolOutputToolName.
+// pageChars is the max number of characters read by a call.
+func NewReadToolOutputTool(store ToolOutputStore, pageChars int) (tool.InvokableTool, error) {
+	if store == nil {
+		return nil, errors.New("tool output store is nil")
+	}
+	if pageChars <= 0 {
+		return nil, fmt.Errorf("page chars must be positive, got %d", pageChars)
+	}
+	return &readToolOutputTool{store: store, pageChars: pageChars}, nil
+}
+
+type readToolOutputTool struct {
+	store     ToolOutputStore
+	pageChars int
+}
+
+func (r *readToolOutputTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: ReadToolOutputToolName,
+		Desc: "read more of a truncated tool output by its handle",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"handle": {Type: schema.String, Desc: "the handle of the tool output", Required: true},
+			"offset": {Type: schema.Integer, Desc: "the character offset to read from", Required: true},
+		}),
+	}, nil
+}
+
+func (r *readToolOutputTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	in := &readToolOutputInput{}
+	if err := sonic.UnmarshalString(argumentsInJSON, in); err != nil {
+		return "", fmt.Errorf("failed to unmarshal arguments of %s: %w", ReadToolOutputToolName, err)
+	}
+
+	output, ok, err := r.store.Load(ctx, in.Handle)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return fmt.Sprintf("tool output of handle %q not found", in.Handle), nil
+	}
+
+	runes := []rune(output)
+	if in.Offset < 0 || in.Offset >= len(runes) {
+		return fmt.Sprintf("offset %d is out of range, the output has %d characters", in.Offset, len(runes)), nil
+	}
+	end := in.Offset + r.pageChars
+	if end >= len(runes) {
+		return string(runes[in.Offset:]), nil
+	}
+	return string(runes[in.Offset:end]) + moreMarker(len(runes)-end, in.Handle, end), nil
+}
+
+func moreMarker(remaining int, handle string, offset int) string {
+	return fmt.Sprintf("\n...[%d more characters, call tool %s with handle %q and offset %d to read more]",
+		remaining, ReadToolOutputToolName, handle, offset)
+}
+
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+func validateToolOutputLimit(l *ToolOutputLimit) error {
+	if l == nil {
+		return nil
+	}
+	switch l.Strategy {
+	case "", ToolOutputLimitTruncate, ToolOutputLimitHeadTail:
+	case ToolOutputLimitStore:
+		if l.Store == nil {
+			return errors.New("tool output limit strategy store requires Store")
+		}
+	default:
+		return fmt.Errorf("unknown tool output limit strategy: %s", l.Strategy)
+	}
+	return nil
+}
+
+// maxChars returns the max number of characters allowed for the output, -1 if not exceeding the limit.
+func (l *ToolOutputLimit) maxChars(output []rune) int {
+	limit := -1
+	if l.MaxChars > 0 && len(output) > l.MaxChars {
+		limit = l.MaxChars
+	}
+	if l.MaxTokens > 0 {
+		estimator := l.TokenEstimator
+		if estimator == nil {
+			estimator = estimateTokens
+		}
+		if tokens := estimator(string(output)); tokens > l.MaxTokens {
+			// scale the characters by the ratio of tokens, as tokens can't be mapped to characters precisely
+			byTokens := len(output) * l.MaxTokens / tokens
+			if limit < 0 || byTokens < limit {
+				limit = byTokens
+			}
+		}
+	}
+	return limit
+}
+
+func (l *ToolOutputLimit) apply(ctx context.Context, name, callID, output string) (string, error) {
+	runes := []rune(output)
+	limit := l.maxChars(runes)
+	if limit < 0 {
+		return output, nil
+	}
+
+	switch l.Strategy {
+	case ToolOutputLimitHeadTail:
+		head := (limit + 1) / 2
+		tail := limit - head
+		return fmt.Sprintf("%s\n...[%d characters omitted]...\n%s",
+			string(runes[:head]), len(runes)-limit, string(runes[len(runes)-tail:])), nil
+	case ToolOutputLimitStore:
+		handle, err := l.Store.Save(ctx, name, callID, output)
+		if err != nil {
+			return "", fmt.Errorf("failed to save output of tool[name:%s id:%s]: %w", name, callID, err)
+		}
+		return string(runes[:limit]) + moreMarker(len(runes)-limit, handle, limit), nil
+	default:
+		return fmt.Sprintf("%s\n...[%d characters truncated]", string(runes[:limit]), len(runes)-limit), nil
+	}
+}
+
+// wrap returns the run function limiting the outputs of the executed tool call tasks.
+func (l *ToolOutputLimit) wrap(run func(ctx context.Context, task *toolCallTask, opts ...tool.Option), isStream bool) func(ctx context.Context, task *toolCallTask, opts ...tool.Option) {
+	return func(ctx context.Context, task *toolCallTask, opts ...tool.Option) {
+		if task.executed {
+			return
+		}
+		run(ctx, task, opts...)
+		if task.err != nil || !task.executed || task.multimodal || task.name == ReadToolOutputToolName {
+			return
+		}
+
+		output := task.output
+		if isStream {
+			var err error
+			output, err = concatStreamReader(task.sOutput)
+			if err != nil {
+				task.err = fmt.Errorf("failed to concat tool[name:%s id:%s]'s stream output: %w", task.name, task.callID, err)
+				return
+			}
+		}
+
+		output, task.err = l.apply(ctx, task.name, task.callID, output)
+		if isStream {
+			task.sOutput = schema.StreamReaderFromArray([]string{output})
+		} else {
+			task.output = output
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/callbacks"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+func TestToolOutputLimit(t *testing.T) {
+	ctx := context.Background()
+	output := "0123456789abcdefghij"
+	long := &policyTestTool{name: "long", run: func(ctx context.Context, calls int) (string, error) {
+		return output, nil
+	}}
+
+	invoke := func(t *testing.T, limit *ToolOutputLimit, stream bool, opts ...ToolsNodeOption) string {
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{long}, ToolOutputLimit: limit})
+		assert.NoError(t, err)
+		if !stream {
+			msgs, err := tn.Invoke(ctx, toolCallsMessage("long"), opts...)
+			assert.NoError(t, err)
+			return msgs[0].Content
+		}
+		sr, err := tn.Stream(ctx, toolCallsMessage("long"), opts...)
+		assert.NoError(t, err)
+		msgs, err := concatStreamReader(sr)
+		assert.NoError(t, err)
+		return msgs[0].Content
+	}
+
+	t.Run("truncate", func(t *testing.T) {
+		for _, stream := range []bool{false, true} {
+			assert.Equal(t, "0123456789\n...[10 characters truncated]", invoke(t, &ToolOutputLimit{MaxChars: 10}, stream))
+		}
+		assert.Equal(t, output, invoke(t, &ToolOutputLimit{MaxChars: 20}, false))
+		// 5 estimated tokens, 2 allowed
+		assert.Equal(t, "01234567\n...[12 characters truncated]", invoke(t, &ToolOutputLimit{MaxTokens: 2}, false))
+	})
+
+	t.Run("head tail", func(t *testing.T) {
+		assert.Equal(t, "01234\n...[10 characters omitted]...\nfghij", invoke(t, &ToolOutputLimit{MaxChars: 10, Strategy: ToolOutputLimitHeadTail}, false))
+	})
+
+	t.Run("store", func(t *testing.T) {
+		store := NewInMemoryToolOutputStore(0)
+		reader, err := NewReadToolOutputTool(store, 6)
+		assert.NoError(t, err)
+
+		var full string
+		handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if info.Name == "long" {
+				full = tool.ConvCallbackOutput(output).Response
+			}
+			return ctx
+		}).Build()
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:           []tool.BaseTool{long, reader},
+			ToolOutputLimit: &ToolOutputLimit{MaxChars: 8, Strategy: ToolOutputLimitStore, Store: store},
+		})
+		assert.NoError(t, err)
+
+		msgs, err := tn.Invoke(callbacks.InitCallbacks(ctx, nil, handler), toolCallsMessage("long"))
+		assert.NoError(t, err)
+		assert.Equal(t, output, full)
+		assert.Equal(t, `01234567`+"\n"+`...[12 more characters, call tool read_tool_output with handle "long-1" and offset 8 to read more]`, msgs[0].Content)
+
+		read := &schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{
+			ID:       "r",
+			Function: schema.FunctionCall{Name: ReadToolOutputToolName, Arguments: `{"handle":"long-1","offset":8}`},
+		}}}
+		msgs, err = tn.Invoke(ctx, read)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(msgs[0].Content, "89abcd\n...[6 more characters"))
+
+		_, err = NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{long}, ToolOutputLimit: &ToolOutputLimit{Strategy: ToolOutputLimitStore}})
+		assert.ErrorContains(t, err, "requires Store")
+	})
+	t.Run("in memory store eviction", func(t *testing.T) {
+		store := NewInMemoryToolOutputStore(2)
+		var handles []string
+		for _, out := range []string{"a", "b", "c"} {
+			handle, err := store.Save(ctx, "t", "id", out)
+			assert.NoError(t, err)
+			handles = append(handles, handle)
+		}
+
+		_, ok, err := store.Load(ctx, handles[0])
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		out, ok, err := store.Load(ctx, handles[2])
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "c", out)
+	})
+}
@@ -32,6 +32,9 @@ const (
 	ComponentOfPassthrough component = "Passthrough"
 	ComponentOfToolsNode   component = "ToolsNode"
 	ComponentOfLambda      component = "Lambda"
+	// ComponentOfJSONRepair is the component of the callbacks reporting the repairs made to model generated json,
+	// see ToolsNodeConfig.RepairToolArguments and schema.MessageJSONParseConfig.RepairJSON.
+	ComponentOfJSONRepair component = "JSONRepair"
 )
 
 // NodeTriggerMode controls the triggering mode of graph nodes.
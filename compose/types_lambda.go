@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/mrh997/eino/schema"
 )
@@ -254,8 +255,14 @@ func ToList[I any](opts ...LambdaOpt) *Lambda {
 //		Role:    schema.MessageRoleUser,
 //		Content: "return a json string for my struct",
 //	})
+//
+// the json repairs made by the parser are reported by callbacks with RunInfo.Component ComponentOfJSONRepair,
+// see schema.MessageJSONParseConfig.RepairJSON.
 func MessageParser[T any](p schema.MessageParser[T], opts ...LambdaOpt) *Lambda {
 	i := func(ctx context.Context, input *schema.Message, opts_ ...unreachableOption) (output T, err error) {
+		ctx = schema.WithJSONRepairReporter(ctx, func(ctx context.Context, result *schema.JSONRepairResult) {
+			reportJSONRepair(ctx, "MessageParser", result)
+		})
 		return p.Parse(ctx, input)
 	}
 
@@ -263,3 +270,47 @@ func MessageParser[T any](p schema.MessageParser[T], opts ...LambdaOpt) *Lambda
 
 	return anyLambda(i, nil, nil, nil, opts...)
 }
+
+// MessageStreamParser creates a transformable lambda that parses the message stream into a stream of progressively more complete T,
+// usually used after a chatmodel to render the structured output while the model is still generating.
+// usage:
+//
+//	parser := schema.NewMessageJSONStreamParser[MyStruct](&schema.MessageJSONParseConfig{
+//		ParseFrom: schema.MessageParseFromToolCall,
+//	})
+//
+//	chain := NewChain[[]*schema.Message, MyStruct]()
+//	chain.AppendChatModel(chatModel)
+//	chain.AppendLambda(MessageStreamParser(parser))
+//
+//	r, err := chain.Compile(context.Background())
+//	sr, err := r.Stream(ctx, input) // receives the partial MyStruct objects
+func MessageStreamParser[T any](p schema.MessageStreamParser[T], opts ...LambdaOpt) *Lambda {
+	// the output of invoke is the last value, i.e. the value parsed from the complete message
+	i := func(ctx context.Context, input *schema.Message, opts_ ...unreachableOption) (output T, err error) {
+		sr, err := p.ParseStream(ctx, schema.StreamReaderFromArray([]*schema.Message{input}))
+		if err != nil {
+			return output, err
+		}
+		defer sr.Close()
+
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				return output, nil
+			}
+			if err != nil {
+				return output, err
+			}
+			output = chunk
+		}
+	}
+
+	t := func(ctx context.Context, input *schema.StreamReader[*schema.Message], opts_ ...unreachableOption) (output *schema.StreamReader[T], err error) {
+		return p.ParseStream(ctx, input)
+	}
+
+	opts = append([]LambdaOpt{WithLambdaType("MessageStreamParse")}, opts...)
+
+	return anyLambda(i, nil, nil, t, opts...)
+}
@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -210,3 +211,33 @@ func TestMessageParser(t *testing.T) {
 		assert.Equal(t, 1, parsed.ID)
 	})
 }
+
+func TestMessageStreamParser(t *testing.T) {
+	ctx := context.Background()
+	parser := schema.NewMessageJSONStreamParser[TestStructForParse](nil)
+
+	chain := NewChain[*schema.Message, TestStructForParse]()
+	chain.AppendLambda(MessageStreamParser(parser))
+	r, err := chain.Compile(ctx)
+	assert.Nil(t, err)
+
+	sr, err := r.Transform(ctx, schema.StreamReaderFromArray([]*schema.Message{
+		{Content: `{"id": 1`},
+		{Content: `2}`},
+	}))
+	assert.Nil(t, err)
+	var ids []int
+	for {
+		parsed, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		ids = append(ids, parsed.ID)
+	}
+	assert.Equal(t, []int{1, 12}, ids)
+
+	parsed, err := r.Invoke(ctx, &schema.Message{Content: `{"id": 3}`})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, parsed.ID)
+}
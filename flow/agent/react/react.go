@@ -18,6 +18,8 @@ package react
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"sync"
 
@@ -30,6 +32,8 @@ import (
 type state struct {
 	Messages                 []*schema.Message
 	ReturnDirectlyToolCallID string
+	// SelectedTools are the names of the tools selected by ToolSelector in the current turn, nil if there is no ToolSelector
+	SelectedTools []string
 }
 
 const (
@@ -40,6 +44,14 @@ const (
 // MessageModifier modify the input messages before the model is called.
 type MessageModifier func(ctx context.Context, input []*schema.Message) []*schema.Message
 
+// FallibleMessageModifier modify the input messages before the model is called, and fails the model call on error.
+type FallibleMessageModifier func(ctx context.Context, input []*schema.Message) ([]*schema.Message, error)
+
+// ToolSelector selects the tools bound to the model in each turn, e.g. *toolselect.Registry.
+type ToolSelector interface {
+	SelectTools(ctx context.Context, messages []*schema.Message) ([]*schema.ToolInfo, error)
+}
+
 // AgentConfig is the config for ReAct agent.
 type AgentConfig struct {
 	// ToolCallingModel is the chat model to be used for handling user messages with tool calling capability.
@@ -56,6 +68,10 @@ type AgentConfig struct {
 	// modify the input messages before the model is called, it's useful when you want to add some system prompt or other messages.
 	MessageModifier MessageModifier
 
+	// FallibleMessageModifier modifies the input messages after MessageModifier, the error of which fails the agent, e.g. NewTrimModifier.
+	// Optional. Default no modification.
+	FallibleMessageModifier FallibleMessageModifier
+
 	// MaxStep.
 	// default 12 of steps in pregel (node num + 10).
 	MaxStep int `json:"max_step"`
@@ -88,6 +104,14 @@ type AgentConfig struct {
 	// ToolsNodeName is the node name of the tools node in the ReAct Agent graph.
 	// Optional. Default `Tools`.
 	ToolsNodeName string
+
+	// ToolSelector selects the tools bound to the model in each turn from ToolsConfig.Tools, by the input messages of the model,
+	// it's useful when there are too many tools to bind all of them to the model.
+	// only the selected tools can be called in the turn, the calls to other tools are handled by ToolsConfig.UnknownToolsHandler.
+	// the selected tools must be in ToolsConfig.Tools, otherwise the model call fails, as they couldn't be called.
+	// Requires ToolCallingModel.
+	// Optional. By default, all tools are bound.
+	ToolSelector ToolSelector
 }
 
 // Deprecated: This approach of adding persona involves unnecessary slice copying overhead.
@@ -116,6 +140,27 @@ func NewPersonaModifier(persona string) MessageModifier {
 	}
 }
 
+// NewTrimModifier trims the input messages to the token budget of the trimmer before the model is called,
+// keeping the system messages and the latest turns, see schema.MessageTrimmer.
+// the error of the trimmer, e.g. of its tokenizer, is returned, failing the agent.
+// example:
+//
+//	trimmer, err := schema.NewMessageTrimmer(&schema.MessageTrimConfig{MaxTokens: 8000, DropReasoningContent: true})
+//	if err != nil {return}
+//	config := AgentConfig{
+//		ToolCallingModel:        model,
+//		FallibleMessageModifier: NewTrimModifier(trimmer),
+//	}
+func NewTrimModifier(trimmer *schema.MessageTrimmer) FallibleMessageModifier {
+	return func(ctx context.Context, input []*schema.Message) ([]*schema.Message, error) {
+		trimmed, err := trimmer.Trim(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to trim messages: %w", err)
+		}
+		return trimmed, nil
+	}
+}
+
 func firstChunkStreamToolCallChecker(_ context.Context, sr *schema.StreamReader[*schema.Message]) (bool, error) {
 	defer sr.Close()
 
@@ -185,11 +230,12 @@ var registerStateOnce sync.Once
 // In such cases, you need to implement a custom StreamToolCallChecker that can properly detect tool calls.
 func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 	var (
-		chatModel       model.BaseChatModel
-		toolsNode       *compose.ToolsNode
-		toolInfos       []*schema.ToolInfo
-		toolCallChecker = config.StreamToolCallChecker
-		messageModifier = config.MessageModifier
+		chatModel        model.BaseChatModel
+		toolsNode        *compose.ToolsNode
+		toolInfos        []*schema.ToolInfo
+		toolCallChecker  = config.StreamToolCallChecker
+		messageModifier  = config.MessageModifier
+		fallibleModifier = config.FallibleMessageModifier
 	)
 
 	registerStateOnce.Do(func() {
@@ -222,11 +268,26 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 		return nil, err
 	}
 
-	if chatModel, err = agent.ChatModelWithTools(config.Model, config.ToolCallingModel, toolInfos); err != nil {
-		return nil, err
+	toolsConfig := config.ToolsConfig
+	if config.ToolSelector != nil {
+		if config.ToolCallingModel == nil {
+			return nil, errors.New("tool selector requires tool calling model")
+		}
+		known := make(map[string]bool, len(toolInfos))
+		for _, info := range toolInfos {
+			known[info.Name] = true
+		}
+		chatModel = &toolSelectingModel{model: config.ToolCallingModel, selector: config.ToolSelector, known: known}
+		if toolsConfig.AllowedToolsGetter == nil {
+			toolsConfig.AllowedToolsGetter = getSelectedTools
+		}
+	} else {
+		if chatModel, err = agent.ChatModelWithTools(config.Model, config.ToolCallingModel, toolInfos); err != nil {
+			return nil, err
+		}
 	}
 
-	if toolsNode, err = compose.NewToolNode(ctx, &config.ToolsConfig); err != nil {
+	if toolsNode, err = compose.NewToolNode(ctx, &toolsConfig); err != nil {
 		return nil, err
 	}
 
@@ -237,13 +298,19 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 	modelPreHandle := func(ctx context.Context, input []*schema.Message, state *state) ([]*schema.Message, error) {
 		state.Messages = append(state.Messages, input...)
 
-		if messageModifier == nil {
+		if messageModifier == nil && fallibleModifier == nil {
 			return state.Messages, nil
 		}
 
 		modifiedInput := make([]*schema.Message, len(state.Messages))
 		copy(modifiedInput, state.Messages)
-		return messageModifier(ctx, modifiedInput), nil
+		if messageModifier != nil {
+			modifiedInput = messageModifier(ctx, modifiedInput)
+		}
+		if fallibleModifier != nil {
+			return fallibleModifier(ctx, modifiedInput)
+		}
+		return modifiedInput, nil
 	}
 
 	if err = graph.AddChatModelNode(nodeKeyModel, chatModel, compose.WithStatePreHandler(modelPreHandle), compose.WithNodeName(modelNodeName)); err != nil {
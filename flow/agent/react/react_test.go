@@ -684,3 +684,38 @@ func randStr() string {
 }
 
 var callbackForTest = BuildAgentCallback(&template.ModelCallbackHandler{}, &template.ToolCallbackHandler{})
+
+func TestTrimModifier(t *testing.T) {
+	trimmer, err := schema.NewMessageTrimmer(&schema.MessageTrimConfig{MaxTokens: 12})
+	assert.NoError(t, err)
+
+	input := []*schema.Message{
+		schema.SystemMessage("system"),
+		schema.UserMessage("first question"),
+		schema.UserMessage("second"),
+	}
+	modifier := NewTrimModifier(trimmer)
+	trimmed, err := modifier(context.Background(), input)
+	assert.NoError(t, err)
+	assert.Equal(t, []*schema.Message{input[0], input[2]}, trimmed)
+
+	// the error of the tokenizer fails the agent
+	trimmer, err = schema.NewMessageTrimmer(&schema.MessageTrimConfig{MaxTokens: 12, Tokenizer: failingTokenizer{}})
+	assert.NoError(t, err)
+	var bound [][]string
+	cm := &bindingModel{bound: &bound, answer: func(input []*schema.Message) *schema.Message {
+		return schema.AssistantMessage("answer", nil)
+	}}
+	ra, err := NewAgent(context.Background(), &AgentConfig{ToolCallingModel: cm, FallibleMessageModifier: NewTrimModifier(trimmer)})
+	assert.NoError(t, err)
+	_, err = ra.Generate(context.Background(), input)
+	assert.ErrorContains(t, err, "failed to trim messages")
+	assert.ErrorContains(t, err, "tokenizer unavailable")
+	assert.Empty(t, bound)
+}
+
+type failingTokenizer struct{}
+
+func (failingTokenizer) CountTokens(ctx context.Context, text string) (int, error) {
+	return 0, errors.New("tokenizer unavailable")
+}
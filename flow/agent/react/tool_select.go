@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mrh997/eino/components"
+	"github.com/mrh997/eino/components/model"
+	"github.com/mrh997/eino/compose"
+	"github.com/mrh997/eino/schema"
+)
+
+// toolSelectingModel binds the tools selected by the selector before each call,
+// and records the names of them in the state for the tools node.
+type toolSelectingModel struct {
+	model    model.ToolCallingChatModel
+	selector ToolSelector
+	// known are the names of the tools of the tools node, the selected tools must be among them to be callable
+	known map[string]bool
+}
+
+func (m *toolSelectingModel) bind(ctx context.Context, input []*schema.Message) (model.BaseChatModel, error) {
+	infos, err := m.selector.SelectTools(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select tools: %w", err)
+	}
+
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if !m.known[info.Name] {
+			return nil, fmt.Errorf("selected tool[%s] is not in the tools of the tools node, "+
+				"tools added to the selector after creating the agent are not callable", info.Name)
+		}
+		names = append(names, info.Name)
+	}
+	err = compose.ProcessState[*state](ctx, func(_ context.Context, s *state) error {
+		s.SelectedTools = names
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(infos) == 0 {
+		return m.model, nil
+	}
+	return m.model.WithTools(infos)
+}
+
+func (m *toolSelectingModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	cm, err := m.bind(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return cm.Generate(ctx, input, opts...)
+}
+
+func (m *toolSelectingModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	cm, err := m.bind(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return cm.Stream(ctx, input, opts...)
+}
+
+func (m *toolSelectingModel) GetType() string {
+	typ, _ := components.GetType(m.model)
+	return typ
+}
+
+func (m *toolSelectingModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(m.model)
+}
+
+func getSelectedTools(ctx context.Context) (names []string, err error) {
+	err = compose.ProcessState[*state](ctx, func(_ context.Context, s *state) error {
+		names = s.SelectedTools
+		return nil
+	})
+	return names, err
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/model"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/components/tool/utils"
+	"github.com/mrh997/eino/compose"
+	"github.com/mrh997/eino/schema"
+)
+
+type selectorFunc func(ctx context.Context, messages []*schema.Message) ([]*schema.ToolInfo, error)
+
+func (f selectorFunc) SelectTools(ctx context.Context, messages []*schema.Message) ([]*schema.ToolInfo, error) {
+	return f(ctx, messages)
+}
+
+// bindingModel records the tools bound for each call.
+type bindingModel struct {
+	tools  []*schema.ToolInfo
+	bound  *[][]string
+	answer func(input []*schema.Message) *schema.Message
+}
+
+func (m *bindingModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	names := make([]string, 0, len(m.tools))
+	for _, info := range m.tools {
+		names = append(names, info.Name)
+	}
+	*m.bound = append(*m.bound, names)
+	return m.answer(input), nil
+}
+
+func (m *bindingModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (m *bindingModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &bindingModel{tools: tools, bound: m.bound, answer: m.answer}, nil
+}
+
+func TestToolSelector(t *testing.T) {
+	ctx := context.Background()
+
+	newTool := func(name string) tool.InvokableTool {
+		tl, err := utils.InferTool(name, name+" tool", func(ctx context.Context, in *fakeToolInput) (string, error) {
+			return name + " done", nil
+		})
+		assert.NoError(t, err)
+		return tl
+	}
+	weather, email := newTool("weather"), newTool("email")
+	weatherInfo, _ := weather.Info(ctx)
+
+	var bound [][]string
+	cm := &bindingModel{bound: &bound, answer: func(input []*schema.Message) *schema.Message {
+		last := input[len(input)-1]
+		if last.Role == schema.User {
+			return schema.AssistantMessage("", []schema.ToolCall{
+				{ID: "1", Function: schema.FunctionCall{Name: "weather", Arguments: `{}`}},
+				{ID: "2", Function: schema.FunctionCall{Name: "email", Arguments: `{}`}},
+			})
+		}
+		content := ""
+		for _, m := range input {
+			if m.Role == schema.Tool {
+				content += m.Content + ";"
+			}
+		}
+		return schema.AssistantMessage(content, nil)
+	}}
+
+	ra, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig: compose.ToolsNodeConfig{
+			Tools: []tool.BaseTool{weather, email},
+			UnknownToolsHandler: func(ctx context.Context, name, input string) (string, error) {
+				return name + " not available", nil
+			},
+		},
+		ToolSelector: selectorFunc(func(ctx context.Context, messages []*schema.Message) ([]*schema.ToolInfo, error) {
+			return []*schema.ToolInfo{weatherInfo}, nil
+		}),
+	})
+	assert.NoError(t, err)
+
+	msg, err := ra.Generate(ctx, []*schema.Message{schema.UserMessage("what's the weather")})
+	assert.NoError(t, err)
+	assert.Equal(t, "weather done;email not available;", msg.Content)
+	assert.Equal(t, [][]string{{"weather"}, {"weather"}}, bound)
+
+	// the selected tools must be callable by the tools node
+	calendar := newTool("calendar")
+	calendarInfo, _ := calendar.Info(ctx)
+	ra, err = NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{weather}},
+		ToolSelector: selectorFunc(func(ctx context.Context, messages []*schema.Message) ([]*schema.ToolInfo, error) {
+			return []*schema.ToolInfo{weatherInfo, calendarInfo}, nil
+		}),
+	})
+	assert.NoError(t, err)
+	_, err = ra.Generate(ctx, []*schema.Message{schema.UserMessage("what's the weather")})
+	assert.ErrorContains(t, err, "selected tool[calendar] is not in the tools of the tools node")
+
+	_, err = NewAgent(ctx, &AgentConfig{ToolSelector: selectorFunc(nil)})
+	assert.ErrorContains(t, err, "requires tool calling model")
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package toolselect provides a tool registry selecting the tools relevant to the conversation by embeddings,
+// for agents with too many tools to bind all of them to the model.
+package toolselect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mrh997/eino/components/embedding"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/internal/vector"
+	"github.com/mrh997/eino/schema"
+)
+
+const defaultTopK = 5
+
+// Config is the config of Registry.
+type Config struct {
+	// Embedder embeds the descriptions of the tools and the queries.
+	Embedder embedding.Embedder
+	// Tools are the tools registered initially, more tools can be registered by Registry.Add.
+	Tools []tool.BaseTool
+	// TopK is the max number of tools selected by relevance.
+	// Optional. Default 5.
+	TopK int
+	// AlwaysSelected are the names of the tools always selected, not counted in TopK.
+	AlwaysSelected []string
+	// QueryBuilder builds the query to select tools from the conversation.
+	// Optional. By default, the query is the content of the last user message.
+	QueryBuilder func(ctx context.Context, messages []*schema.Message) (string, error)
+}
+
+// Registry indexes the descriptions of tools with an embedding.Embedder,
+// and selects the top k tools relevant to the conversation, see SelectTools.
+// e.g.
+//
+//	registry, err := toolselect.NewRegistry(ctx, &toolselect.Config{Embedder: embedder, Tools: tools, TopK: 8})
+//	agent, err := react.NewAgent(ctx, &react.AgentConfig{
+//		ToolCallingModel: chatModel,
+//		ToolsConfig:      compose.ToolsNodeConfig{Tools: registry.Tools()},
+//		ToolSelector:     registry,
+//	})
+type Registry struct {
+	embedder       embedding.Embedder
+	topK           int
+	alwaysSelected map[string]bool
+	queryBuilder   func(ctx context.Context, messages []*schema.Message) (string, error)
+
+	mu      sync.RWMutex
+	entries []*entry
+	indexes map[string]int
+}
+
+type entry struct {
+	tool   tool.BaseTool
+	info   *schema.ToolInfo
+	vector []float64
+}
+
+// NewRegistry creates a Registry and indexes the tools of the config.
+func NewRegistry(ctx context.Context, conf *Config) (*Registry, error) {
+	if conf == nil || conf.Embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+
+	r := &Registry{
+		embedder:       conf.Embedder,
+		topK:           conf.TopK,
+		alwaysSelected: make(map[string]bool, len(conf.AlwaysSelected)),
+		queryBuilder:   conf.QueryBuilder,
+		indexes:        make(map[string]int),
+	}
+	if r.topK <= 0 {
+		r.topK = defaultTopK
+	}
+	if r.queryBuilder == nil {
+		r.queryBuilder = lastUserMessageQuery
+	}
+	for _, name := range conf.AlwaysSelected {
+		r.alwaysSelected[name] = true
+	}
+
+	if err := r.Add(ctx, conf.Tools...); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Add indexes the tools, replacing the registered tools with the same names.
+// the tools must also be added to the ToolsNode calling them, e.g. an agent created with Tools before must be recreated.
+func (r *Registry) Add(ctx context.Context, tools ...tool.BaseTool) error {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	entries := make([]*entry, len(tools))
+	texts := make([]string, len(tools))
+	for i, t := range tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get tool info at idx=%d: %w", i, err)
+		}
+		entries[i] = &entry{tool: t, info: info}
+		texts[i] = info.Name + ": " + info.Desc
+	}
+
+	vectors, err := r.embedder.EmbedStrings(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed tool descriptions: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return fmt.Errorf("embedder returns %d vectors for %d tool descriptions", len(vectors), len(texts))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, e := range entries {
+		e.vector = vectors[i]
+		if idx, ok := r.indexes[e.info.Name]; ok {
+			r.entries[idx] = e
+			continue
+		}
+		r.indexes[e.info.Name] = len(r.entries)
+		r.entries = append(r.entries, e)
+	}
+	return nil
+}
+
+// Tools returns all the registered tools, which should be the tools of the ToolsNode.
+func (r *Registry) Tools() []tool.BaseTool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]tool.BaseTool, len(r.entries))
+	for i, e := range r.entries {
+		tools[i] = e.tool
+	}
+	return tools
+}
+
+// SelectTools returns the infos of the tools always selected and the top k tools most relevant to the messages,
+// in the order of registration.
+func (r *Registry) SelectTools(ctx context.Context, messages []*schema.Message) ([]*schema.ToolInfo, error) {
+	query, err := r.queryBuilder(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tool selection query: %w", err)
+	}
+
+	var queryVector []float64
+	if query != "" {
+		vectors, err := r.embedder.EmbedStrings(ctx, []string{query})
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed tool selection query: %w", err)
+		}
+		if len(vectors) != 1 {
+			return nil, fmt.Errorf("embedder returns %d vectors for 1 query", len(vectors))
+		}
+		queryVector = vectors[0]
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	selected := make([]bool, len(r.entries))
+	candidates := make([]int, 0, len(r.entries))
+	scores := make([]float64, len(r.entries))
+	for i, e := range r.entries {
+		if r.alwaysSelected[e.info.Name] {
+			selected[i] = true
+			continue
+		}
+		if queryVector != nil {
+			scores[i] = vector.CosineSimilarity(queryVector, e.vector)
+			candidates = append(candidates, i)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i]] > scores[candidates[j]]
+	})
+	if len(candidates) > r.topK {
+		candidates = candidates[:r.topK]
+	}
+	for _, i := range candidates {
+		selected[i] = true
+	}
+
+	infos := make([]*schema.ToolInfo, 0, len(candidates)+len(r.alwaysSelected))
+	for i, e := range r.entries {
+		if selected[i] {
+			infos = append(infos, e.info)
+		}
+	}
+	return infos, nil
+}
+
+func lastUserMessageQuery(_ context.Context, messages []*schema.Message) (string, error) {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i] != nil && messages[i].Role == schema.User {
+			return messages[i].Content, nil
+		}
+	}
+	return "", nil
+}
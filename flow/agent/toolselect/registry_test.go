@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package toolselect
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/embedding"
+	"github.com/mrh997/eino/components/tool"
+	"github.com/mrh997/eino/schema"
+)
+
+// keywordEmbedder embeds a text by the occurrences of the keywords.
+type keywordEmbedder struct {
+	keywords []string
+}
+
+func (k *keywordEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float64, len(k.keywords))
+		for j, kw := range k.keywords {
+			vectors[i][j] = float64(strings.Count(strings.ToLower(text), kw))
+		}
+	}
+	return vectors, nil
+}
+
+type infoTool struct {
+	name, desc string
+}
+
+func (i *infoTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: i.name, Desc: i.desc}, nil
+}
+
+func toolNames(infos []*schema.ToolInfo) []string {
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name)
+	}
+	return names
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRegistry(ctx, &Config{
+		Embedder: &keywordEmbedder{keywords: []string{"weather", "email", "calendar", "file"}},
+		Tools: []tool.BaseTool{
+			&infoTool{name: "get_weather", desc: "get the weather forecast"},
+			&infoTool{name: "send_email", desc: "send an email"},
+			&infoTool{name: "read_file", desc: "read a file"},
+			&infoTool{name: "help", desc: "show help"},
+		},
+		TopK:           1,
+		AlwaysSelected: []string{"help"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, r.Tools(), 4)
+
+	infos, err := r.SelectTools(ctx, []*schema.Message{
+		schema.UserMessage("read the file"),
+		schema.AssistantMessage("ok", nil),
+		schema.UserMessage("then email it to bob"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"send_email", "help"}, toolNames(infos))
+
+	assert.NoError(t, r.Add(ctx,
+		&infoTool{name: "schedule", desc: "add an event to the calendar"},
+		&infoTool{name: "send_email", desc: "send a mail"},
+	))
+	assert.Len(t, r.Tools(), 5)
+
+	infos, err = r.SelectTools(ctx, []*schema.Message{schema.UserMessage("what is on my calendar")})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"help", "schedule"}, toolNames(infos))
+
+	// no query, only the tools always selected
+	infos, err = r.SelectTools(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"help"}, toolNames(infos))
+
+	_, err = NewRegistry(ctx, &Config{})
+	assert.ErrorContains(t, err, "embedder is required")
+}
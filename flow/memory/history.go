@@ -0,0 +1,153 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mrh997/eino/schema"
+)
+
+// ChatHistory stores the messages of the conversations by session ID.
+type ChatHistory interface {
+	// Append appends the messages to the history of the session.
+	Append(ctx context.Context, sessionID string, msgs ...*schema.Message) error
+	// Load loads all the messages of the session in order, empty if the session doesn't exist.
+	Load(ctx context.Context, sessionID string) ([]*schema.Message, error)
+	// Clear removes the history of the session.
+	Clear(ctx context.Context, sessionID string) error
+}
+
+// NewInMemoryChatHistory creates a ChatHistory keeping the messages in memory.
+func NewInMemoryChatHistory() ChatHistory {
+	return &inMemoryChatHistory{sessions: make(map[string][]*schema.Message)}
+}
+
+type inMemoryChatHistory struct {
+	mu       sync.RWMutex
+	sessions map[string][]*schema.Message
+}
+
+func (h *inMemoryChatHistory) Append(_ context.Context, sessionID string, msgs ...*schema.Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessions[sessionID] = append(h.sessions[sessionID], msgs...)
+	return nil
+}
+
+func (h *inMemoryChatHistory) Load(_ context.Context, sessionID string) ([]*schema.Message, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	msgs := h.sessions[sessionID]
+	ret := make([]*schema.Message, len(msgs))
+	copy(ret, msgs)
+	return ret, nil
+}
+
+func (h *inMemoryChatHistory) Clear(_ context.Context, sessionID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sessions, sessionID)
+	return nil
+}
+
+// NewFileChatHistory creates a ChatHistory keeping the messages of each session in a JSON lines file under the directory,
+// the directory is created if not exists.
+func NewFileChatHistory(dir string) (ChatHistory, error) {
+	if dir == "" {
+		return nil, errors.New("directory of file chat history is empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory of file chat history: %w", err)
+	}
+	return &fileChatHistory{dir: dir}, nil
+}
+
+type fileChatHistory struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+func (h *fileChatHistory) path(sessionID string) string {
+	return filepath.Join(h.dir, url.PathEscape(sessionID)+".jsonl")
+}
+
+func (h *fileChatHistory) Append(_ context.Context, sessionID string, msgs ...*schema.Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, err := os.OpenFile(h.path(sessionID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open chat history of session[%s]: %w", sessionID, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, msg := range msgs {
+		if err = enc.Encode(msg); err != nil {
+			return fmt.Errorf("failed to encode message of session[%s]: %w", sessionID, err)
+		}
+	}
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("failed to write chat history of session[%s]: %w", sessionID, err)
+	}
+	return nil
+}
+
+func (h *fileChatHistory) Load(_ context.Context, sessionID string) ([]*schema.Message, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	f, err := os.Open(h.path(sessionID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []*schema.Message{}, nil
+		}
+		return nil, fmt.Errorf("failed to open chat history of session[%s]: %w", sessionID, err)
+	}
+	defer f.Close()
+
+	var msgs []*schema.Message
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		msg := &schema.Message{}
+		if err = dec.Decode(msg); err != nil {
+			return nil, fmt.Errorf("failed to decode chat history of session[%s]: %w", sessionID, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (h *fileChatHistory) Clear(_ context.Context, sessionID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.Remove(h.path(sessionID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to clear chat history of session[%s]: %w", sessionID, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/schema"
+)
+
+func TestFileChatHistory(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	h, err := NewFileChatHistory(dir)
+	assert.NoError(t, err)
+
+	msgs := []*schema.Message{
+		schema.UserMessage("what's the weather"),
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "weather", Arguments: `{"city":"beijing"}`}}}),
+		schema.ToolMessage("sunny", "1"),
+	}
+	assert.NoError(t, h.Append(ctx, "user/1", msgs[:1]...))
+	assert.NoError(t, h.Append(ctx, "user/1", msgs[1:]...))
+
+	// the history is persisted across instances
+	h, err = NewFileChatHistory(dir)
+	assert.NoError(t, err)
+	loaded, err := h.Load(ctx, "user/1")
+	assert.NoError(t, err)
+	assert.Equal(t, msgs, loaded)
+
+	loaded, err = h.Load(ctx, "user/2")
+	assert.NoError(t, err)
+	assert.Empty(t, loaded)
+
+	assert.NoError(t, h.Clear(ctx, "user/1"))
+	assert.NoError(t, h.Clear(ctx, "user/1"))
+	loaded, err = h.Load(ctx, "user/1")
+	assert.NoError(t, err)
+	assert.Empty(t, loaded)
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package memory provides the chat history storage of conversations, and the strategies selecting the history given to the model,
+// e.g. the whole buffer, a sliding window or a rolling summary.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mrh997/eino/compose"
+	"github.com/mrh997/eino/schema"
+)
+
+const defaultHistoryKey = "history"
+
+// Config is the config of Memory.
+type Config struct {
+	// History stores the messages of the sessions.
+	History ChatHistory
+	// Strategy selects the messages given to the model from the history.
+	// Optional. Default NewBufferStrategy().
+	Strategy Strategy
+	// HistoryKey is the key of the selected messages in the variables of the prompt, used with schema.MessagesPlaceholder.
+	// Optional. Default "history".
+	HistoryKey string
+	// QueryKey is the key of the user query in the variables of the prompt.
+	// if set, the query is saved as a user message by the save node together with the output message of the model,
+	// so that neither is saved if the model fails.
+	// Optional. By default, the user query is not saved.
+	QueryKey string
+}
+
+// Memory loads the history of the sessions for prompts, and saves the new messages.
+// e.g.
+//
+//	mem, err := memory.NewMemory(&memory.Config{History: memory.NewInMemoryChatHistory(), QueryKey: "query"})
+//	template := prompt.FromMessages(schema.FString,
+//		schema.SystemMessage("you are a helpful assistant"),
+//		schema.MessagesPlaceholder("history", true),
+//		schema.UserMessage("{query}"),
+//	)
+//
+//	chain := compose.NewChain[map[string]any, *schema.Message]()
+//	chain.AppendLambda(mem.LoadLambda()).AppendChatTemplate(template).AppendChatModel(chatModel).AppendLambda(mem.SaveLambda())
+//	r, err := chain.Compile(ctx)
+//	out, err := r.Invoke(memory.WithSessionID(ctx, sessionID), map[string]any{"query": "what is eino?"})
+type Memory struct {
+	history    ChatHistory
+	strategy   Strategy
+	historyKey string
+	queryKey   string
+}
+
+// NewMemory creates a Memory.
+func NewMemory(conf *Config) (*Memory, error) {
+	if conf == nil || conf.History == nil {
+		return nil, errors.New("chat history is required")
+	}
+	m := &Memory{
+		history:    conf.History,
+		strategy:   conf.Strategy,
+		historyKey: conf.HistoryKey,
+		queryKey:   conf.QueryKey,
+	}
+	if m.strategy == nil {
+		m.strategy = NewBufferStrategy()
+	}
+	if m.historyKey == "" {
+		m.historyKey = defaultHistoryKey
+	}
+	return m, nil
+}
+
+type sessionKey struct{}
+
+// session is set to the context by WithSessionID, and carries the user query from the load node to the save node.
+type session struct {
+	id string
+
+	mu sync.Mutex
+	// query is replaced by every load, so that the query of a failed run is dropped
+	query *schema.Message
+}
+
+func (s *session) setQuery(query *schema.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.query = query
+}
+
+func (s *session) takeQuery() []*schema.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.query == nil {
+		return nil
+	}
+	query := s.query
+	s.query = nil
+	return []*schema.Message{query}
+}
+
+// WithSessionID sets the session ID used by the load and save nodes of Memory.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, &session{id: sessionID})
+}
+
+// GetSessionID returns the session ID set by WithSessionID.
+func GetSessionID(ctx context.Context) (string, bool) {
+	s, ok := ctx.Value(sessionKey{}).(*session)
+	if !ok {
+		return "", false
+	}
+	return s.id, true
+}
+
+func sessionFromContext(ctx context.Context) (*session, error) {
+	s, ok := ctx.Value(sessionKey{}).(*session)
+	if !ok {
+		return nil, errors.New("session id not found in context, set it by memory.WithSessionID")
+	}
+	return s, nil
+}
+
+// Load loads the history of the session selected by the strategy.
+func (m *Memory) Load(ctx context.Context, sessionID string) ([]*schema.Message, error) {
+	history, err := m.history.Load(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history of session[%s]: %w", sessionID, err)
+	}
+	msgs, err := m.strategy.Select(ctx, sessionID, history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select history of session[%s]: %w", sessionID, err)
+	}
+	return msgs, nil
+}
+
+// Save appends the messages to the history of the session.
+func (m *Memory) Save(ctx context.Context, sessionID string, msgs ...*schema.Message) error {
+	if err := m.history.Append(ctx, sessionID, msgs...); err != nil {
+		return fmt.Errorf("failed to save history of session[%s]: %w", sessionID, err)
+	}
+	return nil
+}
+
+// Clear removes the history of the session, and resets the states of the session kept by the strategy, see StrategyResetter.
+func (m *Memory) Clear(ctx context.Context, sessionID string) error {
+	if err := m.history.Clear(ctx, sessionID); err != nil {
+		return err
+	}
+	if r, ok := m.strategy.(StrategyResetter); ok {
+		if err := r.Reset(ctx, sessionID); err != nil {
+			return fmt.Errorf("failed to reset strategy of session[%s]: %w", sessionID, err)
+		}
+	}
+	return nil
+}
+
+// LoadVariables returns a copy of the variables of the prompt with the history of the session under HistoryKey.
+// the user query is not saved, save it together with the answer by Save.
+func (m *Memory) LoadVariables(ctx context.Context, sessionID string, vs map[string]any) (map[string]any, error) {
+	msgs, err := m.Load(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]any, len(vs)+1)
+	for k, v := range vs {
+		ret[k] = v
+	}
+	ret[m.historyKey] = msgs
+	return ret, nil
+}
+
+// LoadLambda returns the graph node adding the history of the session to the variables of the prompt, see LoadVariables.
+// the user query under QueryKey is kept in the context if configured, and saved by the node of SaveLambda.
+// the session ID is got from the context, see WithSessionID.
+func (m *Memory) LoadLambda() *compose.Lambda {
+	return compose.InvokableLambda(func(ctx context.Context, vs map[string]any) (map[string]any, error) {
+		s, err := sessionFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var query string
+		if m.queryKey != "" {
+			var ok bool
+			if query, ok = vs[m.queryKey].(string); !ok {
+				return nil, fmt.Errorf("query of key[%s] is not a string: %T", m.queryKey, vs[m.queryKey])
+			}
+		}
+
+		ret, err := m.LoadVariables(ctx, s.id, vs)
+		if err != nil {
+			return nil, err
+		}
+		if m.queryKey != "" {
+			s.setQuery(schema.UserMessage(query))
+		}
+		return ret, nil
+	})
+}
+
+// SaveLambda returns the graph node saving the output message of the model to the history of the session, and outputs the message as is.
+// the user query kept by the node of LoadLambda is saved before the message.
+// the session ID is got from the context, see WithSessionID.
+func (m *Memory) SaveLambda() *compose.Lambda {
+	return compose.InvokableLambda(func(ctx context.Context, msg *schema.Message) (*schema.Message, error) {
+		s, err := sessionFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err = m.Save(ctx, s.id, append(s.takeQuery(), msg)...); err != nil {
+			return nil, err
+		}
+		return msg, nil
+	})
+}
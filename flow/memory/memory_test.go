@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/model"
+	"github.com/mrh997/eino/components/prompt"
+	"github.com/mrh997/eino/compose"
+	"github.com/mrh997/eino/schema"
+)
+
+// echoModel answers with the number of the input messages and the last content.
+type echoModel struct {
+	calls int
+}
+
+func (e *echoModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	e.calls++
+	if input[len(input)-1].Content == "fail" {
+		return nil, errors.New("model unavailable")
+	}
+	return schema.AssistantMessage(fmt.Sprintf("%d:%s", len(input), input[len(input)-1].Content), nil), nil
+}
+
+func (e *echoModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := e.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewMemory(&Config{})
+	assert.Error(t, err)
+
+	mem, err := NewMemory(&Config{History: NewInMemoryChatHistory(), QueryKey: "query"})
+	assert.NoError(t, err)
+
+	template := prompt.FromMessages(schema.FString,
+		schema.SystemMessage("you are a helpful assistant"),
+		schema.MessagesPlaceholder("history", true),
+		schema.UserMessage("{query}"),
+	)
+	chain := compose.NewChain[map[string]any, *schema.Message]()
+	chain.AppendLambda(mem.LoadLambda()).AppendChatTemplate(template).AppendChatModel(&echoModel{}).AppendLambda(mem.SaveLambda())
+	r, err := chain.Compile(ctx)
+	assert.NoError(t, err)
+
+	_, err = r.Invoke(ctx, map[string]any{"query": "hi"})
+	assert.ErrorContains(t, err, "session id not found")
+
+	sessionCtx := WithSessionID(ctx, "s1")
+	out, err := r.Invoke(sessionCtx, map[string]any{"query": "hi"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2:hi", out.Content)
+	// neither the query nor the answer is saved if the model fails
+	_, err = r.Invoke(sessionCtx, map[string]any{"query": "fail"})
+	assert.ErrorContains(t, err, "model unavailable")
+	out, err = r.Invoke(sessionCtx, map[string]any{"query": "again"})
+	assert.NoError(t, err)
+	assert.Equal(t, "4:again", out.Content)
+
+	history, err := mem.Load(ctx, "s1")
+	assert.NoError(t, err)
+	contents := make([]string, len(history))
+	for i, msg := range history {
+		contents[i] = string(msg.Role) + ":" + msg.Content
+	}
+	assert.Equal(t, "user:hi,assistant:2:hi,user:again,assistant:4:again", strings.Join(contents, ","))
+
+	// other sessions are isolated
+	history, err = mem.Load(ctx, "s2")
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+
+	assert.NoError(t, mem.Clear(ctx, "s1"))
+	history, err = mem.Load(ctx, "s1")
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
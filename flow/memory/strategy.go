@@ -0,0 +1,198 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mrh997/eino/components/model"
+	"github.com/mrh997/eino/schema"
+)
+
+// Strategy selects the messages given to the model from the history of the session.
+// a strategy keeping states of the sessions should implement StrategyResetter.
+type Strategy interface {
+	Select(ctx context.Context, sessionID string, history []*schema.Message) ([]*schema.Message, error)
+}
+
+// StrategyResetter is implemented by the Strategy keeping states of the sessions, e.g. the summaries,
+// which are dropped by Memory.Clear with the history, so that nothing of the cleared history is selected again.
+type StrategyResetter interface {
+	Reset(ctx context.Context, sessionID string) error
+}
+
+// NewBufferStrategy creates a Strategy selecting the whole history.
+func NewBufferStrategy() Strategy {
+	return bufferStrategy{}
+}
+
+type bufferStrategy struct{}
+
+func (bufferStrategy) Select(_ context.Context, _ string, history []*schema.Message) ([]*schema.Message, error) {
+	return history, nil
+}
+
+// NewWindowStrategy creates a Strategy selecting at most the last maxMessages messages of the history.
+// tool messages at the start of the window are dropped, as the assistant message calling the tools is out of the window.
+func NewWindowStrategy(maxMessages int) (Strategy, error) {
+	if maxMessages <= 0 {
+		return nil, fmt.Errorf("max messages of window must be positive, got %d", maxMessages)
+	}
+	return windowStrategy{maxMessages: maxMessages}, nil
+}
+
+type windowStrategy struct {
+	maxMessages int
+}
+
+func (w windowStrategy) Select(_ context.Context, _ string, history []*schema.Message) ([]*schema.Message, error) {
+	start := 0
+	if len(history) > w.maxMessages {
+		start = len(history) - w.maxMessages
+	}
+	for start < len(history) && history[start].Role == schema.Tool {
+		start++
+	}
+	return history[start:], nil
+}
+
+const defaultSummaryPrompt = `Summarize the conversation below concisely, keeping the facts, decisions and open questions needed to continue it.
+If there is a previous summary, merge it with the new messages into one summary.`
+
+// SummaryConfig is the config of the rolling summary strategy.
+type SummaryConfig struct {
+	// Model summarizes the earlier messages.
+	Model model.BaseChatModel
+	// KeepRecent is the number of the latest messages kept as they are, the earlier messages are summarized.
+	KeepRecent int
+	// Prompt is the system prompt instructing the model to summarize.
+	// Optional. By default, the model is asked for a concise summary keeping the facts, decisions and open questions.
+	Prompt string
+}
+
+// NewSummaryStrategy creates a Strategy selecting a system message of the summary of the earlier messages and the latest messages.
+// the summary is rolling, i.e. the messages falling out of the latest ones are merged into the previous summary of the session,
+// rather than summarizing the whole history again.
+// the summaries are cached in memory, rebuilt from the history after restarting, and dropped by Memory.Clear.
+func NewSummaryStrategy(conf *SummaryConfig) (Strategy, error) {
+	if conf == nil || conf.Model == nil {
+		return nil, errors.New("model of summary strategy is required")
+	}
+	if conf.KeepRecent < 0 {
+		return nil, fmt.Errorf("keep recent of summary strategy must not be negative, got %d", conf.KeepRecent)
+	}
+	s := &summaryStrategy{
+		model:      conf.Model,
+		keepRecent: conf.KeepRecent,
+		prompt:     conf.Prompt,
+		summaries:  make(map[string]*summary),
+	}
+	if s.prompt == "" {
+		s.prompt = defaultSummaryPrompt
+	}
+	return s, nil
+}
+
+type summaryStrategy struct {
+	model      model.BaseChatModel
+	keepRecent int
+	prompt     string
+
+	mu        sync.Mutex
+	summaries map[string]*summary
+}
+
+type summary struct {
+	// summarized is the number of the messages at the start of the history merged into the content
+	summarized int
+	content    string
+}
+
+func (s *summaryStrategy) Select(ctx context.Context, sessionID string, history []*schema.Message) ([]*schema.Message, error) {
+	cut := len(history) - s.keepRecent
+	// don't separate the tool messages from the assistant message calling the tools
+	for cut > 0 && cut < len(history) && history[cut].Role == schema.Tool {
+		cut--
+	}
+	if cut <= 0 {
+		return history, nil
+	}
+
+	s.mu.Lock()
+	sum, ok := s.summaries[sessionID]
+	s.mu.Unlock()
+	// the history is cleared or rewritten
+	if !ok || sum.summarized > cut {
+		sum = &summary{}
+	}
+
+	if sum.summarized < cut {
+		content, err := s.summarize(ctx, sum.content, history[sum.summarized:cut])
+		if err != nil {
+			return nil, err
+		}
+		sum = &summary{summarized: cut, content: content}
+		s.mu.Lock()
+		s.summaries[sessionID] = sum
+		s.mu.Unlock()
+	}
+
+	ret := make([]*schema.Message, 0, len(history)-cut+1)
+	ret = append(ret, schema.SystemMessage("Summary of the earlier conversation:\n"+sum.content))
+	ret = append(ret, history[cut:]...)
+	return ret, nil
+}
+
+// Reset drops the cached summary of the session.
+func (s *summaryStrategy) Reset(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.summaries, sessionID)
+	return nil
+}
+
+func (s *summaryStrategy) summarize(ctx context.Context, previous string, msgs []*schema.Message) (string, error) {
+	sb := strings.Builder{}
+	if previous != "" {
+		sb.WriteString("Previous summary:\n")
+		sb.WriteString(previous)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("New messages:\n")
+	for _, msg := range msgs {
+		sb.WriteString(string(msg.Role))
+		sb.WriteString(": ")
+		sb.WriteString(msg.Content)
+		for _, tc := range msg.ToolCalls {
+			sb.WriteString(fmt.Sprintf("\n[call tool %s with %s]", tc.Function.Name, tc.Function.Arguments))
+		}
+		sb.WriteString("\n")
+	}
+
+	out, err := s.model.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(s.prompt),
+		schema.UserMessage(sb.String()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize history: %w", err)
+	}
+	return out.Content, nil
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/schema"
+)
+
+func TestStrategies(t *testing.T) {
+	ctx := context.Background()
+	history := []*schema.Message{
+		schema.UserMessage("u1"),
+		schema.AssistantMessage("a1", nil),
+		schema.UserMessage("u2"),
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "search"}}}),
+		schema.ToolMessage("t1", "1"),
+		schema.AssistantMessage("a2", nil),
+	}
+
+	t.Run("buffer", func(t *testing.T) {
+		msgs, err := NewBufferStrategy().Select(ctx, "s", history)
+		assert.NoError(t, err)
+		assert.Equal(t, history, msgs)
+	})
+
+	t.Run("window", func(t *testing.T) {
+		_, err := NewWindowStrategy(0)
+		assert.Error(t, err)
+
+		w, err := NewWindowStrategy(3)
+		assert.NoError(t, err)
+		msgs, err := w.Select(ctx, "s", history)
+		assert.NoError(t, err)
+		assert.Equal(t, history[3:], msgs)
+
+		// the tool message without its tool call is dropped
+		w, err = NewWindowStrategy(2)
+		assert.NoError(t, err)
+		msgs, err = w.Select(ctx, "s", history)
+		assert.NoError(t, err)
+		assert.Equal(t, history[5:], msgs)
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		_, err := NewSummaryStrategy(&SummaryConfig{})
+		assert.Error(t, err)
+
+		m := &echoModel{}
+		s, err := NewSummaryStrategy(&SummaryConfig{Model: m, KeepRecent: 2})
+		assert.NoError(t, err)
+
+		// the recent messages don't start with the tool message
+		msgs, err := s.Select(ctx, "s", history)
+		assert.NoError(t, err)
+		assert.Len(t, msgs, 4)
+		assert.Equal(t, schema.System, msgs[0].Role)
+		assert.Contains(t, msgs[0].Content, "New messages:\nuser: u1\nassistant: a1\nuser: u2\n")
+		assert.Equal(t, history[3:], msgs[1:])
+		assert.Equal(t, 1, m.calls)
+
+		// the summary is reused until more messages fall out of the recent ones
+		_, err = s.Select(ctx, "s", history)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, m.calls)
+
+		more := append(history, schema.UserMessage("u3"), schema.AssistantMessage("a3", nil))
+		msgs, err = s.Select(ctx, "s", more)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, m.calls)
+		assert.Contains(t, msgs[0].Content, "Previous summary:")
+		assert.Equal(t, more[6:], msgs[1:])
+
+		// short history is not summarized
+		msgs, err = s.Select(ctx, "other", history[:2])
+		assert.NoError(t, err)
+		assert.Equal(t, history[:2], msgs)
+	})
+	t.Run("summary reset by clear", func(t *testing.T) {
+		s, err := NewSummaryStrategy(&SummaryConfig{Model: &echoModel{}, KeepRecent: 2})
+		assert.NoError(t, err)
+		mem, err := NewMemory(&Config{History: NewInMemoryChatHistory(), Strategy: s})
+		assert.NoError(t, err)
+
+		turns := func(prefix string, n int) []*schema.Message {
+			var msgs []*schema.Message
+			for i := 0; i < n; i++ {
+				msgs = append(msgs, schema.UserMessage(prefix+" question"), schema.AssistantMessage(prefix+" answer", nil))
+			}
+			return msgs
+		}
+
+		assert.NoError(t, mem.Save(ctx, "s", turns("secret", 3)...))
+		msgs, err := mem.Load(ctx, "s")
+		assert.NoError(t, err)
+		assert.Contains(t, msgs[0].Content, "secret")
+
+		// the new history grows past the summarized messages of the cleared one
+		assert.NoError(t, mem.Clear(ctx, "s"))
+		assert.NoError(t, mem.Save(ctx, "s", turns("public", 4)...))
+		msgs, err = mem.Load(ctx, "s")
+		assert.NoError(t, err)
+		assert.Contains(t, msgs[0].Content, "public")
+		assert.NotContains(t, msgs[0].Content, "secret")
+	})
+}
@@ -19,7 +19,6 @@ package multiquery
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/mrh997/eino/callbacks"
 	"github.com/mrh997/eino/components/model"
@@ -90,9 +89,7 @@ func NewRetriever(ctx context.Context, config *Config) (retriever.Retriever, err
 			variable = defaultQueryVariable
 		}
 		if parser == nil {
-			parser = func(ctx context.Context, message *schema.Message) ([]string, error) {
-				return strings.Split(message.Content, "\n"), nil
-			}
+			parser = schema.NewMessageListParser(nil).Parse
 		}
 
 		rewriteChain.
@@ -136,7 +133,7 @@ type Config struct {
 	RewriteTemplate prompt.ChatTemplate
 	//	c. origin query variable of your custom template, it can be empty if you use default template
 	QueryVar string
-	//	d. parser llm output to queries, split content using "\n" by default
+	//	d. parser llm output to queries, split content into lines by default, see schema.NewMessageListParser
 	LLMOutputParser func(context.Context, *schema.Message) ([]string, error)
 	// 2. set RewriteHandler to provide custom query generation logic, possibly without a ChatModel. If this field is set, it takes precedence over other configurations above
 	RewriteHandler func(ctx context.Context, query string) ([]string, error)
@@ -0,0 +1,210 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package structured gets the structured output of a Go type from a tool calling chat model,
+// by binding a single tool with the parameters of the type and forcing the model to call it.
+package structured
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mrh997/eino/components/model"
+	"github.com/mrh997/eino/components/tool/utils"
+	"github.com/mrh997/eino/schema"
+)
+
+const (
+	defaultToolName   = "output"
+	defaultToolDesc   = "output the result in the structure of the parameters"
+	defaultMaxRetries = 2
+)
+
+// Config is the config of Generator.
+type Config[T any] struct {
+	// Model is the chat model generating the output, required.
+	Model model.ToolCallingChatModel
+	// ToolName is the name of the tool whose parameters are derived from T.
+	// Optional. Default "output".
+	ToolName string
+	// ToolDesc is the description of the tool, tell the model what the output is for here.
+	// Optional. Default "output the result in the structure of the parameters".
+	ToolDesc string
+	// MaxRetries is the max number of retries after the output fails to parse or validate,
+	// the error is fed back to the model in each retry.
+	// Optional. Default 2, set a negative value to disable retries.
+	MaxRetries int
+	// RepairJSON repairs the common mistakes in the arguments generated by the model before parsing, see schema.RepairJSON.
+	// Optional. Default false.
+	RepairJSON bool
+	// Validator validates the parsed output beyond the JSON schema of T, e.g. the range of a field.
+	// the error is fed back to the model in the retry.
+	// Optional.
+	Validator func(ctx context.Context, output T) error
+}
+
+// Generator generates the output of T by a tool calling chat model.
+// e.g.
+//
+//	type Weather struct {
+//		City        string `json:"city" jsonschema:"description=the city name"`
+//		Temperature int    `json:"temperature"`
+//	}
+//
+//	g, err := structured.NewGenerator(ctx, &structured.Config[Weather]{Model: chatModel})
+//	weather, err := g.Generate(ctx, []*schema.Message{schema.UserMessage("beijing is sunny, 25 degrees today")})
+type Generator[T any] struct {
+	model      model.ToolCallingChatModel
+	info       *schema.ToolInfo
+	parser     schema.MessageParser[T]
+	maxRetries int
+	repairJSON bool
+	validator  func(ctx context.Context, output T) error
+}
+
+// NewGenerator creates a Generator, binding the tool of T to the model.
+func NewGenerator[T any](_ context.Context, conf *Config[T]) (*Generator[T], error) {
+	if conf == nil || conf.Model == nil {
+		return nil, errors.New("model is required")
+	}
+
+	name, desc := conf.ToolName, conf.ToolDesc
+	if name == "" {
+		name = defaultToolName
+	}
+	if desc == "" {
+		desc = defaultToolDesc
+	}
+	info, err := utils.GoStruct2ToolInfo[T](name, desc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to infer tool info of output: %w", err)
+	}
+	m, err := conf.Model.WithTools([]*schema.ToolInfo{info})
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind output tool: %w", err)
+	}
+
+	g := &Generator[T]{
+		model:      m,
+		info:       info,
+		maxRetries: conf.MaxRetries,
+		repairJSON: conf.RepairJSON,
+		validator:  conf.Validator,
+		parser: schema.NewMessageJSONParser[T](&schema.MessageJSONParseConfig{
+			ParseFrom:  schema.MessageParseFromToolCall,
+			RepairJSON: conf.RepairJSON,
+		}),
+	}
+	if g.maxRetries == 0 {
+		g.maxRetries = defaultMaxRetries
+	} else if g.maxRetries < 0 {
+		g.maxRetries = 0
+	}
+	return g, nil
+}
+
+// ToolInfo returns the info of the tool bound to the model.
+func (g *Generator[T]) ToolInfo() *schema.ToolInfo {
+	return g.info
+}
+
+// Generate calls the model with the input messages and the tool choice forced, and returns the parsed output.
+// when the output fails to parse or validate, the model is called again with the error fed back,
+// and the last error is returned when the retries are exhausted.
+func (g *Generator[T]) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (output T, err error) {
+	opts = append(opts, model.WithToolChoice(schema.ToolChoiceForced))
+	msgs := make([]*schema.Message, len(input), len(input)+2*g.maxRetries)
+	copy(msgs, input)
+
+	for attempt := 0; ; attempt++ {
+		out, err := g.model.Generate(ctx, msgs, opts...)
+		if err != nil {
+			return output, fmt.Errorf("failed to generate output: %w", err)
+		}
+
+		output, err = g.parse(ctx, out)
+		if err == nil {
+			return output, nil
+		}
+		if attempt >= g.maxRetries {
+			return output, fmt.Errorf("failed to get valid output after %d attempts: %w", attempt+1, err)
+		}
+		msgs = append(msgs, g.feedback(out, err)...)
+	}
+}
+
+func (g *Generator[T]) parse(ctx context.Context, out *schema.Message) (output T, err error) {
+	if len(out.ToolCalls) == 0 {
+		return output, fmt.Errorf("no call of tool %s", g.info.Name)
+	}
+	if name := out.ToolCalls[0].Function.Name; name != g.info.Name {
+		return output, fmt.Errorf("unknown tool %s is called instead of %s", name, g.info.Name)
+	}
+
+	arguments := out.ToolCalls[0].Function.Arguments
+	if g.repairJSON {
+		// the repaired arguments are what the parser parses, so they are validated instead
+		if result, rErr := schema.RepairJSON(arguments); rErr == nil {
+			arguments = result.Repaired
+		}
+	}
+	violations, err := g.info.ParamsOneOf.ValidateArguments(arguments)
+	// invalid JSON is left to the parser, which reports the syntax error
+	if err == nil && len(violations) > 0 {
+		texts := make([]string, len(violations))
+		for i, v := range violations {
+			texts[i] = v.String()
+		}
+		return output, fmt.Errorf("arguments violate the schema: %s", strings.Join(texts, "; "))
+	}
+
+	output, err = g.parser.Parse(ctx, out)
+	if err != nil {
+		return output, err
+	}
+	if g.validator != nil {
+		if err = g.validator(ctx, output); err != nil {
+			return output, fmt.Errorf("invalid output: %w", err)
+		}
+	}
+	return output, nil
+}
+
+// feedback returns the messages telling the model the error of its output.
+// the error is returned as the result of the tool call if any, so that the conversation stays valid for the model.
+func (g *Generator[T]) feedback(out *schema.Message, err error) []*schema.Message {
+	if len(out.ToolCalls) == 0 {
+		return []*schema.Message{
+			out,
+			schema.UserMessage(fmt.Sprintf("You must call the tool %s to output the result.", g.info.Name)),
+		}
+	}
+
+	// only the first tool call is kept, as the others are not answered
+	call := &schema.Message{
+		Role:             out.Role,
+		Content:          out.Content,
+		ReasoningContent: out.ReasoningContent,
+		ToolCalls:        out.ToolCalls[:1],
+	}
+	return []*schema.Message{
+		call,
+		schema.ToolMessage(fmt.Sprintf("Error: %v\nCall the tool %s again with corrected arguments.", err, g.info.Name),
+			out.ToolCalls[0].ID, schema.WithToolName(out.ToolCalls[0].Function.Name)),
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package structured
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/components/model"
+	"github.com/mrh997/eino/schema"
+)
+
+type weather struct {
+	City        string `json:"city"`
+	Temperature int    `json:"temperature"`
+}
+
+// scriptedModel returns the arguments of the tool calls in order, and records the inputs.
+type scriptedModel struct {
+	tools     []*schema.ToolInfo
+	arguments []string
+	inputs    [][]*schema.Message
+	choices   []*schema.ToolChoice
+}
+
+func (s *scriptedModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	s.inputs = append(s.inputs, input)
+	s.choices = append(s.choices, model.GetCommonOptions(&model.Options{}, opts...).ToolChoice)
+
+	args := s.arguments[0]
+	s.arguments = s.arguments[1:]
+	if args == "" {
+		return schema.AssistantMessage("no tool", nil), nil
+	}
+	return schema.AssistantMessage("", []schema.ToolCall{{ID: "call", Function: schema.FunctionCall{Name: s.tools[0].Name, Arguments: args}}}), nil
+}
+
+func (s *scriptedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *scriptedModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	s.tools = tools
+	return s, nil
+}
+
+func TestGenerator(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{schema.UserMessage("beijing is sunny, 25 degrees")}
+
+	_, err := NewGenerator(ctx, &Config[weather]{})
+	assert.Error(t, err)
+
+	t.Run("retry with feedback", func(t *testing.T) {
+		m := &scriptedModel{arguments: []string{``, `{"city": 1}`, `{"city": "beijing", "temperature": 25}`}}
+		g, err := NewGenerator(ctx, &Config[weather]{Model: m, ToolName: "weather"})
+		assert.NoError(t, err)
+		assert.Equal(t, "weather", m.tools[0].Name)
+
+		out, err := g.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, weather{City: "beijing", Temperature: 25}, out)
+
+		forced := schema.ToolChoiceForced
+		assert.Equal(t, []*schema.ToolChoice{&forced, &forced, &forced}, m.choices)
+		assert.Len(t, m.inputs[1], 3)
+		assert.Equal(t, "You must call the tool weather to output the result.", m.inputs[1][2].Content)
+		assert.Len(t, m.inputs[2], 5)
+		assert.Equal(t, schema.Tool, m.inputs[2][4].Role)
+		assert.Equal(t, "call", m.inputs[2][4].ToolCallID)
+		assert.Contains(t, m.inputs[2][4].Content, "/city: expected type string, got number")
+		// the input is not modified
+		assert.Len(t, input, 1)
+	})
+
+	t.Run("validator and exhausted retries", func(t *testing.T) {
+		m := &scriptedModel{arguments: []string{`{"city": "beijing", "temperature": 99}`, `{"city": "beijing", "temperature": 98}`}}
+		g, err := NewGenerator(ctx, &Config[weather]{
+			Model:      m,
+			MaxRetries: 1,
+			Validator: func(ctx context.Context, output weather) error {
+				if output.Temperature > 60 {
+					return errors.New("temperature is too high")
+				}
+				return nil
+			},
+		})
+		assert.NoError(t, err)
+
+		_, err = g.Generate(ctx, input)
+		assert.ErrorContains(t, err, "failed to get valid output after 2 attempts: invalid output: temperature is too high")
+		assert.Contains(t, m.inputs[1][2].Content, "temperature is too high")
+	})
+
+	t.Run("repair json", func(t *testing.T) {
+		m := &scriptedModel{arguments: []string{`{city: 'beijing', temperature: 25,}`}}
+		g, err := NewGenerator(ctx, &Config[*weather]{Model: m, MaxRetries: -1, RepairJSON: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "output", g.ToolInfo().Name)
+
+		out, err := g.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, &weather{City: "beijing", Temperature: 25}, out)
+	})
+
+	t.Run("repaired json violating schema", func(t *testing.T) {
+		m := &scriptedModel{arguments: []string{`{city: 'beijing', temperature: 'hot',}`}}
+		g, err := NewGenerator(ctx, &Config[*weather]{Model: m, MaxRetries: -1, RepairJSON: true})
+		assert.NoError(t, err)
+
+		_, err = g.Generate(ctx, input)
+		assert.ErrorContains(t, err, "arguments violate the schema")
+		assert.ErrorContains(t, err, "temperature")
+	})
+}
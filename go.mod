@@ -8,6 +8,7 @@ require (
 	github.com/bytedance/sonic v1.13.2
 	github.com/eino-contrib/jsonschema v1.0.0
 	github.com/getkin/kin-openapi v0.118.0
+	github.com/invopop/yaml v0.1.0
 	github.com/nikolalohinski/gonja v1.5.3
 	github.com/slongfield/pyfmt v0.0.0-20220222012616-ea85ff4c361f
 	github.com/smartystreets/goconvey v1.8.1
@@ -27,7 +28,6 @@ require (
 	github.com/go-openapi/swag v0.19.5 // indirect
 	github.com/goph/emperror v0.17.2 // indirect
 	github.com/gopherjs/gopherjs v1.17.2 // indirect
-	github.com/invopop/yaml v0.1.0 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/jtolds/gls v4.20.0+incompatible // indirect
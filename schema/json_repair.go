@@ -0,0 +1,376 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JSONRepairKind is a kind of mistake fixed by RepairJSON.
+type JSONRepairKind string
+
+const (
+	// JSONRepairCodeFence means the JSON was wrapped in a markdown code fence.
+	JSONRepairCodeFence JSONRepairKind = "code_fence"
+	// JSONRepairStrayText means there was text before or after the JSON.
+	JSONRepairStrayText JSONRepairKind = "stray_text"
+	// JSONRepairTrailingComma means there were trailing or redundant commas.
+	JSONRepairTrailingComma JSONRepairKind = "trailing_comma"
+	// JSONRepairSingleQuotes means strings were quoted with single quotes.
+	JSONRepairSingleQuotes JSONRepairKind = "single_quotes"
+	// JSONRepairUnquotedKey means object keys were not quoted.
+	JSONRepairUnquotedKey JSONRepairKind = "unquoted_key"
+	// JSONRepairMissingClose means the JSON was truncated, closing quotes and brackets were missing.
+	JSONRepairMissingClose JSONRepairKind = "missing_close"
+	// JSONRepairControlChar means strings contained raw control characters, e.g. line breaks and tabs.
+	JSONRepairControlChar JSONRepairKind = "control_char"
+)
+
+// JSONRepairResult is the result of RepairJSON.
+type JSONRepairResult struct {
+	Original string
+	Repaired string
+	// Repairs are the kinds of mistakes fixed, in the order found, empty if the original is valid JSON.
+	Repairs []JSONRepairKind
+}
+
+// RepairJSON fixes the common mistakes in the JSON generated by models:
+// code fences around the JSON, stray text before or after the JSON, trailing commas,
+// single quoted strings, unquoted keys and missing closing quotes and brackets of truncated JSON.
+// valid JSON is returned as is, and an error is returned if the input can't be repaired into a valid JSON object or array.
+// e.g.
+//
+//	result, err := schema.RepairJSON("```json\n{name: 'eino', tags: ['a', 'b',],\n```")
+//	// result.Repaired: {"name": "eino", "tags": ["a", "b"]}
+func RepairJSON(input string) (*JSONRepairResult, error) {
+	result := &JSONRepairResult{Original: input, Repaired: input}
+	if json.Valid([]byte(input)) {
+		return result, nil
+	}
+
+	r := &jsonRepairer{}
+	s := input
+	if inner, ok := extractCodeFence(s); ok {
+		s = inner
+		r.add(JSONRepairCodeFence)
+	}
+
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return nil, errors.New("failed to repair json: no json object or array found")
+	}
+	if strings.TrimSpace(s[:start]) != "" {
+		r.add(JSONRepairStrayText)
+	}
+
+	rest := r.rewrite(s[start:])
+	if strings.TrimSpace(rest) != "" {
+		r.add(JSONRepairStrayText)
+	}
+
+	repaired := r.out.String()
+	if !json.Valid([]byte(repaired)) {
+		return nil, fmt.Errorf("failed to repair json: %s", repaired)
+	}
+
+	result.Repaired = repaired
+	result.Repairs = r.repairs
+	return result, nil
+}
+
+// extractCodeFence returns the content of the first markdown code fence, the closing fence can be missing.
+func extractCodeFence(s string) (string, bool) {
+	start := strings.Index(s, "```")
+	if start < 0 {
+		return "", false
+	}
+	body := s[start+3:]
+	// skip the language tag
+	if nl := strings.IndexByte(body, '\n'); nl >= 0 {
+		body = body[nl+1:]
+	} else {
+		return "", false
+	}
+	if end := strings.Index(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+	return body, true
+}
+
+type jsonRepairState int
+
+const (
+	expectValue jsonRepairState = iota
+	expectKey
+	expectColon
+	expectComma
+)
+
+type jsonRepairFrame struct {
+	object bool
+	state  jsonRepairState
+}
+
+type jsonRepairer struct {
+	out          strings.Builder
+	repairs      []JSONRepairKind
+	stack        []*jsonRepairFrame
+	pendingComma bool
+	// whitespaces after the pending comma
+	pendingSpace strings.Builder
+}
+
+func (r *jsonRepairer) add(kind JSONRepairKind) {
+	for _, k := range r.repairs {
+		if k == kind {
+			return
+		}
+	}
+	r.repairs = append(r.repairs, kind)
+}
+
+func (r *jsonRepairer) top() *jsonRepairFrame {
+	return r.stack[len(r.stack)-1]
+}
+
+// beforeToken writes the comma postponed to here, so that trailing commas can be dropped.
+func (r *jsonRepairer) beforeToken() {
+	if r.pendingComma {
+		r.out.WriteByte(',')
+		r.dropComma()
+	}
+}
+
+// dropComma clears the pending comma, and writes the whitespaces after it.
+func (r *jsonRepairer) dropComma() {
+	r.pendingComma = false
+	r.out.WriteString(r.pendingSpace.String())
+	r.pendingSpace.Reset()
+}
+
+// afterValue moves the state of the enclosing container after a value is written.
+func (r *jsonRepairer) afterValue() {
+	if len(r.stack) > 0 {
+		r.top().state = expectComma
+	}
+}
+
+// completeMember writes null for an object member missing its value.
+func (r *jsonRepairer) completeMember(f *jsonRepairFrame) {
+	if !f.object {
+		return
+	}
+	switch f.state {
+	case expectColon:
+		r.out.WriteString(":null")
+	case expectValue:
+		r.out.WriteString("null")
+	}
+}
+
+func (r *jsonRepairer) closeFrame() {
+	f := r.top()
+	if r.pendingComma {
+		r.dropComma()
+		r.add(JSONRepairTrailingComma)
+	}
+	r.completeMember(f)
+	if f.object {
+		r.out.WriteByte('}')
+	} else {
+		r.out.WriteByte(']')
+	}
+	r.stack = r.stack[:len(r.stack)-1]
+	r.afterValue()
+}
+
+// rewrite writes the repaired JSON of the first value in s, and returns the text after it.
+func (r *jsonRepairer) rewrite(s string) string {
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if r.pendingComma {
+				r.pendingSpace.WriteByte(c)
+			} else {
+				r.out.WriteByte(c)
+			}
+			i++
+		case c == '{' || c == '[':
+			r.beforeToken()
+			r.out.WriteByte(c)
+			f := &jsonRepairFrame{object: c == '{', state: expectValue}
+			if f.object {
+				f.state = expectKey
+			}
+			r.stack = append(r.stack, f)
+			i++
+		case c == '}' || c == ']':
+			r.closeFrame()
+			i++
+			if len(r.stack) == 0 {
+				return s[i:]
+			}
+		case c == ',':
+			if f := r.top(); f.state == expectComma && !r.pendingComma {
+				r.pendingComma = true
+				if f.object {
+					f.state = expectKey
+				} else {
+					f.state = expectValue
+				}
+			} else {
+				r.add(JSONRepairTrailingComma)
+			}
+			i++
+		case c == ':':
+			if f := r.top(); f.object && f.state == expectColon {
+				r.out.WriteByte(':')
+				f.state = expectValue
+			}
+			i++
+		case c == '"' || c == '\'':
+			r.beforeToken()
+			i = r.rewriteString(s, i)
+			if f := r.top(); f.object && f.state == expectKey {
+				f.state = expectColon
+			} else {
+				r.afterValue()
+			}
+		default:
+			end := i
+			for end < len(s) && !strings.ContainsRune(" \t\r\n,:{}[]\"'", rune(s[end])) {
+				end++
+			}
+			token := s[i:end]
+			f := r.top()
+			isKey := f.object && f.state == expectKey
+			if end == len(s) && !isKey {
+				token = completeLiteral(token)
+			}
+			i = end
+			if token == "" {
+				break
+			}
+			r.beforeToken()
+			if isKey {
+				r.add(JSONRepairUnquotedKey)
+				b, _ := json.Marshal(token)
+				r.out.Write(b)
+				f.state = expectColon
+			} else {
+				r.out.WriteString(token)
+				r.afterValue()
+			}
+		}
+	}
+
+	// the input is truncated, a comma at the end is not a mistake
+	r.dropComma()
+	if len(r.stack) > 0 {
+		r.add(JSONRepairMissingClose)
+	}
+	for len(r.stack) > 0 {
+		r.closeFrame()
+	}
+	return ""
+}
+
+// completeLiteral completes a literal truncated at the end of the input.
+func completeLiteral(token string) string {
+	for _, literal := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(literal, token) {
+			return literal
+		}
+	}
+	return strings.TrimRight(token, ".eE+-")
+}
+
+// rewriteString writes the string starting at s[i] in double quotes, and returns the index after it.
+func (r *jsonRepairer) rewriteString(s string, i int) int {
+	quote := s[i]
+	if quote == '\'' {
+		r.add(JSONRepairSingleQuotes)
+	}
+
+	r.out.WriteByte('"')
+	i++
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			if s[i+1] == '\'' {
+				r.out.WriteByte('\'')
+			} else {
+				r.out.WriteByte(c)
+				r.out.WriteByte(s[i+1])
+			}
+			i += 2
+		case c == quote:
+			r.out.WriteByte('"')
+			return i + 1
+		case c == '"':
+			r.out.WriteString(`\"`)
+			i++
+		case c < 0x20:
+			r.add(JSONRepairControlChar)
+			switch c {
+			case '\n':
+				r.out.WriteString(`\n`)
+			case '\r':
+				r.out.WriteString(`\r`)
+			case '\t':
+				r.out.WriteString(`\t`)
+			default:
+				fmt.Fprintf(&r.out, `\u%04x`, c)
+			}
+			i++
+		case c == '\\':
+			// a dangling backslash at the end of truncated input
+			i++
+		default:
+			r.out.WriteByte(c)
+			i++
+		}
+	}
+
+	r.add(JSONRepairMissingClose)
+	r.out.WriteByte('"')
+	return i
+}
+
+type jsonRepairReporterKey struct{}
+
+// WithJSONRepairReporter returns a context with which MessageJSONParser reports the repairs it makes, see MessageJSONParseConfig.RepairJSON.
+// in graphs, the repairs made by parsers of compose.MessageParser are reported by callbacks without setting this.
+func WithJSONRepairReporter(ctx context.Context, reporter func(ctx context.Context, result *JSONRepairResult)) context.Context {
+	return context.WithValue(ctx, jsonRepairReporterKey{}, reporter)
+}
+
+func reportJSONRepair(ctx context.Context, result *JSONRepairResult) {
+	if ctx == nil || len(result.Repairs) == 0 {
+		return
+	}
+	if reporter, ok := ctx.Value(jsonRepairReporterKey{}).(func(ctx context.Context, result *JSONRepairResult)); ok && reporter != nil {
+		reporter(ctx, result)
+	}
+}
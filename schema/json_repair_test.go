@@ -0,0 +1,141 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepairJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+		repairs  []JSONRepairKind
+	}{
+		{
+			name:     "valid",
+			input:    `{"a": [1, 2]}`,
+			expected: `{"a": [1, 2]}`,
+		},
+		{
+			name:     "code fence",
+			input:    "here you are:\n```json\n{\"a\": 1}\n```\nbye",
+			expected: "{\"a\": 1}",
+			repairs:  []JSONRepairKind{JSONRepairCodeFence},
+		},
+		{
+			name:     "stray text",
+			input:    `The result is {"a": 1}. Hope it helps!`,
+			expected: `{"a": 1}`,
+			repairs:  []JSONRepairKind{JSONRepairStrayText},
+		},
+		{
+			name:     "trailing commas",
+			input:    `{"a": [1, 2,], "b": 3,}`,
+			expected: `{"a": [1, 2], "b": 3}`,
+			repairs:  []JSONRepairKind{JSONRepairTrailingComma},
+		},
+		{
+			name:     "single quotes",
+			input:    `{'a': 'it\'s "ok"'}`,
+			expected: `{"a": "it's \"ok\""}`,
+			repairs:  []JSONRepairKind{JSONRepairSingleQuotes},
+		},
+		{
+			name:     "unquoted keys",
+			input:    `{a: 1, b_c: {d: true}}`,
+			expected: `{"a": 1, "b_c": {"d": true}}`,
+			repairs:  []JSONRepairKind{JSONRepairUnquotedKey},
+		},
+		{
+			name:     "truncated",
+			input:    `{"a": [1, {"b": "hel`,
+			expected: `{"a": [1, {"b": "hel"}]}`,
+			repairs:  []JSONRepairKind{JSONRepairMissingClose},
+		},
+		{
+			name:     "truncated after key",
+			input:    `{"a": 1, "b"`,
+			expected: `{"a": 1, "b":null}`,
+			repairs:  []JSONRepairKind{JSONRepairMissingClose},
+		},
+		{
+			name:     "truncated literal",
+			input:    `[1, tr`,
+			expected: `[1, true]`,
+			repairs:  []JSONRepairKind{JSONRepairMissingClose},
+		},
+		{
+			name:     "control characters",
+			input:    "{\"a\": \"line1\nline2\tend\r\x01\"}",
+			expected: `{"a": "line1\nline2\tend\r\u0001"}`,
+			repairs:  []JSONRepairKind{JSONRepairControlChar},
+		},
+		{
+			name:     "all together",
+			input:    "```json\n{name: 'eino', tags: ['a', 'b',],\n```",
+			expected: "{\"name\": \"eino\", \"tags\": [\"a\", \"b\"]\n}",
+			repairs: []JSONRepairKind{JSONRepairCodeFence, JSONRepairUnquotedKey, JSONRepairSingleQuotes,
+				JSONRepairTrailingComma, JSONRepairMissingClose},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := RepairJSON(c.input)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, result.Repaired)
+			assert.Equal(t, c.repairs, result.Repairs)
+			assert.Equal(t, c.input, result.Original)
+		})
+	}
+
+	_, err := RepairJSON("no json here")
+	assert.Error(t, err)
+}
+
+func TestMessageJSONParserRepair(t *testing.T) {
+	type param struct {
+		City string `json:"city"`
+	}
+
+	var reported *JSONRepairResult
+	ctx := WithJSONRepairReporter(context.Background(), func(ctx context.Context, result *JSONRepairResult) {
+		reported = result
+	})
+
+	parser := NewMessageJSONParser[param](&MessageJSONParseConfig{RepairJSON: true})
+	p, err := parser.Parse(ctx, AssistantMessage("```json\n{city: 'paris',}\n```", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "paris", p.City)
+	assert.Equal(t, []JSONRepairKind{JSONRepairCodeFence, JSONRepairUnquotedKey, JSONRepairSingleQuotes, JSONRepairTrailingComma}, reported.Repairs)
+
+	reported = nil
+	parser = NewMessageJSONParser[param](&MessageJSONParseConfig{RepairJSON: true})
+	p, err = parser.Parse(ctx, AssistantMessage("{\"city\": \"new\nyork\"}", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "new\nyork", p.City)
+	assert.Equal(t, []JSONRepairKind{JSONRepairControlChar}, reported.Repairs)
+
+	parser = NewMessageJSONParser[param](nil)
+	_, err = parser.Parse(ctx, AssistantMessage("{city: 'paris'}", nil))
+	assert.Error(t, err)
+}
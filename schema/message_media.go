@@ -0,0 +1,264 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrMediaTooLarge is the error of a media exceeding the size limit, see WithMaxMediaSize.
+var ErrMediaTooLarge = errors.New("media too large")
+
+const defaultMaxMediaSize = 20 << 20
+
+type mediaOptions struct {
+	maxSize  int64
+	mimeType string
+	name     string
+	detail   ImageURLDetail
+}
+
+// MediaOption is the option of NewMediaPartFromFile and NewMediaPartFromReader.
+type MediaOption func(*mediaOptions)
+
+// WithMaxMediaSize limits the size of the media in bytes, non-positive means no limit.
+// Default 20MB.
+func WithMaxMediaSize(size int64) MediaOption {
+	return func(o *mediaOptions) {
+		o.maxSize = size
+	}
+}
+
+// WithMediaMIMEType sets the MIME type of the media instead of sniffing it.
+func WithMediaMIMEType(mimeType string) MediaOption {
+	return func(o *mediaOptions) {
+		o.mimeType = mimeType
+	}
+}
+
+// WithMediaName sets the name of the media, whose extension helps to detect the MIME type,
+// and which is the name of the ChatMessageFileURL. it defaults to the base name of the file for NewMediaPartFromFile.
+func WithMediaName(name string) MediaOption {
+	return func(o *mediaOptions) {
+		o.name = name
+	}
+}
+
+// WithImageURLDetail sets the detail of the ChatMessageImageURL.
+func WithImageURLDetail(detail ImageURLDetail) MediaOption {
+	return func(o *mediaOptions) {
+		o.detail = detail
+	}
+}
+
+// NewMediaPartFromFile reads the local file into a ChatMessagePart with a data URL, see NewMediaPartFromReader.
+// e.g.
+//
+//	part, err := schema.NewMediaPartFromFile("testdata/cat.png", schema.WithImageURLDetail(schema.ImageURLDetailLow))
+//	msg := &schema.Message{Role: schema.User, MultiContent: []schema.ChatMessagePart{{Type: schema.ChatMessagePartTypeText, Text: "what is it?"}, part}}
+func NewMediaPartFromFile(path string, opts ...MediaOption) (ChatMessagePart, error) {
+	o := &mediaOptions{maxSize: defaultMaxMediaSize, name: filepath.Base(path)}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ChatMessagePart{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return ChatMessagePart{}, err
+	}
+	if o.maxSize > 0 && info.Size() > o.maxSize {
+		return ChatMessagePart{}, fmt.Errorf("%w: file[%s] has %d bytes, exceeding %d bytes", ErrMediaTooLarge, path, info.Size(), o.maxSize)
+	}
+	return newMediaPart(f, o)
+}
+
+// NewMediaPartFromReader reads the media into a ChatMessagePart with a data URL,
+// whose type is image_url, audio_url or video_url by the MIME type, or file_url for the other types.
+// the MIME type is sniffed from the content, or from the extension of the name set by WithMediaName
+// if the content is only recognized as plain text or binary, unless set by WithMediaMIMEType.
+func NewMediaPartFromReader(r io.Reader, opts ...MediaOption) (ChatMessagePart, error) {
+	o := &mediaOptions{maxSize: defaultMaxMediaSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return newMediaPart(r, o)
+}
+
+func newMediaPart(r io.Reader, o *mediaOptions) (ChatMessagePart, error) {
+	if o.maxSize > 0 {
+		r = io.LimitReader(r, o.maxSize+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ChatMessagePart{}, fmt.Errorf("failed to read media: %w", err)
+	}
+	if o.maxSize > 0 && int64(len(data)) > o.maxSize {
+		return ChatMessagePart{}, fmt.Errorf("%w: exceeding %d bytes", ErrMediaTooLarge, o.maxSize)
+	}
+
+	mimeType := o.mimeType
+	if mimeType == "" {
+		mimeType = DetectMIMEType(data, o.name)
+	}
+	dataURL := EncodeDataURL(mimeType, data)
+
+	switch strings.SplitN(mimeType, "/", 2)[0] {
+	case "image":
+		return ChatMessagePart{Type: ChatMessagePartTypeImageURL, ImageURL: &ChatMessageImageURL{URL: dataURL, MIMEType: mimeType, Detail: o.detail}}, nil
+	case "audio":
+		return ChatMessagePart{Type: ChatMessagePartTypeAudioURL, AudioURL: &ChatMessageAudioURL{URL: dataURL, MIMEType: mimeType}}, nil
+	case "video":
+		return ChatMessagePart{Type: ChatMessagePartTypeVideoURL, VideoURL: &ChatMessageVideoURL{URL: dataURL, MIMEType: mimeType}}, nil
+	default:
+		return ChatMessagePart{Type: ChatMessagePartTypeFileURL, FileURL: &ChatMessageFileURL{URL: dataURL, MIMEType: mimeType, Name: o.name}}, nil
+	}
+}
+
+// DetectMIMEType returns the MIME type of the data without parameters, sniffed by http.DetectContentType,
+// or by the extension of the name if the data is only recognized as plain text or binary.
+// the extension is also preferred if the data is sniffed as a container of both audio and video, e.g. ogg and mp4,
+// and the extension gives an audio, video or image type, e.g. audio/mp4 of .m4a.
+func DetectMIMEType(data []byte, name string) string {
+	mimeType := trimMIMEParams(http.DetectContentType(data))
+	generic := mimeType == "text/plain" || mimeType == "application/octet-stream"
+	if !generic && !containerMIMETypes[mimeType] {
+		return mimeType
+	}
+
+	byExt := mimeTypeByExt(filepath.Ext(name))
+	if byExt == "" {
+		return mimeType
+	}
+	if generic || strings.HasPrefix(byExt, "audio/") || strings.HasPrefix(byExt, "video/") || strings.HasPrefix(byExt, "image/") {
+		return byExt
+	}
+	return mimeType
+}
+
+// containerMIMETypes are sniffed from the containers of both audio and video.
+var containerMIMETypes = map[string]bool{
+	"application/ogg": true,
+	"video/mp4":       true,
+	"video/webm":      true,
+}
+
+// mediaExtMIMETypes are the types of the common media extensions, which are not in the builtin table of mime.
+var mediaExtMIMETypes = map[string]string{
+	".m4a":  "audio/mp4",
+	".mp3":  "audio/mpeg",
+	".oga":  "audio/ogg",
+	".ogg":  "audio/ogg",
+	".opus": "audio/ogg",
+	".ogv":  "video/ogg",
+	".weba": "audio/webm",
+	".webm": "video/webm",
+}
+
+func mimeTypeByExt(ext string) string {
+	if ext == "" {
+		return ""
+	}
+	if mimeType, ok := mediaExtMIMETypes[strings.ToLower(ext)]; ok {
+		return mimeType
+	}
+	return trimMIMEParams(mime.TypeByExtension(ext))
+}
+
+func trimMIMEParams(mimeType string) string {
+	if t, _, err := mime.ParseMediaType(mimeType); err == nil {
+		return t
+	}
+	return mimeType
+}
+
+// EncodeDataURL returns the base64 data URL of RFC-2397 of the data, e.g. data:image/png;base64,iVBORw0KGgo...
+func EncodeDataURL(mimeType string, data []byte) string {
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// DecodeDataURL decodes the data URL of RFC-2397, either base64 or percent encoded.
+// the MIME type is text/plain if omitted in the URL.
+func DecodeDataURL(dataURL string) (mimeType string, data []byte, err error) {
+	if !strings.HasPrefix(dataURL, "data:") {
+		return "", nil, errors.New("not a data url")
+	}
+	meta, payload, found := strings.Cut(dataURL[len("data:"):], ",")
+	if !found {
+		return "", nil, errors.New("invalid data url: missing comma")
+	}
+
+	isBase64 := strings.HasSuffix(meta, ";base64")
+	mimeType = trimMIMEParams(strings.TrimSuffix(meta, ";base64"))
+	if mimeType == "" {
+		mimeType = "text/plain"
+	}
+
+	if isBase64 {
+		data, err = base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid base64 data url: %w", err)
+		}
+		return mimeType, data, nil
+	}
+	text, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid percent encoded data url: %w", err)
+	}
+	return mimeType, []byte(text), nil
+}
+
+// DecodeMediaPart decodes the data URL of the image, audio, video or file part, e.g. built by NewMediaPartFromFile.
+// the MIME type of the part is returned if set, otherwise the one of the data URL.
+func DecodeMediaPart(part ChatMessagePart) (mimeType string, data []byte, err error) {
+	var dataURL string
+	switch {
+	case part.Type == ChatMessagePartTypeImageURL && part.ImageURL != nil:
+		dataURL, mimeType = part.ImageURL.URL, part.ImageURL.MIMEType
+	case part.Type == ChatMessagePartTypeAudioURL && part.AudioURL != nil:
+		dataURL, mimeType = part.AudioURL.URL, part.AudioURL.MIMEType
+	case part.Type == ChatMessagePartTypeVideoURL && part.VideoURL != nil:
+		dataURL, mimeType = part.VideoURL.URL, part.VideoURL.MIMEType
+	case part.Type == ChatMessagePartTypeFileURL && part.FileURL != nil:
+		dataURL, mimeType = part.FileURL.URL, part.FileURL.MIMEType
+	default:
+		return "", nil, fmt.Errorf("part of type %q has no media", part.Type)
+	}
+
+	urlMIMEType, data, err := DecodeDataURL(dataURL)
+	if err != nil {
+		return "", nil, err
+	}
+	if mimeType == "" {
+		mimeType = urlMIMEType
+	}
+	return mimeType, data, nil
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaPart(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 16)...)
+	wav := append([]byte("RIFF\x00\x00\x00\x00WAVEfmt "), make([]byte, 16)...)
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{
+		"cat.png":    png,
+		"hello.wav":  wav,
+		"doc.pdf":    []byte("%PDF-1.4\n"),
+		"data.json":  []byte(`{"a":1}`),
+		"notes.text": []byte("hello"),
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o644))
+	}
+
+	t.Run("file", func(t *testing.T) {
+		part, err := NewMediaPartFromFile(filepath.Join(dir, "cat.png"), WithImageURLDetail(ImageURLDetailLow))
+		assert.NoError(t, err)
+		assert.Equal(t, ChatMessagePartTypeImageURL, part.Type)
+		assert.Equal(t, "image/png", part.ImageURL.MIMEType)
+		assert.Equal(t, ImageURLDetailLow, part.ImageURL.Detail)
+		assert.True(t, strings.HasPrefix(part.ImageURL.URL, "data:image/png;base64,iVBORw0KGgo"))
+
+		mimeType, data, err := DecodeMediaPart(part)
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", mimeType)
+		assert.Equal(t, png, data)
+
+		part, err = NewMediaPartFromFile(filepath.Join(dir, "hello.wav"))
+		assert.NoError(t, err)
+		assert.Equal(t, ChatMessagePartTypeAudioURL, part.Type)
+		assert.Equal(t, "audio/wave", part.AudioURL.MIMEType)
+
+		part, err = NewMediaPartFromFile(filepath.Join(dir, "doc.pdf"))
+		assert.NoError(t, err)
+		assert.Equal(t, ChatMessagePartTypeFileURL, part.Type)
+		assert.Equal(t, "application/pdf", part.FileURL.MIMEType)
+		assert.Equal(t, "doc.pdf", part.FileURL.Name)
+
+		// plain text is detected by the extension
+		part, err = NewMediaPartFromFile(filepath.Join(dir, "data.json"))
+		assert.NoError(t, err)
+		assert.Equal(t, "application/json", part.FileURL.MIMEType)
+		part, err = NewMediaPartFromFile(filepath.Join(dir, "notes.text"), WithMediaMIMEType("text/markdown"))
+		assert.NoError(t, err)
+		assert.Equal(t, "text/markdown", part.FileURL.MIMEType)
+
+		_, err = NewMediaPartFromFile(filepath.Join(dir, "cat.png"), WithMaxMediaSize(8))
+		assert.True(t, errors.Is(err, ErrMediaTooLarge))
+		_, err = NewMediaPartFromFile(filepath.Join(dir, "missing.png"))
+		assert.Error(t, err)
+	})
+
+	t.Run("container", func(t *testing.T) {
+		mp4 := append([]byte("\x00\x00\x00\x1cftypM4A \x00\x00\x00\x00M4A mp42isom"), make([]byte, 16)...)
+		ogg := append([]byte("OggS\x00"), make([]byte, 16)...)
+		assert.Equal(t, "video/mp4", DetectMIMEType(mp4, ""))
+		assert.Equal(t, "application/ogg", DetectMIMEType(ogg, "voice"))
+
+		// the extension is more specific than the container
+		assert.Equal(t, "audio/mp4", DetectMIMEType(mp4, "voice.m4a"))
+		assert.Equal(t, "audio/ogg", DetectMIMEType(ogg, "voice.ogg"))
+		assert.Equal(t, "video/mp4", DetectMIMEType(mp4, "clip.mp4"))
+		assert.Equal(t, "video/mp4", DetectMIMEType(mp4, "clip.txt"))
+
+		part, err := NewMediaPartFromReader(bytes.NewReader(mp4), WithMediaName("voice.m4a"))
+		assert.NoError(t, err)
+		assert.Equal(t, ChatMessagePartTypeAudioURL, part.Type)
+		assert.Equal(t, "audio/mp4", part.AudioURL.MIMEType)
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		part, err := NewMediaPartFromReader(bytes.NewReader(png))
+		assert.NoError(t, err)
+		assert.Equal(t, ChatMessagePartTypeImageURL, part.Type)
+
+		part, err = NewMediaPartFromReader(strings.NewReader("a,b\n1,2\n"), WithMediaName("table.csv"))
+		assert.NoError(t, err)
+		assert.Equal(t, ChatMessagePartTypeFileURL, part.Type)
+		assert.Equal(t, "table.csv", part.FileURL.Name)
+
+		_, err = NewMediaPartFromReader(bytes.NewReader(wav), WithMaxMediaSize(10))
+		assert.True(t, errors.Is(err, ErrMediaTooLarge))
+	})
+
+	t.Run("data url", func(t *testing.T) {
+		mimeType, data, err := DecodeDataURL("data:,hello%20world")
+		assert.NoError(t, err)
+		assert.Equal(t, "text/plain", mimeType)
+		assert.Equal(t, "hello world", string(data))
+
+		mimeType, data, err = DecodeDataURL(EncodeDataURL("text/plain;charset=utf-8", []byte("hi")))
+		assert.NoError(t, err)
+		assert.Equal(t, "text/plain", mimeType)
+		assert.Equal(t, "hi", string(data))
+
+		_, _, err = DecodeDataURL("https://example.com/cat.png")
+		assert.Error(t, err)
+		_, _, err = DecodeDataURL("data:image/png;base64,!!")
+		assert.Error(t, err)
+
+		_, _, err = DecodeMediaPart(ChatMessagePart{Type: ChatMessagePartTypeText, Text: "hi"})
+		assert.Error(t, err)
+	})
+}
@@ -43,6 +43,10 @@ type MessageJSONParseConfig struct {
 	// parse key path, default is empty.
 	// must be a valid json path expression, eg: field.sub_field
 	ParseKeyPath string `json:"parse_key_path,omitempty"`
+
+	// repair the common mistakes in model generated json before parsing, see RepairJSON.
+	// the repairs made are reported to the reporter set by WithJSONRepairReporter.
+	RepairJSON bool `json:"repair_json,omitempty"`
 }
 
 // NewMessageJSONParser creates a new MessageJSONParser.
@@ -58,6 +62,7 @@ func NewMessageJSONParser[T any](config *MessageJSONParseConfig) MessageParser[T
 	return &MessageJSONParser[T]{
 		ParseFrom:    config.ParseFrom,
 		ParseKeyPath: config.ParseKeyPath,
+		RepairJSON:   config.RepairJSON,
 	}
 }
 
@@ -81,18 +86,19 @@ func NewMessageJSONParser[T any](config *MessageJSONParseConfig) MessageParser[T
 type MessageJSONParser[T any] struct {
 	ParseFrom    MessageParseFrom
 	ParseKeyPath string
+	RepairJSON   bool
 }
 
 // Parse parses a message into an object T.
 func (p *MessageJSONParser[T]) Parse(ctx context.Context, m *Message) (parsed T, err error) {
 	if p.ParseFrom == MessageParseFromContent {
-		return p.parse(m.Content)
+		return p.parse(ctx, m.Content)
 	} else if p.ParseFrom == MessageParseFromToolCall {
 		if len(m.ToolCalls) == 0 {
 			return parsed, fmt.Errorf("no tool call found")
 		}
 
-		return p.parse(m.ToolCalls[0].Function.Arguments)
+		return p.parse(ctx, m.ToolCalls[0].Function.Arguments)
 	}
 
 	return parsed, fmt.Errorf("invalid parse from type: %s", p.ParseFrom)
@@ -124,7 +130,16 @@ func (p *MessageJSONParser[T]) extractData(data string) (string, error) {
 }
 
 // parse parses a string into an object T.
-func (p *MessageJSONParser[T]) parse(data string) (parsed T, err error) {
+func (p *MessageJSONParser[T]) parse(ctx context.Context, data string) (parsed T, err error) {
+	if p.RepairJSON {
+		result, err := RepairJSON(data)
+		if err != nil {
+			return parsed, err
+		}
+		reportJSONRepair(ctx, result)
+		data = result.Repaired
+	}
+
 	parsedData, err := p.extractData(data)
 	if err != nil {
 		return parsed, err
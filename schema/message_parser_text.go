@@ -0,0 +1,307 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NewMessageXMLTagParser creates a parser getting the sections tagged like <answer>...</answer> in the content, keyed by tag.
+// the text of each section is trimmed, the first section is used if a tag appears more than once,
+// and an error is returned if any of the tags is missing.
+// e.g.
+//
+//	parser := schema.NewMessageXMLTagParser("thinking", "answer")
+//	sections, err := parser.Parse(ctx, schema.AssistantMessage("<thinking>...</thinking><answer>42</answer>", nil))
+//	// sections["answer"]: 42
+func NewMessageXMLTagParser(tags ...string) MessageParser[map[string]string] {
+	return &messageXMLTagParser{tags: tags}
+}
+
+type messageXMLTagParser struct {
+	tags []string
+}
+
+func (p *messageXMLTagParser) Parse(_ context.Context, m *Message) (map[string]string, error) {
+	ret := make(map[string]string, len(p.tags))
+	for _, tag := range p.tags {
+		open, closing := "<"+tag+">", "</"+tag+">"
+		start := strings.Index(m.Content, open)
+		if start < 0 {
+			return nil, fmt.Errorf("tag <%s> not found", tag)
+		}
+		body := m.Content[start+len(open):]
+		end := strings.Index(body, closing)
+		if end < 0 {
+			return nil, fmt.Errorf("closing tag </%s> not found", tag)
+		}
+		ret[tag] = strings.TrimSpace(body[:end])
+	}
+	return ret, nil
+}
+
+// NewMessageCodeBlockParser creates a parser getting the first fenced code block of the language in the content,
+// language is matched case-insensitively, and an empty language matches any code block.
+// e.g.
+//
+//	parser := schema.NewMessageCodeBlockParser("sql")
+//	query, err := parser.Parse(ctx, schema.AssistantMessage("here it is:\n```sql\nSELECT 1;\n```", nil))
+//	// query: SELECT 1;
+func NewMessageCodeBlockParser(language string) MessageParser[string] {
+	return &messageCodeBlockParser{language: language}
+}
+
+type messageCodeBlockParser struct {
+	language string
+}
+
+func (p *messageCodeBlockParser) Parse(_ context.Context, m *Message) (string, error) {
+	if block, ok := findCodeBlock(m.Content, p.language); ok {
+		return block, nil
+	}
+	if p.language == "" {
+		return "", fmt.Errorf("code block not found")
+	}
+	return "", fmt.Errorf("code block of %s not found", p.language)
+}
+
+// findCodeBlock returns the body of the first fenced code block of the language, the closing fence of the last block can be missing.
+func findCodeBlock(s, language string) (string, bool) {
+	for {
+		start := strings.Index(s, "```")
+		if start < 0 {
+			return "", false
+		}
+		s = s[start+3:]
+		nl := strings.IndexByte(s, '\n')
+		if nl < 0 {
+			return "", false
+		}
+		lang := strings.TrimSpace(s[:nl])
+		s = s[nl+1:]
+
+		body := s
+		end := strings.Index(s, "```")
+		if end >= 0 {
+			body = s[:end]
+			s = s[end+3:]
+		}
+		if language == "" || strings.EqualFold(lang, language) {
+			return strings.TrimSuffix(body, "\n"), true
+		}
+		if end < 0 {
+			return "", false
+		}
+	}
+}
+
+// MessageListParseConfig is the config of the list parser.
+type MessageListParseConfig struct {
+	// Separator separates the items of the list.
+	// Optional. Default "\n".
+	Separator string
+	// KeepMarkers keeps the list markers at the start of the items, e.g. "- ", "* " and "1. ".
+	// Optional. Default false, the markers are removed.
+	KeepMarkers bool
+}
+
+var listMarker = regexp.MustCompile(`^(?:[-*•+]|\d+[.)])\s+`)
+
+// NewMessageListParser creates a parser splitting the content into a list of items by the separator,
+// the items are trimmed, and the empty items are dropped.
+// e.g.
+//
+//	parser := schema.NewMessageListParser(&schema.MessageListParseConfig{Separator: ","})
+//	tags, err := parser.Parse(ctx, schema.AssistantMessage("go, eino , llm", nil))
+//	// tags: [go eino llm]
+func NewMessageListParser(config *MessageListParseConfig) MessageParser[[]string] {
+	p := &messageListParser{separator: "\n"}
+	if config != nil {
+		if config.Separator != "" {
+			p.separator = config.Separator
+		}
+		p.keepMarkers = config.KeepMarkers
+	}
+	return p
+}
+
+type messageListParser struct {
+	separator   string
+	keepMarkers bool
+}
+
+func (p *messageListParser) Parse(_ context.Context, m *Message) ([]string, error) {
+	items := strings.Split(m.Content, p.separator)
+	ret := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if !p.keepMarkers {
+			item = strings.TrimSpace(listMarker.ReplaceAllString(item, ""))
+		}
+		if item != "" {
+			ret = append(ret, item)
+		}
+	}
+	return ret, nil
+}
+
+// NewMessageRegexParser creates a parser matching the content with the pattern, and setting the named groups to the fields of T,
+// which must be a struct or a pointer to struct.
+// a group is set to the field with the same json tag name, or the same name case-insensitively,
+// fields of string, bool, integer and float kinds are supported.
+// e.g.
+//
+//	type Score struct {
+//		Score  int    `json:"score"`
+//		Reason string `json:"reason"`
+//	}
+//	parser, err := schema.NewMessageRegexParser[Score](`Score: (?P<score>\d+)\s+Reason: (?P<reason>.*)`)
+func NewMessageRegexParser[T any](pattern string) (MessageParser[T], error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	isPtr := typ.Kind() == reflect.Ptr
+	if isPtr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("regex parser requires a struct type, got %s", typ)
+	}
+
+	fields := make(map[int][]int)
+	for i, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+		index, ok := findFieldByName(typ, name)
+		if !ok {
+			return nil, fmt.Errorf("no field of %s for group %s", typ, name)
+		}
+		if kind := typ.FieldByIndex(index).Type.Kind(); !isScalarKind(kind) {
+			return nil, fmt.Errorf("unsupported field kind %s for group %s", kind, name)
+		}
+		fields[i] = index
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("regex pattern has no named group")
+	}
+
+	return &messageRegexParser[T]{re: re, typ: typ, isPtr: isPtr, fields: fields}, nil
+}
+
+type messageRegexParser[T any] struct {
+	re     *regexp.Regexp
+	typ    reflect.Type
+	isPtr  bool
+	fields map[int][]int
+}
+
+func (p *messageRegexParser[T]) Parse(_ context.Context, m *Message) (parsed T, err error) {
+	match := p.re.FindStringSubmatchIndex(m.Content)
+	if match == nil {
+		return parsed, fmt.Errorf("content doesn't match the pattern %s", p.re)
+	}
+
+	v := reflect.New(p.typ)
+	for i, index := range p.fields {
+		// the fields of optional groups not participating in the match are left zero
+		if match[2*i] < 0 {
+			continue
+		}
+		field := v.Elem().FieldByIndex(index)
+		if err = setFieldFromString(field, m.Content[match[2*i]:match[2*i+1]]); err != nil {
+			return parsed, fmt.Errorf("failed to set group %s: %w", p.re.SubexpNames()[i], err)
+		}
+	}
+
+	if p.isPtr {
+		return v.Interface().(T), nil
+	}
+	return v.Elem().Interface().(T), nil
+}
+
+func findFieldByName(typ reflect.Type, name string) ([]int, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag == name {
+			return f.Index, true
+		}
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.IsExported() && strings.EqualFold(f.Name, name) {
+			return f.Index, true
+		}
+	}
+	return nil, false
+}
+
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func setFieldFromString(field reflect.Value, s string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+	return nil
+}
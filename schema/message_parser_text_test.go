@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageXMLTagParser(t *testing.T) {
+	ctx := context.Background()
+	parser := NewMessageXMLTagParser("thinking", "answer")
+
+	sections, err := parser.Parse(ctx, AssistantMessage("<thinking>\nadd them\n</thinking>\n<answer> 42 </answer><answer>43</answer>", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"thinking": "add them", "answer": "42"}, sections)
+
+	_, err = parser.Parse(ctx, AssistantMessage("<thinking>add them</thinking>", nil))
+	assert.ErrorContains(t, err, "tag <answer> not found")
+	_, err = parser.Parse(ctx, AssistantMessage("<thinking>add them</thinking><answer>4", nil))
+	assert.ErrorContains(t, err, "closing tag </answer> not found")
+}
+
+func TestMessageCodeBlockParser(t *testing.T) {
+	ctx := context.Background()
+	content := "run this:\n```bash\nls\n```\nthen:\n```SQL\nSELECT 1;\nSELECT 2;\n```"
+
+	block, err := NewMessageCodeBlockParser("sql").Parse(ctx, AssistantMessage(content, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1;\nSELECT 2;", block)
+
+	block, err = NewMessageCodeBlockParser("").Parse(ctx, AssistantMessage(content, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "ls", block)
+
+	_, err = NewMessageCodeBlockParser("go").Parse(ctx, AssistantMessage(content, nil))
+	assert.ErrorContains(t, err, "code block of go not found")
+
+	// the closing fence of a truncated message is missing
+	block, err = NewMessageCodeBlockParser("go").Parse(ctx, AssistantMessage("```go\nfunc main() {", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "func main() {", block)
+}
+
+func TestMessageListParser(t *testing.T) {
+	ctx := context.Background()
+
+	items, err := NewMessageListParser(nil).Parse(ctx, AssistantMessage("1. what is eino\n\n2) how to use eino\n- eino graph\n* 12", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"what is eino", "how to use eino", "eino graph", "12"}, items)
+
+	items, err = NewMessageListParser(&MessageListParseConfig{Separator: ",", KeepMarkers: true}).Parse(ctx, AssistantMessage("go, - eino , ,llm", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "- eino", "llm"}, items)
+}
+
+func TestMessageRegexParser(t *testing.T) {
+	ctx := context.Background()
+	type score struct {
+		Score  int     `json:"score"`
+		Reason string  `json:"reason"`
+		Passed bool    `json:"passed"`
+		Weight float64 // matched by name
+	}
+
+	parser, err := NewMessageRegexParser[*score](`Score: (?P<score>\d+)\nPassed: (?P<passed>\w+)\nWeight: (?P<weight>[\d.]+)\nReason: (?P<reason>.*)`)
+	assert.NoError(t, err)
+	parsed, err := parser.Parse(ctx, AssistantMessage("Score: 8\nPassed: true\nWeight: 0.5\nReason: clear", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, &score{Score: 8, Reason: "clear", Passed: true, Weight: 0.5}, parsed)
+
+	_, err = parser.Parse(ctx, AssistantMessage("no score", nil))
+	assert.ErrorContains(t, err, "doesn't match")
+
+	// optional groups not participating in the match are left zero
+	optional, err := NewMessageRegexParser[score](`Reason: (?P<reason>\w+)(?:, score (?P<score>\d+))?(?:, passed (?P<passed>\w+))?`)
+	assert.NoError(t, err)
+	s, err := optional.Parse(ctx, AssistantMessage("Reason: clear", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, score{Reason: "clear"}, s)
+	s, err = optional.Parse(ctx, AssistantMessage("Reason: clear, score 7", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, score{Reason: "clear", Score: 7}, s)
+
+	_, err = NewMessageRegexParser[score](`(?P<unknown>\d+)`)
+	assert.ErrorContains(t, err, "no field")
+	_, err = NewMessageRegexParser[score](`\d+`)
+	assert.ErrorContains(t, err, "no named group")
+	_, err = NewMessageRegexParser[string](`(?P<a>\d+)`)
+	assert.ErrorContains(t, err, "requires a struct type")
+
+	overflow, err := NewMessageRegexParser[struct {
+		N int8 `json:"n"`
+	}](`(?P<n>\d+)`)
+	assert.NoError(t, err)
+	_, err = overflow.Parse(ctx, AssistantMessage("300", nil))
+	assert.ErrorContains(t, err, "failed to set group n")
+}
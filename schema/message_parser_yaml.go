@@ -0,0 +1,52 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/invopop/yaml"
+)
+
+// NewMessageYAMLParser creates a parser unmarshalling the YAML in the content into T, through the json tags of T as MessageJSONParser does.
+// if the content has a fenced code block of yaml, or any code block, the first one is parsed instead of the whole content.
+// e.g.
+//
+//	parser := schema.NewMessageYAMLParser[Config]()
+//	conf, err := parser.Parse(ctx, schema.AssistantMessage("```yaml\nname: eino\nreplicas: 2\n```", nil))
+func NewMessageYAMLParser[T any]() MessageParser[T] {
+	return &messageYAMLParser[T]{}
+}
+
+type messageYAMLParser[T any] struct{}
+
+func (p *messageYAMLParser[T]) Parse(_ context.Context, m *Message) (parsed T, err error) {
+	data, ok := findCodeBlock(m.Content, "yaml")
+	if !ok {
+		if data, ok = findCodeBlock(m.Content, "yml"); !ok {
+			if data, ok = findCodeBlock(m.Content, ""); !ok {
+				data = m.Content
+			}
+		}
+	}
+
+	if err = yaml.Unmarshal([]byte(data), &parsed); err != nil {
+		return parsed, fmt.Errorf("failed to unmarshal yaml content: %w", err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageYAMLParser(t *testing.T) {
+	ctx := context.Background()
+	type config struct {
+		Name     string   `json:"name"`
+		Replicas int      `json:"replicas"`
+		Tags     []string `json:"tags"`
+	}
+	parser := NewMessageYAMLParser[config]()
+
+	parsed, err := parser.Parse(ctx, AssistantMessage("here:\n```yaml\nname: eino\nreplicas: 2\ntags: [a, b]\n```", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, config{Name: "eino", Replicas: 2, Tags: []string{"a", "b"}}, parsed)
+
+	parsed, err = parser.Parse(ctx, AssistantMessage("name: eino\nreplicas: 3", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, config{Name: "eino", Replicas: 3}, parsed)
+
+	_, err = parser.Parse(ctx, AssistantMessage("name: [eino", nil))
+	assert.ErrorContains(t, err, "failed to unmarshal yaml content")
+}
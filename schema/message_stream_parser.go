@@ -0,0 +1,170 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/mrh997/eino/internal/safe"
+)
+
+// MessageStreamParser parses the stream of a message into a stream of progressively more complete T,
+// so that the fields can be rendered while the model is still generating.
+type MessageStreamParser[T any] interface {
+	ParseStream(ctx context.Context, sr *StreamReader[*Message]) (*StreamReader[T], error)
+}
+
+// NewMessageJSONStreamParser creates a MessageJSONStreamParser with the same config as NewMessageJSONParser.
+func NewMessageJSONStreamParser[T any](config *MessageJSONParseConfig) MessageStreamParser[T] {
+	return &MessageJSONStreamParser[T]{parser: NewMessageJSONParser[T](config).(*MessageJSONParser[T])}
+}
+
+// MessageJSONStreamParser parses the partial JSON in the content or the arguments of the first tool call of the message chunks.
+// after each chunk, the JSON received so far is closed by RepairJSON and unmarshalled into T,
+// which is sent when it's changed, chunks that can't be parsed yet are skipped.
+// the last value sent is parsed from the complete JSON, and the error is sent if the complete JSON can't be parsed,
+// in which case RepairJSON of the config decides whether to repair it, as MessageJSONParser does.
+// e.g.
+//
+//	parser := schema.NewMessageJSONStreamParser[Weather](nil)
+//	sr, err := parser.ParseStream(ctx, modelStream)
+//	for {
+//		weather, err := sr.Recv() // {City: "bei"}, {City: "beijing"}, {City: "beijing", Temperature: 2}...
+//	}
+type MessageJSONStreamParser[T any] struct {
+	parser *MessageJSONParser[T]
+}
+
+// ParseStream parses the message stream, the input stream is closed when the output stream ends.
+func (p *MessageJSONStreamParser[T]) ParseStream(ctx context.Context, sr *StreamReader[*Message]) (*StreamReader[T], error) {
+	if p.parser.ParseFrom != MessageParseFromContent && p.parser.ParseFrom != MessageParseFromToolCall {
+		sr.Close()
+		return nil, fmt.Errorf("invalid parse from type: %s", p.parser.ParseFrom)
+	}
+
+	out, sw := Pipe[T](5)
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				var zero T
+				_ = sw.Send(zero, safe.NewPanicErr(panicErr, debug.Stack()))
+			}
+			sw.Close()
+			sr.Close()
+		}()
+		p.run(ctx, sr, sw)
+	}()
+	return out, nil
+}
+
+func (p *MessageJSONStreamParser[T]) run(ctx context.Context, sr *StreamReader[*Message], sw *StreamWriter[T]) {
+	var (
+		data         strings.Builder
+		lastSent     string
+		toolCallSeen bool
+		toolCallIdx  int
+	)
+
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var zero T
+			_ = sw.Send(zero, err)
+			return
+		}
+		if chunk == nil {
+			continue
+		}
+
+		before := data.Len()
+		if p.parser.ParseFrom == MessageParseFromContent {
+			data.WriteString(chunk.Content)
+		} else {
+			for _, tc := range chunk.ToolCalls {
+				// the arguments of the first tool call, the chunks of a tool call share the same index
+				idx := 0
+				if tc.Index != nil {
+					idx = *tc.Index
+				}
+				if !toolCallSeen {
+					toolCallSeen, toolCallIdx = true, idx
+				}
+				if idx == toolCallIdx {
+					data.WriteString(tc.Function.Arguments)
+				}
+			}
+		}
+		if data.Len() == before {
+			continue
+		}
+
+		parsed, raw, ok := p.parsePartial(data.String())
+		if !ok || raw == lastSent {
+			continue
+		}
+		lastSent = raw
+		if closed := sw.Send(parsed, nil); closed {
+			return
+		}
+	}
+
+	if p.parser.ParseFrom == MessageParseFromToolCall && !toolCallSeen {
+		var zero T
+		_ = sw.Send(zero, fmt.Errorf("no tool call found"))
+		return
+	}
+
+	parsed, err := p.parser.parse(ctx, data.String())
+	if err != nil {
+		_ = sw.Send(parsed, err)
+		return
+	}
+	if raw, err := sonic.MarshalString(parsed); err == nil && raw == lastSent {
+		return
+	}
+	_ = sw.Send(parsed, nil)
+}
+
+// parsePartial parses the partial JSON closed by RepairJSON, raw is the JSON of the parsed value to detect changes.
+func (p *MessageJSONStreamParser[T]) parsePartial(data string) (parsed T, raw string, ok bool) {
+	result, err := RepairJSON(data)
+	if err != nil {
+		return parsed, "", false
+	}
+	extracted, err := p.parser.extractData(result.Repaired)
+	if err != nil {
+		return parsed, "", false
+	}
+	if err = sonic.UnmarshalString(extracted, &parsed); err != nil {
+		return parsed, "", false
+	}
+	raw, err = sonic.MarshalString(parsed)
+	if err != nil {
+		return parsed, "", false
+	}
+	return parsed, raw, true
+}
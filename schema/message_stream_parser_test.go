@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type streamParseWeather struct {
+	City        string   `json:"city"`
+	Temperature int      `json:"temperature"`
+	Tags        []string `json:"tags"`
+}
+
+func collectStream[T any](t *testing.T, sr *StreamReader[T]) ([]T, error) {
+	defer sr.Close()
+	var ret []T
+	for {
+		v, err := sr.Recv()
+		if err == io.EOF {
+			return ret, nil
+		}
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, v)
+	}
+}
+
+func TestMessageJSONStreamParser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("content", func(t *testing.T) {
+		chunks := []*Message{
+			{Content: `{"ci`},
+			{Content: `ty": "bei`},
+			{Content: `jing", `},
+			{Content: `"temperature": 2`},
+			{Content: `, "tags": ["su`},
+			{Content: `nny"]}`},
+		}
+		parser := NewMessageJSONStreamParser[streamParseWeather](nil)
+		sr, err := parser.ParseStream(ctx, StreamReaderFromArray(chunks))
+		assert.NoError(t, err)
+		values, err := collectStream(t, sr)
+		assert.NoError(t, err)
+		assert.Equal(t, []streamParseWeather{
+			{},
+			{City: "bei"},
+			{City: "beijing"},
+			{City: "beijing", Temperature: 2},
+			{City: "beijing", Temperature: 2, Tags: []string{"su"}},
+			{City: "beijing", Temperature: 2, Tags: []string{"sunny"}},
+		}, values)
+	})
+
+	t.Run("tool call with key path", func(t *testing.T) {
+		idx0, idx1 := 0, 1
+		chunks := []*Message{
+			{ToolCalls: []ToolCall{{Index: &idx0, Function: FunctionCall{Name: "report", Arguments: `{"weather": {"city": "sh`}}}},
+			{ToolCalls: []ToolCall{{Index: &idx0, Function: FunctionCall{Arguments: `anghai"}}`}}}},
+			{ToolCalls: []ToolCall{{Index: &idx1, Function: FunctionCall{Name: "other", Arguments: `{"weather": {"city": "x"}}`}}}},
+		}
+		parser := NewMessageJSONStreamParser[*streamParseWeather](&MessageJSONParseConfig{
+			ParseFrom:    MessageParseFromToolCall,
+			ParseKeyPath: "weather",
+		})
+		sr, err := parser.ParseStream(ctx, StreamReaderFromArray(chunks))
+		assert.NoError(t, err)
+		values, err := collectStream(t, sr)
+		assert.NoError(t, err)
+		assert.Equal(t, []*streamParseWeather{{City: "sh"}, {City: "shanghai"}}, values)
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		parser := NewMessageJSONStreamParser[streamParseWeather](nil)
+		sr, err := parser.ParseStream(ctx, StreamReaderFromArray([]*Message{{Content: `{"city": "beijing"`}}))
+		assert.NoError(t, err)
+		values, err := collectStream(t, sr)
+		assert.ErrorContains(t, err, "failed to unmarshal content")
+		assert.Equal(t, []streamParseWeather{{City: "beijing"}}, values)
+
+		// repaired when RepairJSON is set
+		parser = NewMessageJSONStreamParser[streamParseWeather](&MessageJSONParseConfig{RepairJSON: true})
+		sr, err = parser.ParseStream(ctx, StreamReaderFromArray([]*Message{{Content: `{"city": "beijing"`}}))
+		assert.NoError(t, err)
+		values, err = collectStream(t, sr)
+		assert.NoError(t, err)
+		assert.Equal(t, []streamParseWeather{{City: "beijing"}}, values)
+	})
+
+	t.Run("no tool call", func(t *testing.T) {
+		parser := NewMessageJSONStreamParser[streamParseWeather](&MessageJSONParseConfig{ParseFrom: MessageParseFromToolCall})
+		sr, err := parser.ParseStream(ctx, StreamReaderFromArray([]*Message{{Content: "hi"}}))
+		assert.NoError(t, err)
+		_, err = collectStream(t, sr)
+		assert.ErrorContains(t, err, "no tool call found")
+	})
+}
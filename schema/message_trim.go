@@ -0,0 +1,137 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"errors"
+)
+
+// MessageTrimConfig is the config of MessageTrimmer.
+type MessageTrimConfig struct {
+	// MaxTokens is the token budget of the trimmed messages, required.
+	MaxTokens int
+	// Tokenizer counts the tokens of the messages.
+	// Optional. Default NewHeuristicTokenizer().
+	Tokenizer Tokenizer
+	// DropReasoningContent clears the ReasoningContent of the messages before counting,
+	// as most models don't need the reasoning of previous turns.
+	// Optional. Default false.
+	DropReasoningContent bool
+}
+
+// MessageTrimmer trims the messages to fit the token budget of the context window.
+// it keeps all system messages and the latest turns, and never splits an assistant message with tool calls from its tool messages.
+// Trim can be used as a graph node directly, e.g.
+//
+//	trimmer, err := schema.NewMessageTrimmer(&schema.MessageTrimConfig{MaxTokens: 8000})
+//	err = g.AddLambdaNode("trim", compose.InvokableLambda(trimmer.Trim))
+type MessageTrimmer struct {
+	maxTokens            int
+	tokenizer            Tokenizer
+	dropReasoningContent bool
+}
+
+// NewMessageTrimmer creates a MessageTrimmer.
+func NewMessageTrimmer(conf *MessageTrimConfig) (*MessageTrimmer, error) {
+	if conf == nil || conf.MaxTokens <= 0 {
+		return nil, errors.New("max tokens must be positive")
+	}
+	t := &MessageTrimmer{
+		maxTokens:            conf.MaxTokens,
+		tokenizer:            conf.Tokenizer,
+		dropReasoningContent: conf.DropReasoningContent,
+	}
+	if t.tokenizer == nil {
+		t.tokenizer = NewHeuristicTokenizer()
+	}
+	return t, nil
+}
+
+// Trim returns the system messages and the latest other messages fitting the token budget, in the original order.
+// the messages are grouped into units, an assistant message with tool calls and the tool messages following it is a unit,
+// and units are kept from the latest one until the next one doesn't fit.
+// only the system messages are returned if they exceed the budget themselves.
+// the input messages are not modified.
+func (t *MessageTrimmer) Trim(ctx context.Context, msgs []*Message) ([]*Message, error) {
+	if t.dropReasoningContent {
+		dropped := make([]*Message, len(msgs))
+		for i, msg := range msgs {
+			if msg != nil && msg.ReasoningContent != "" {
+				cp := *msg
+				cp.ReasoningContent = ""
+				msg = &cp
+			}
+			dropped[i] = msg
+		}
+		msgs = dropped
+	}
+
+	keep := make([]bool, len(msgs))
+	budget := t.maxTokens
+	var units [][]int
+	for i := 0; i < len(msgs); i++ {
+		msg := msgs[i]
+		if msg == nil {
+			continue
+		}
+		if msg.Role == System {
+			n, err := CountMessageTokens(ctx, t.tokenizer, msg)
+			if err != nil {
+				return nil, err
+			}
+			budget -= n
+			keep[i] = true
+			continue
+		}
+
+		unit := []int{i}
+		if msg.Role == Assistant && len(msg.ToolCalls) > 0 {
+			for i+1 < len(msgs) && msgs[i+1] != nil && msgs[i+1].Role == Tool {
+				i++
+				unit = append(unit, i)
+			}
+		}
+		units = append(units, unit)
+	}
+
+	for u := len(units) - 1; u >= 0 && budget > 0; u-- {
+		n := 0
+		for _, i := range units[u] {
+			c, err := CountMessageTokens(ctx, t.tokenizer, msgs[i])
+			if err != nil {
+				return nil, err
+			}
+			n += c
+		}
+		if n > budget {
+			break
+		}
+		budget -= n
+		for _, i := range units[u] {
+			keep[i] = true
+		}
+	}
+
+	ret := make([]*Message, 0, len(msgs))
+	for i, msg := range msgs {
+		if keep[i] {
+			ret = append(ret, msg)
+		}
+	}
+	return ret, nil
+}
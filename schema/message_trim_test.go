@@ -0,0 +1,68 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageTrimmer(t *testing.T) {
+	ctx := context.Background()
+	// each message takes 4+2 tokens with 8 characters of content
+	text := strings.Repeat("x", 8)
+	system := SystemMessage(text)
+	msgs := []*Message{
+		system,
+		UserMessage(text),
+		AssistantMessage(text, nil),
+		UserMessage(text),
+		{Role: Assistant, ToolCalls: []ToolCall{{ID: "1", Function: FunctionCall{Name: "t"}}}, ReasoningContent: text},
+		ToolMessage(text, "1"),
+		AssistantMessage(text, nil),
+	}
+
+	_, err := NewMessageTrimmer(&MessageTrimConfig{})
+	assert.Error(t, err)
+
+	trimmer, err := NewMessageTrimmer(&MessageTrimConfig{MaxTokens: 24})
+	assert.NoError(t, err)
+	// 12 tokens are left after the last message, the tool call with reasoning content and its tool message take 7+6 tokens
+	trimmed, err := trimmer.Trim(ctx, msgs)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Message{system, msgs[6]}, trimmed)
+
+	trimmer, err = NewMessageTrimmer(&MessageTrimConfig{MaxTokens: 24, DropReasoningContent: true})
+	assert.NoError(t, err)
+	trimmed, err = trimmer.Trim(ctx, msgs)
+	assert.NoError(t, err)
+	assert.Len(t, trimmed, 4)
+	assert.Equal(t, system, trimmed[0])
+	assert.Equal(t, "", trimmed[1].ReasoningContent)
+	assert.Equal(t, text, msgs[4].ReasoningContent)
+	assert.Equal(t, []*Message{msgs[5], msgs[6]}, trimmed[2:])
+
+	// only the system messages are kept if they exceed the budget
+	trimmer, err = NewMessageTrimmer(&MessageTrimConfig{MaxTokens: 5})
+	assert.NoError(t, err)
+	trimmed, err = trimmer.Trim(ctx, msgs)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Message{system}, trimmed)
+}
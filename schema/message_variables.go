@@ -0,0 +1,310 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/nikolalohinski/gonja/tokens"
+)
+
+// TemplateVariable is a variable referenced by a MessagesTemplate, see ExtractVariables.
+type TemplateVariable struct {
+	Name string
+	// Optional is true if the template can be formatted without the variable, e.g. an optional MessagesPlaceholder.
+	Optional bool
+	// Messages is true if the variable must be a []*Message, e.g. the key of a MessagesPlaceholder.
+	Messages bool
+}
+
+// VariablesDeclarer is implemented by the MessagesTemplate declaring the variables it references,
+// such as *Message and MessagesPlaceholder.
+type VariablesDeclarer interface {
+	Variables(formatType FormatType) ([]TemplateVariable, error)
+}
+
+// ExtractVariables returns the variables referenced by the templates without formatting them, in the order of first reference,
+// so that missing or misspelled variables can be found before running.
+// the variables referenced by more than one template are merged, which are optional only if optional in all the templates.
+// the templates not implementing VariablesDeclarer are ignored.
+// e.g.
+//
+//	vars, err := schema.ExtractVariables(schema.FString,
+//		schema.SystemMessage("you are a {role}."),
+//		schema.MessagesPlaceholder("history", true),
+//		schema.UserMessage("{query}"),
+//	)
+//	// vars: role, history(optional, messages), query
+func ExtractVariables(formatType FormatType, templates ...MessagesTemplate) ([]TemplateVariable, error) {
+	var ret []TemplateVariable
+	indexes := make(map[string]int)
+	for i, t := range templates {
+		d, ok := t.(VariablesDeclarer)
+		if !ok {
+			continue
+		}
+		vars, err := d.Variables(formatType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract variables of template[%d]: %w", i, err)
+		}
+		for _, v := range vars {
+			idx, ok := indexes[v.Name]
+			if !ok {
+				indexes[v.Name] = len(ret)
+				ret = append(ret, v)
+				continue
+			}
+			ret[idx].Optional = ret[idx].Optional && v.Optional
+			ret[idx].Messages = ret[idx].Messages || v.Messages
+		}
+	}
+	return ret, nil
+}
+
+// Variables returns the variables referenced by the content and the texts of the multi content of the message.
+// missing variables make Format fail with FString and GoTemplate, and render as empty with Jinja2,
+// but they are all reported as required.
+func (m *Message) Variables(formatType FormatType) ([]TemplateVariable, error) {
+	texts := []string{m.Content}
+	for _, part := range m.MultiContent {
+		if len(part.Text) > 0 {
+			texts = append(texts, part.Text)
+		}
+	}
+
+	var ret []TemplateVariable
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		names, err := contentVariables(text, formatType)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				ret = append(ret, TemplateVariable{Name: name})
+			}
+		}
+	}
+	return ret, nil
+}
+
+// Variables returns the key of the placeholder.
+func (p *messagesPlaceholder) Variables(_ FormatType) ([]TemplateVariable, error) {
+	return []TemplateVariable{{Name: p.key, Optional: p.optional, Messages: true}}, nil
+}
+
+func contentVariables(content string, formatType FormatType) ([]string, error) {
+	switch formatType {
+	case FString:
+		return fStringVariables(content)
+	case GoTemplate:
+		return goTemplateVariables(content)
+	case Jinja2:
+		return jinja2Variables(content)
+	default:
+		return nil, fmt.Errorf("unknown format type: %v", formatType)
+	}
+}
+
+// fStringVariables returns the names of the replacement fields, e.g. user of {user.name:>10}.
+func fStringVariables(content string) ([]string, error) {
+	var names []string
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			if i+1 < len(content) && content[i+1] == '{' {
+				i++
+				continue
+			}
+			end := strings.IndexByte(content[i+1:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed replacement field at offset %d", i)
+			}
+			field := content[i+1 : i+1+end]
+			if idx := strings.IndexAny(field, ".[:!"); idx >= 0 {
+				field = field[:idx]
+			}
+			if field == "" {
+				return nil, fmt.Errorf("replacement field without name at offset %d", i)
+			}
+			names = append(names, field)
+			i += end + 1
+		case '}':
+			if i+1 < len(content) && content[i+1] == '}' {
+				i++
+			}
+		}
+	}
+	return names, nil
+}
+
+// goTemplateVariables returns the names of the fields of the root data, e.g. user of {{.user.name}} and {{$.user}},
+// the fields inside range and with refer to other data, so they are not variables.
+func goTemplateVariables(content string) ([]string, error) {
+	tpl, err := template.New("template").Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	if tpl.Tree == nil {
+		return nil, nil
+	}
+
+	var names []string
+	var walk func(node parse.Node, root bool)
+	walk = func(node parse.Node, root bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, root)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, root)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd, root)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, root)
+			}
+		case *parse.ChainNode:
+			walk(n.Node, root)
+		case *parse.FieldNode:
+			if root {
+				names = append(names, n.Ident[0])
+			}
+		case *parse.VariableNode:
+			if n.Ident[0] == "$" && len(n.Ident) > 1 {
+				names = append(names, n.Ident[1])
+			}
+		case *parse.IfNode:
+			walk(n.Pipe, root)
+			walk(n.List, root)
+			walk(n.ElseList, root)
+		case *parse.RangeNode:
+			walk(n.Pipe, root)
+			walk(n.List, false)
+			walk(n.ElseList, root)
+		case *parse.WithNode:
+			walk(n.Pipe, root)
+			walk(n.List, false)
+			walk(n.ElseList, root)
+		case *parse.TemplateNode:
+			walk(n.Pipe, root)
+		}
+	}
+	walk(tpl.Tree.Root, true)
+	return names, nil
+}
+
+var jinja2Keywords = map[string]bool{
+	"if": true, "else": true, "elif": true, "for": true, "in": true, "recursive": true,
+	"true": true, "false": true, "none": true, "True": true, "False": true, "None": true,
+	"loop": true, "caller": true, "varargs": true, "kwargs": true, "self": true, "super": true,
+}
+
+// jinja2Variables returns the names referenced by the template, except the attributes, filters, tests,
+// keyword arguments, globals, and the names defined by for, set, with and macro.
+func jinja2Variables(content string) ([]string, error) {
+	env, err := getJinjaEnv()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = env.FromString(content); err != nil {
+		return nil, err
+	}
+
+	var toks []*tokens.Token
+	for stream := tokens.Lex(content); !stream.End(); stream.Next() {
+		toks = append(toks, stream.Current())
+	}
+
+	var names []string
+	locals := make(map[string]bool)
+	// stmt is the name of the statement being lexed, and assigned is true after its first =
+	stmt, assigned := "", false
+	for i, tok := range toks {
+		var prev, next *tokens.Token
+		if i > 0 {
+			prev = toks[i-1]
+		}
+		if i+1 < len(toks) {
+			next = toks[i+1]
+		}
+
+		switch tok.Type {
+		case tokens.BlockBegin:
+			stmt, assigned = "", false
+			if next != nil && next.Type == tokens.Name {
+				stmt = next.Val
+			}
+			continue
+		case tokens.VariableBegin:
+			stmt, assigned = "", false
+			continue
+		case tokens.Assign:
+			assigned = true
+			continue
+		case tokens.In:
+			if stmt == "for" {
+				stmt = ""
+			}
+			continue
+		case tokens.Name:
+		default:
+			continue
+		}
+
+		switch {
+		case prev != nil && prev.Type == tokens.BlockBegin,
+			prev != nil && (prev.Type == tokens.Dot || prev.Type == tokens.Pipe || prev.Type == tokens.Is),
+			prev != nil && prev.Type == tokens.Not && i >= 2 && toks[i-2].Type == tokens.Is:
+			// statement, attribute, filter or test
+		case stmt == "for" || stmt == "macro":
+			locals[tok.Val] = true
+		case next != nil && next.Type == tokens.Assign:
+			// target of set and with, or keyword argument
+			if stmt == "set" || stmt == "with" {
+				locals[tok.Val] = true
+			}
+		case stmt == "set" && !assigned:
+			// target of block set
+			locals[tok.Val] = true
+		case jinja2Keywords[tok.Val] || env.Globals.Has(tok.Val):
+		default:
+			names = append(names, tok.Val)
+		}
+	}
+
+	ret := names[:0]
+	for _, name := range names {
+		if !locals[name] {
+			ret = append(ret, name)
+		}
+	}
+	return ret, nil
+}
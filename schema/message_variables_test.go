@@ -0,0 +1,87 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractVariables(t *testing.T) {
+	names := func(vars []TemplateVariable) []string {
+		ret := make([]string, 0, len(vars))
+		for _, v := range vars {
+			ret = append(ret, v.Name)
+		}
+		return ret
+	}
+
+	t.Run("fstring", func(t *testing.T) {
+		vars, err := (&Message{
+			Content:      "{{literal}} hi {user.name}, {items[0]} and {score:.2f} {user}",
+			MultiContent: []ChatMessagePart{{Type: ChatMessagePartTypeText, Text: "about {topic!r}"}},
+		}).Variables(FString)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"user", "items", "score", "topic"}, names(vars))
+
+		_, err = UserMessage("hi {name").Variables(FString)
+		assert.ErrorContains(t, err, "unclosed")
+		_, err = UserMessage("hi {}").Variables(FString)
+		assert.Error(t, err)
+	})
+
+	t.Run("go template", func(t *testing.T) {
+		vars, err := UserMessage(`{{.name}} {{if .vip}}{{.title | printf "%s"}}{{end}}` +
+			`{{range .items}}{{.sku}} {{$.currency}}{{else}}{{.empty}}{{end}}{{with $x := .profile}}{{.age}}{{end}}`).Variables(GoTemplate)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name", "vip", "title", "items", "currency", "empty", "profile"}, names(vars))
+
+		_, err = UserMessage("{{.name").Variables(GoTemplate)
+		assert.Error(t, err)
+	})
+
+	t.Run("jinja2", func(t *testing.T) {
+		vars, err := UserMessage(`{{ user.name | upper }} {% if vip is not none %}{{ title }}{% endif %}` +
+			`{% for item in items if item.ok %}{{ loop.index }} {{ item.sku }} {{ currency }}{% endfor %}` +
+			`{% set total = count + 1 %}{{ total }} {{ range(3) | join(d=sep) }}` +
+			`{% with a = x, b = y %}{{ a }}{{ b }}{% endwith %}`).Variables(Jinja2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"user", "vip", "title", "items", "currency", "count", "sep", "x", "y"}, names(vars))
+
+		_, err = UserMessage("{% if x %}").Variables(Jinja2)
+		assert.Error(t, err)
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		vars, err := ExtractVariables(FString,
+			SystemMessage("you are a {role}."),
+			MessagesPlaceholder("history", true),
+			UserMessage("{query}"),
+			MessagesPlaceholder("role", true),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []TemplateVariable{
+			{Name: "role", Messages: true},
+			{Name: "history", Optional: true, Messages: true},
+			{Name: "query"},
+		}, vars)
+
+		_, err = ExtractVariables(FString, UserMessage("{"))
+		assert.ErrorContains(t, err, "template[0]")
+	})
+}
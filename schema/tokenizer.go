@@ -0,0 +1,181 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tokenizer counts the tokens of texts, e.g. to estimate the size of the messages sent to the model.
+// use NewHeuristicTokenizer for a rough estimate without vocabulary, or NewBPETokenizer for the exact count of a BPE vocabulary.
+type Tokenizer interface {
+	CountTokens(ctx context.Context, text string) (int, error)
+}
+
+// NewHeuristicTokenizer creates a Tokenizer estimating one token per 4 ASCII characters, and one token per other character,
+// which is close to common BPE vocabularies for English and CJK texts.
+func NewHeuristicTokenizer() Tokenizer {
+	return heuristicTokenizer{}
+}
+
+type heuristicTokenizer struct{}
+
+func (heuristicTokenizer) CountTokens(_ context.Context, text string) (int, error) {
+	ascii, others := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			others++
+		}
+	}
+	return (ascii+3)/4 + others, nil
+}
+
+// bpePattern splits the text into pieces before merging, it approximates the pattern of cl100k_base, as RE2 has no lookahead.
+var bpePattern = regexp.MustCompile(`(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`)
+
+// BPETokenizer is a Tokenizer of byte pair encoding, with the vocabulary in the format of tiktoken,
+// i.e. a line of the base64 encoded token and its rank for each token.
+type BPETokenizer struct {
+	ranks map[string]int
+}
+
+// NewBPETokenizer creates a BPETokenizer reading the vocabulary from r.
+func NewBPETokenizer(r io.Reader) (*BPETokenizer, error) {
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid bpe vocabulary at line %d: %q", line, text)
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bpe token at line %d: %w", line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bpe rank at line %d: %w", line, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bpe vocabulary: %w", err)
+	}
+	if len(ranks) == 0 {
+		return nil, fmt.Errorf("bpe vocabulary is empty")
+	}
+	return &BPETokenizer{ranks: ranks}, nil
+}
+
+// NewBPETokenizerFromFile creates a BPETokenizer loading the vocabulary from a local file, e.g. cl100k_base.tiktoken.
+func NewBPETokenizerFromFile(path string) (*BPETokenizer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bpe vocabulary: %w", err)
+	}
+	defer f.Close()
+	return NewBPETokenizer(f)
+}
+
+// CountTokens counts the tokens of the text encoded by the vocabulary.
+func (t *BPETokenizer) CountTokens(_ context.Context, text string) (int, error) {
+	count := 0
+	for _, piece := range bpePattern.FindAllString(text, -1) {
+		count += t.countPiece(piece)
+	}
+	return count, nil
+}
+
+// countPiece merges the adjacent parts of the piece with the lowest rank repeatedly, and returns the number of parts left.
+func (t *BPETokenizer) countPiece(piece string) int {
+	if _, ok := t.ranks[piece]; ok {
+		return 1
+	}
+
+	// bounds[i] is the start of the i-th part
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+	for len(bounds) > 2 {
+		minIdx, minRank := -1, 0
+		for i := 0; i+2 < len(bounds); i++ {
+			rank, ok := t.ranks[piece[bounds[i]:bounds[i+2]]]
+			if ok && (minIdx < 0 || rank < minRank) {
+				minIdx, minRank = i, rank
+			}
+		}
+		if minIdx < 0 {
+			break
+		}
+		bounds = append(bounds[:minIdx+1], bounds[minIdx+2:]...)
+	}
+	return len(bounds) - 1
+}
+
+// tokensPerMessage is the overhead of the role and separators of each message, the same as the chat format of OpenAI.
+const tokensPerMessage = 4
+
+// CountMessageTokens counts the tokens of the messages by the tokenizer, including the content, the reasoning content,
+// the text parts of the multi content, the tool calls and a fixed overhead of each message.
+// non-text parts are not counted, as their tokens depend on the model.
+func CountMessageTokens(ctx context.Context, tokenizer Tokenizer, msgs ...*Message) (int, error) {
+	total := 0
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		texts := []string{msg.Content, msg.ReasoningContent, msg.Name}
+		for _, part := range msg.MultiContent {
+			if part.Type == ChatMessagePartTypeText {
+				texts = append(texts, part.Text)
+			}
+		}
+		for _, tc := range msg.ToolCalls {
+			texts = append(texts, tc.Function.Name, tc.Function.Arguments)
+		}
+
+		total += tokensPerMessage
+		for _, text := range texts {
+			if text == "" {
+				continue
+			}
+			n, err := tokenizer.CountTokens(ctx, text)
+			if err != nil {
+				return 0, fmt.Errorf("failed to count tokens: %w", err)
+			}
+			total += n
+		}
+	}
+	return total, nil
+}
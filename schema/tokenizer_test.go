@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeuristicTokenizer(t *testing.T) {
+	ctx := context.Background()
+	n, err := NewHeuristicTokenizer().CountTokens(ctx, "hello world")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	n, err = NewHeuristicTokenizer().CountTokens(ctx, "你好 hi")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestBPETokenizer(t *testing.T) {
+	ctx := context.Background()
+	sb := strings.Builder{}
+	for rank, token := range []string{"a", "b", "c", " ", "ab", "abc", " abc"} {
+		sb.WriteString(fmt.Sprintf("%s %d\n", base64.StdEncoding.EncodeToString([]byte(token)), rank))
+	}
+	path := filepath.Join(t.TempDir(), "test.tiktoken")
+	assert.NoError(t, os.WriteFile(path, []byte(sb.String()), 0o600))
+
+	tokenizer, err := NewBPETokenizerFromFile(path)
+	assert.NoError(t, err)
+
+	n, err := tokenizer.CountTokens(ctx, "abc abc")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// abcab is merged into abc and ab
+	n, err = tokenizer.CountTokens(ctx, "abcab")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	// unknown bytes are counted one by one
+	n, err = tokenizer.CountTokens(ctx, "abd")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	_, err = NewBPETokenizer(strings.NewReader("YQ== x\n"))
+	assert.ErrorContains(t, err, "invalid bpe rank at line 1")
+	_, err = NewBPETokenizerFromFile(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestCountMessageTokens(t *testing.T) {
+	ctx := context.Background()
+	n, err := CountMessageTokens(ctx, NewHeuristicTokenizer(),
+		UserMessage("hello world"),
+		AssistantMessage("", []ToolCall{{Function: FunctionCall{Name: "get", Arguments: `{"a":1}`}}}),
+	)
+	assert.NoError(t, err)
+	// 4+3 and 4+1+2
+	assert.Equal(t, 14, n)
+}
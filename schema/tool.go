@@ -69,6 +69,11 @@ type ToolInfo struct {
 	//  - use openAPIV3: schema.NewParamsOneOfByOpenAPIV3(openAPIV3)
 	// If is nil, signals that the tool does not need any input parameter
 	*ParamsOneOf
+
+	// OutputSchema is the JSON schema of the structured output of the tool, optional.
+	// it tells downstream nodes and the model providers accepting output schemas what the tool returns, see ValidateOutput.
+	// If is nil, signals that the output is not described.
+	OutputSchema *jsonschema.Schema
 }
 
 // ParameterInfo is the information of a parameter.
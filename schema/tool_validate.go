@@ -0,0 +1,443 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SchemaViolation describes a value violating a JSON schema.
+type SchemaViolation struct {
+	// Path is the JSON pointer of the invalid value, e.g. /items/0/name, empty for the whole value.
+	Path string `json:"path"`
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+func (v *SchemaViolation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// ValidateArguments validates the arguments of a tool call against the parameters, in all the ways ParamsOneOf is defined.
+// the violations are returned if any, and the error is returned if the arguments are not valid JSON.
+// e.g.
+//
+//	violations, err := info.ParamsOneOf.ValidateArguments(`{"city": 1}`)
+//	// violations: [/city: expected type string, got number]
+func (p *ParamsOneOf) ValidateArguments(argumentsInJSON string) ([]*SchemaViolation, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	if strings.TrimSpace(argumentsInJSON) == "" {
+		argumentsInJSON = "{}"
+	}
+	dec := json.NewDecoder(strings.NewReader(argumentsInJSON))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid arguments json: %w", err)
+	}
+	if dec.More() {
+		return nil, errors.New("invalid arguments json: unexpected content after the value")
+	}
+
+	if p.params == nil && p.jsonschema == nil && p.openAPIV3 != nil {
+		return validateOpenAPIV3(p.openAPIV3, value), nil
+	}
+
+	js, err := p.ToJSONSchema()
+	if err != nil {
+		return nil, err
+	}
+	return ValidateJSONSchema(js, value), nil
+}
+
+// ValidateOutput validates the output of the tool against OutputSchema, and returns the violations found.
+// an output that is not valid JSON is validated as a JSON string, as tools usually return plain text as is,
+// and so is any output if the type of OutputSchema is string, e.g. the text "42" is a valid string output.
+// nil is returned if OutputSchema is nil.
+func (t *ToolInfo) ValidateOutput(output string) ([]*SchemaViolation, error) {
+	if t == nil || t.OutputSchema == nil {
+		return nil, nil
+	}
+
+	var value any = output
+	if t.OutputSchema.Type != "string" && json.Valid([]byte(output)) {
+		dec := json.NewDecoder(strings.NewReader(output))
+		dec.UseNumber()
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("invalid output json: %w", err)
+		}
+	}
+	return ValidateJSONSchema(t.OutputSchema, value), nil
+}
+
+func validateOpenAPIV3(s *openapi3.Schema, value any) []*SchemaViolation {
+	// openapi3 expects numbers in float64
+	b, _ := json.Marshal(value)
+	var v any
+	_ = json.Unmarshal(b, &v)
+
+	err := s.VisitJSON(v, openapi3.MultiErrors())
+	if err == nil {
+		return nil
+	}
+
+	var errs openapi3.MultiError
+	if !errors.As(err, &errs) {
+		errs = openapi3.MultiError{err}
+	}
+	ret := make([]*SchemaViolation, 0, len(errs))
+	for _, e := range errs {
+		violation := &SchemaViolation{Message: e.Error()}
+		var se *openapi3.SchemaError
+		if errors.As(e, &se) {
+			violation.Message = se.Reason
+			if ptr := se.JSONPointer(); len(ptr) > 0 {
+				violation.Path = "/" + strings.Join(ptr, "/")
+			}
+		}
+		ret = append(ret, violation)
+	}
+	return ret
+}
+
+// ValidateJSONSchema validates a decoded JSON value against the JSON schema, and returns the violations.
+// numbers in the value can be json.Number or float64.
+// the common keywords are supported: type, enum, const, properties, required, additionalProperties, items,
+// length, pattern, range and size limits, allOf, anyOf, oneOf, not, and local $ref to $defs,
+// format and other keywords are ignored.
+func ValidateJSONSchema(js *jsonschema.Schema, value any) []*SchemaViolation {
+	v := &schemaValidator{root: js}
+	return v.validate(js, value, "", 0)
+}
+
+const maxValidateDepth = 64
+
+type schemaValidator struct {
+	root *jsonschema.Schema
+}
+
+func isFalseSchema(s *jsonschema.Schema) bool {
+	return s == jsonschema.FalseSchema || reflect.DeepEqual(s, jsonschema.FalseSchema)
+}
+
+func (sv *schemaValidator) resolve(ref string) *jsonschema.Schema {
+	if ref == "#" {
+		return sv.root
+	}
+	if name := strings.TrimPrefix(ref, "#/$defs/"); name != ref && sv.root.Definitions != nil {
+		return sv.root.Definitions[name]
+	}
+	return nil
+}
+
+func (sv *schemaValidator) validate(s *jsonschema.Schema, value any, path string, depth int) []*SchemaViolation {
+	if s == nil || depth > maxValidateDepth {
+		return nil
+	}
+	if isFalseSchema(s) {
+		return []*SchemaViolation{{Path: path, Message: "value is not allowed"}}
+	}
+
+	var ret []*SchemaViolation
+	add := func(format string, args ...any) {
+		ret = append(ret, &SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Ref != "" {
+		if rs := sv.resolve(s.Ref); rs != nil {
+			ret = append(ret, sv.validate(rs, value, path, depth+1)...)
+		}
+	}
+
+	types := s.TypeEnhanced
+	if s.Type != "" {
+		types = []string{s.Type}
+	}
+	if len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if matchType(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			add("expected type %s, got %s", strings.Join(types, " or "), typeOf(value))
+			// the other keywords are meaningless for a value of wrong type
+			return ret
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("value %s is not one of the allowed values %s", marshalForMessage(value), marshalForMessage(s.Enum))
+		}
+	}
+	if s.Const != nil && !jsonEqual(s.Const, value) {
+		add("value %s does not equal the constant %s", marshalForMessage(value), marshalForMessage(s.Const))
+	}
+
+	switch val := value.(type) {
+	case string:
+		n := uint64(utf8.RuneCountInString(val))
+		if s.MinLength != nil && n < *s.MinLength {
+			add("length %d is less than minLength %d", n, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			add("length %d is greater than maxLength %d", n, *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(val) {
+				add("value does not match pattern %s", s.Pattern)
+			}
+		}
+	case json.Number, float64:
+		f, _ := toFloat(val)
+		if limit, ok := toFloat(s.Minimum); ok && f < limit {
+			add("value %v is less than minimum %v", f, limit)
+		}
+		if limit, ok := toFloat(s.ExclusiveMinimum); ok && f <= limit {
+			add("value %v is not greater than exclusiveMinimum %v", f, limit)
+		}
+		if limit, ok := toFloat(s.Maximum); ok && f > limit {
+			add("value %v is greater than maximum %v", f, limit)
+		}
+		if limit, ok := toFloat(s.ExclusiveMaximum); ok && f >= limit {
+			add("value %v is not less than exclusiveMaximum %v", f, limit)
+		}
+		if m, ok := toFloat(s.MultipleOf); ok && m > 0 {
+			if q := f / m; math.Abs(q-math.Round(q)) > 1e-9 {
+				add("value %v is not a multiple of %v", f, m)
+			}
+		}
+	case []any:
+		n := uint64(len(val))
+		if s.MinItems != nil && n < *s.MinItems {
+			add("array has %d items, less than minItems %d", n, *s.MinItems)
+		}
+		if s.MaxItems != nil && n > *s.MaxItems {
+			add("array has %d items, more than maxItems %d", n, *s.MaxItems)
+		}
+		if s.UniqueItems {
+			for i := 0; i < len(val); i++ {
+				for j := i + 1; j < len(val); j++ {
+					if jsonEqual(val[i], val[j]) {
+						add("items %d and %d are equal, but items should be unique", i, j)
+					}
+				}
+			}
+		}
+		for i, item := range val {
+			itemPath := path + "/" + strconv.Itoa(i)
+			if i < len(s.PrefixItems) {
+				ret = append(ret, sv.validate(s.PrefixItems[i], item, itemPath, depth+1)...)
+			} else if s.Items != nil {
+				ret = append(ret, sv.validate(s.Items, item, itemPath, depth+1)...)
+			}
+		}
+	case map[string]any:
+		n := uint64(len(val))
+		if s.MinProperties != nil && n < *s.MinProperties {
+			add("object has %d properties, less than minProperties %d", n, *s.MinProperties)
+		}
+		if s.MaxProperties != nil && n > *s.MaxProperties {
+			add("object has %d properties, more than maxProperties %d", n, *s.MaxProperties)
+		}
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				ret = append(ret, &SchemaViolation{Path: path + "/" + escapePointer(name), Message: "required property is missing"})
+			}
+		}
+
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			propPath := path + "/" + escapePointer(k)
+			if s.Properties != nil {
+				if ps, ok := s.Properties.Get(k); ok {
+					ret = append(ret, sv.validate(ps, val[k], propPath, depth+1)...)
+					continue
+				}
+			}
+			matchedPattern := false
+			for pattern, ps := range s.PatternProperties {
+				if re, err := regexp.Compile(pattern); err == nil && re.MatchString(k) {
+					matchedPattern = true
+					ret = append(ret, sv.validate(ps, val[k], propPath, depth+1)...)
+				}
+			}
+			if matchedPattern || s.AdditionalProperties == nil {
+				continue
+			}
+			if isFalseSchema(s.AdditionalProperties) {
+				ret = append(ret, &SchemaViolation{Path: propPath, Message: "additional property is not allowed"})
+				continue
+			}
+			ret = append(ret, sv.validate(s.AdditionalProperties, val[k], propPath, depth+1)...)
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		ret = append(ret, sv.validate(sub, value, path, depth+1)...)
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if len(sv.validate(sub, value, path, depth+1)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			add("value does not match any of the schemas in anyOf")
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, sub := range s.OneOf {
+			if len(sv.validate(sub, value, path, depth+1)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			add("value matches %d of the schemas in oneOf, expected exactly 1", matched)
+		}
+	}
+	if s.Not != nil && len(sv.validate(s.Not, value, path, depth+1)) == 0 {
+		add("value should not match the schema in not")
+	}
+
+	return ret
+}
+
+func matchType(t string, value any) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		f, ok := toFloat(value)
+		return ok && f == math.Trunc(f)
+	default:
+		return true
+	}
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	case json.Number, float64:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		if v == "" {
+			return 0, false
+		}
+		f, err := v.Float64()
+		return f, err == nil
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// jsonEqual compares two values by their JSON, so that numbers of different go types can be equal.
+func jsonEqual(a, b any) bool {
+	fa, okA := toFloat(a)
+	fb, okB := toFloat(b)
+	if okA && okB {
+		return fa == fb
+	}
+	ba, errA := json.Marshal(a)
+	bb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ba, bb)
+}
+
+func marshalForMessage(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
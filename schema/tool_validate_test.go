@@ -0,0 +1,146 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func violationStrings(vs []*SchemaViolation) []string {
+	ret := make([]string, 0, len(vs))
+	for _, v := range vs {
+		ret = append(ret, v.String())
+	}
+	return ret
+}
+
+func TestValidateArguments(t *testing.T) {
+	t.Run("params", func(t *testing.T) {
+		p := NewParamsOneOfByParams(map[string]*ParameterInfo{
+			"city": {Type: String, Required: true},
+			"unit": {Type: String, Enum: []string{"celsius", "fahrenheit"}},
+			"days": {Type: Integer},
+			"tags": {Type: Array, ElemInfo: &ParameterInfo{Type: String}},
+		})
+
+		vs, err := p.ValidateArguments(`{"city":"paris","unit":"celsius","days":3,"tags":["a"]}`)
+		assert.NoError(t, err)
+		assert.Empty(t, vs)
+
+		vs, err = p.ValidateArguments(`{"unit":"kelvin","days":1.5,"tags":["a",1]}`)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"/city: required property is missing",
+			"/days: expected type integer, got number",
+			"/tags/1: expected type string, got number",
+			`/unit: value "kelvin" is not one of the allowed values ["celsius","fahrenheit"]`,
+		}, violationStrings(vs))
+
+		_, err = p.ValidateArguments(`{"city":`)
+		assert.Error(t, err)
+	})
+
+	t.Run("json schema", func(t *testing.T) {
+		js := &jsonschema.Schema{}
+		assert.NoError(t, json.Unmarshal([]byte(`{
+			"type": "object",
+			"properties": {
+				"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+				"age": {"type": ["integer", "null"], "minimum": 0, "exclusiveMaximum": 150},
+				"pet": {"$ref": "#/$defs/pet"}
+			},
+			"required": ["name"],
+			"additionalProperties": false,
+			"$defs": {
+				"pet": {"oneOf": [{"type": "string"}, {"type": "object", "required": ["kind"]}]}
+			}
+		}`), js))
+		p := NewParamsOneOfByJSONSchema(js)
+
+		vs, err := p.ValidateArguments(`{"name":"ab","age":null,"pet":{"kind":"cat"}}`)
+		assert.NoError(t, err)
+		assert.Empty(t, vs)
+
+		vs, err = p.ValidateArguments(`{"name":"A","age":150,"pet":1,"extra":true}`)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"/age: value 150 is not less than exclusiveMaximum 150",
+			"/extra: additional property is not allowed",
+			"/name: length 1 is less than minLength 2",
+			"/name: value does not match pattern ^[a-z]+$",
+			"/pet: value matches 0 of the schemas in oneOf, expected exactly 1",
+		}, violationStrings(vs))
+	})
+
+	t.Run("openapi v3", func(t *testing.T) {
+		p := NewParamsOneOfByOpenAPIV3(&openapi3.Schema{
+			Type:       openapi3.TypeObject,
+			Properties: openapi3.Schemas{"q": openapi3.NewStringSchema().NewRef()},
+			Required:   []string{"q"},
+		})
+		vs, err := p.ValidateArguments(`{}`)
+		assert.NoError(t, err)
+		assert.Len(t, vs, 1)
+
+		vs, err = p.ValidateArguments(`{"q":"x"}`)
+		assert.NoError(t, err)
+		assert.Empty(t, vs)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var p *ParamsOneOf
+		vs, err := p.ValidateArguments(`{"any":1}`)
+		assert.NoError(t, err)
+		assert.Empty(t, vs)
+	})
+}
+
+func TestValidateOutput(t *testing.T) {
+	info := &ToolInfo{Name: "weather"}
+	vs, err := info.ValidateOutput(`anything`)
+	assert.NoError(t, err)
+	assert.Empty(t, vs)
+
+	info.OutputSchema = &jsonschema.Schema{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"type":"object","properties":{"temp":{"type":"number"}},"required":["temp"]}`), info.OutputSchema))
+
+	vs, err = info.ValidateOutput(`{"temp":21.5}`)
+	assert.NoError(t, err)
+	assert.Empty(t, vs)
+
+	vs, err = info.ValidateOutput(`{"temp":"hot"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/temp: expected type number, got string"}, violationStrings(vs))
+
+	// plain text is validated as a string
+	vs, err = info.ValidateOutput(`sunny`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"expected type object, got string"}, violationStrings(vs))
+
+	// outputs looking like json are validated as strings by a string schema
+	info.OutputSchema = &jsonschema.Schema{Type: "string"}
+	for _, output := range []string{`42`, `true`, `{"a":1}`, `sunny`} {
+		vs, err = info.ValidateOutput(output)
+		assert.NoError(t, err)
+		assert.Empty(t, vs, output)
+	}
+}
@@ -0,0 +1,425 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wireformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/mrh997/eino/schema"
+)
+
+const providerAnthropic = "anthropic"
+
+type anthropicRequest struct {
+	Model     string             `json:"model,omitempty"`
+	MaxTokens int                `json:"max_tokens,omitempty"`
+	System    json.RawMessage    `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	Tools     []anthropicTool    `json:"tools,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+type anthropicBlock struct {
+	Type      string           `json:"type"`
+	Text      string           `json:"text,omitempty"`
+	Thinking  string           `json:"thinking,omitempty"`
+	Signature string           `json:"signature,omitempty"`
+	Source    *anthropicSource `json:"source,omitempty"`
+	ID        string           `json:"id,omitempty"`
+	Name      string           `json:"name,omitempty"`
+	Input     json.RawMessage  `json:"input,omitempty"`
+	ToolUseID string           `json:"tool_use_id,omitempty"`
+	Content   json.RawMessage  `json:"content,omitempty"`
+}
+
+type anthropicSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+	FileID    string `json:"file_id,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	InputSchema *jsonschema.Schema `json:"input_schema,omitempty"`
+}
+
+type anthropicResponse struct {
+	ID         string           `json:"id,omitempty"`
+	Type       string           `json:"type"`
+	Role       string           `json:"role"`
+	Model      string           `json:"model,omitempty"`
+	Content    []anthropicBlock `json:"content"`
+	StopReason string           `json:"stop_reason,omitempty"`
+	Usage      *anthropicUsage  `json:"usage,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens          int `json:"input_tokens"`
+	OutputTokens         int `json:"output_tokens"`
+	CacheReadInputTokens int `json:"cache_read_input_tokens,omitempty"`
+}
+
+// anthropicSignatureKey is the key of the signature of the thinking block in Message.Extra,
+// which is required when the thinking is sent back to Anthropic.
+const anthropicSignatureKey = "anthropic_thinking_signature"
+
+// MarshalAnthropicRequest converts the request to the JSON body of Anthropic messages.
+// system messages are moved to the system field, and the tool messages become tool_result blocks of user messages,
+// consecutive tool messages are merged into one user message.
+// audio and video parts are not supported.
+func MarshalAnthropicRequest(req *Request) ([]byte, error) {
+	r := anthropicRequest{Model: req.Model, MaxTokens: req.MaxTokens, Messages: make([]anthropicMessage, 0, len(req.Messages))}
+
+	var system []anthropicBlock
+	var lastBlocks []anthropicBlock
+	lastIsTool := false
+	flush := func(role string) error {
+		raw, err := json.Marshal(lastBlocks)
+		if err != nil {
+			return fmt.Errorf("failed to marshal anthropic message content: %w", err)
+		}
+		r.Messages = append(r.Messages, anthropicMessage{Role: role, Content: raw})
+		return nil
+	}
+
+	for _, msg := range req.Messages {
+		if msg.Role == schema.System {
+			system = append(system, anthropicBlock{Type: "text", Text: msg.Content})
+			continue
+		}
+
+		blocks, err := toAnthropicBlocks(msg)
+		if err != nil {
+			return nil, err
+		}
+		if msg.Role == schema.Tool && lastIsTool {
+			// merge into the user message of the previous tool message
+			r.Messages = r.Messages[:len(r.Messages)-1]
+			lastBlocks = append(lastBlocks, blocks...)
+		} else {
+			lastBlocks = blocks
+		}
+		lastIsTool = msg.Role == schema.Tool
+
+		role := "user"
+		if msg.Role == schema.Assistant {
+			role = "assistant"
+		}
+		if err = flush(role); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(system) > 0 {
+		raw, err := json.Marshal(system)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal anthropic system: %w", err)
+		}
+		r.System = raw
+	}
+	for _, info := range req.Tools {
+		params, err := toolParameters(info)
+		if err != nil {
+			return nil, err
+		}
+		r.Tools = append(r.Tools, anthropicTool{Name: info.Name, Description: info.Desc, InputSchema: params})
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalAnthropicRequest converts the JSON body of Anthropic messages to the request.
+// the system field becomes a system message at the start, and each tool_result block becomes a tool message.
+func UnmarshalAnthropicRequest(data []byte) (*Request, error) {
+	var r anthropicRequest
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal anthropic request: %w", err)
+	}
+
+	req := &Request{Model: r.Model, MaxTokens: r.MaxTokens}
+	if len(r.System) > 0 {
+		system, err := anthropicBlocks(r.System)
+		if err != nil {
+			return nil, fmt.Errorf("invalid anthropic system: %w", err)
+		}
+		for _, b := range system {
+			req.Messages = append(req.Messages, schema.SystemMessage(b.Text))
+		}
+	}
+
+	toolNames := make(map[string]string)
+	for _, m := range r.Messages {
+		blocks, err := anthropicBlocks(m.Content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid anthropic message content: %w", err)
+		}
+		msgs, err := fromAnthropicBlocks(m.Role, blocks, toolNames)
+		if err != nil {
+			return nil, err
+		}
+		req.Messages = append(req.Messages, msgs...)
+	}
+	for _, t := range r.Tools {
+		req.Tools = append(req.Tools, toolInfo(t.Name, t.Description, t.InputSchema))
+	}
+	return req, nil
+}
+
+// MarshalAnthropicResponse converts the response to the JSON body of the Anthropic message.
+func MarshalAnthropicResponse(resp *Response) ([]byte, error) {
+	blocks, err := toAnthropicBlocks(resp.Message)
+	if err != nil {
+		return nil, err
+	}
+	r := anthropicResponse{ID: resp.ID, Type: "message", Role: "assistant", Model: resp.Model, Content: blocks}
+	if meta := resp.Message.ResponseMeta; meta != nil {
+		r.StopReason = meta.FinishReason
+		if meta.Usage != nil {
+			r.Usage = &anthropicUsage{
+				InputTokens:          meta.Usage.PromptTokens,
+				OutputTokens:         meta.Usage.CompletionTokens,
+				CacheReadInputTokens: meta.Usage.PromptTokenDetails.CachedTokens,
+			}
+		}
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalAnthropicResponse converts the JSON body of the Anthropic message to the response.
+func UnmarshalAnthropicResponse(data []byte) (*Response, error) {
+	var r anthropicResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal anthropic response: %w", err)
+	}
+
+	msgs, err := fromAnthropicBlocks("assistant", r.Content, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	msg := msgs[0]
+	if r.StopReason != "" || r.Usage != nil {
+		msg.ResponseMeta = &schema.ResponseMeta{FinishReason: r.StopReason}
+		if r.Usage != nil {
+			msg.ResponseMeta.Usage = &schema.TokenUsage{
+				PromptTokens:       r.Usage.InputTokens,
+				PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: r.Usage.CacheReadInputTokens},
+				CompletionTokens:   r.Usage.OutputTokens,
+				TotalTokens:        r.Usage.InputTokens + r.Usage.OutputTokens,
+			}
+		}
+	}
+	return &Response{ID: r.ID, Model: r.Model, Message: msg}, nil
+}
+
+// anthropicBlocks parses the content in the form of a string or blocks.
+func anthropicBlocks(raw json.RawMessage) ([]anthropicBlock, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return []anthropicBlock{{Type: "text", Text: text}}, nil
+	}
+	var blocks []anthropicBlock
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func toAnthropicBlocks(msg *schema.Message) ([]anthropicBlock, error) {
+	var blocks []anthropicBlock
+	if msg.ReasoningContent != "" {
+		signature, _ := msg.Extra[anthropicSignatureKey].(string)
+		blocks = append(blocks, anthropicBlock{Type: "thinking", Thinking: msg.ReasoningContent, Signature: signature})
+	}
+
+	content, err := toAnthropicContent(msg)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Role == schema.Tool {
+		var result any = msg.Content
+		if len(msg.MultiContent) > 0 {
+			result = content
+		}
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal anthropic tool result: %w", err)
+		}
+		return append(blocks, anthropicBlock{Type: "tool_result", ToolUseID: msg.ToolCallID, Content: raw}), nil
+	}
+	blocks = append(blocks, content...)
+
+	for _, tc := range msg.ToolCalls {
+		input, err := rawArguments(tc.Function.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, anthropicBlock{Type: "tool_use", ID: tc.ID, Name: tc.Function.Name, Input: input})
+	}
+	return blocks, nil
+}
+
+func toAnthropicContent(msg *schema.Message) ([]anthropicBlock, error) {
+	if len(msg.MultiContent) == 0 {
+		if msg.Content == "" {
+			return nil, nil
+		}
+		return []anthropicBlock{{Type: "text", Text: msg.Content}}, nil
+	}
+
+	blocks := make([]anthropicBlock, 0, len(msg.MultiContent))
+	for _, part := range msg.MultiContent {
+		if part.Type == schema.ChatMessagePartTypeText {
+			blocks = append(blocks, anthropicBlock{Type: "text", Text: part.Text})
+			continue
+		}
+
+		url, uri, mimeType, ok := mediaOf(part)
+		if !ok || (part.Type != schema.ChatMessagePartTypeImageURL && part.Type != schema.ChatMessagePartTypeFileURL) {
+			return nil, unsupportedPart(providerAnthropic, part)
+		}
+		source := &anthropicSource{}
+		if dataMIME, data, isData := parseDataURL(url); isData {
+			source.Type, source.MediaType, source.Data = "base64", dataMIME, data
+		} else if url != "" {
+			source.Type, source.URL = "url", url
+		} else {
+			source.Type, source.FileID = "file", uri
+		}
+		if source.MediaType == "" {
+			source.MediaType = mimeType
+		}
+
+		blockType := "image"
+		if part.Type == schema.ChatMessagePartTypeFileURL {
+			blockType = "document"
+		}
+		blocks = append(blocks, anthropicBlock{Type: blockType, Source: source})
+	}
+	return blocks, nil
+}
+
+// fromAnthropicBlocks converts the blocks of a message, the tool_result blocks become separate tool messages,
+// toolNames records the names of the tool_use blocks by ID to set the tool names of the tool messages.
+func fromAnthropicBlocks(role string, blocks []anthropicBlock, toolNames map[string]string) ([]*schema.Message, error) {
+	msg := &schema.Message{Role: schema.User}
+	if role == "assistant" {
+		msg.Role = schema.Assistant
+	}
+
+	var toolMsgs []*schema.Message
+	var parts []schema.ChatMessagePart
+	hasMedia := false
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			parts = append(parts, schema.ChatMessagePart{Type: schema.ChatMessagePartTypeText, Text: b.Text})
+		case "thinking":
+			msg.ReasoningContent += b.Thinking
+			if b.Signature != "" {
+				if msg.Extra == nil {
+					msg.Extra = make(map[string]any)
+				}
+				msg.Extra[anthropicSignatureKey] = b.Signature
+			}
+		case "image", "document":
+			if b.Source == nil {
+				return nil, fmt.Errorf("anthropic %s block has no source", b.Type)
+			}
+			url := b.Source.URL
+			if b.Source.Type == "base64" {
+				url = dataURL(b.Source.MediaType, b.Source.Data)
+			}
+			if b.Type == "image" {
+				parts = append(parts, schema.ChatMessagePart{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{
+					URL:      url,
+					URI:      b.Source.FileID,
+					MIMEType: b.Source.MediaType,
+				}})
+			} else {
+				parts = append(parts, schema.ChatMessagePart{Type: schema.ChatMessagePartTypeFileURL, FileURL: &schema.ChatMessageFileURL{
+					URL:      url,
+					URI:      b.Source.FileID,
+					MIMEType: b.Source.MediaType,
+				}})
+			}
+			hasMedia = true
+		case "tool_use":
+			toolNames[b.ID] = b.Name
+			msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+				ID:       b.ID,
+				Type:     "function",
+				Function: schema.FunctionCall{Name: b.Name, Arguments: string(b.Input)},
+			})
+		case "tool_result":
+			toolMsg, err := fromAnthropicToolResult(b, toolNames)
+			if err != nil {
+				return nil, err
+			}
+			toolMsgs = append(toolMsgs, toolMsg)
+		default:
+			return nil, fmt.Errorf("unknown anthropic content block: %s", b.Type)
+		}
+	}
+
+	if hasMedia {
+		msg.MultiContent = parts
+	} else {
+		texts := make([]string, len(parts))
+		for i, p := range parts {
+			texts[i] = p.Text
+		}
+		msg.Content = strings.Join(texts, "")
+	}
+
+	if len(toolMsgs) > 0 && len(parts) == 0 && len(msg.ToolCalls) == 0 && msg.ReasoningContent == "" {
+		return toolMsgs, nil
+	}
+	return append(toolMsgs, msg), nil
+}
+
+func fromAnthropicToolResult(b anthropicBlock, toolNames map[string]string) (*schema.Message, error) {
+	msg := &schema.Message{Role: schema.Tool, ToolCallID: b.ToolUseID, ToolName: toolNames[b.ToolUseID]}
+	if len(b.Content) == 0 {
+		return msg, nil
+	}
+	var text string
+	if err := json.Unmarshal(b.Content, &text); err == nil {
+		msg.Content = text
+		return msg, nil
+	}
+
+	blocks, err := anthropicBlocks(b.Content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid anthropic tool result content: %w", err)
+	}
+	msgs, err := fromAnthropicBlocks("user", blocks, toolNames)
+	if err != nil {
+		return nil, err
+	}
+	msg.Content, msg.MultiContent = msgs[0].Content, msgs[0].MultiContent
+	return msg, nil
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wireformat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/schema"
+)
+
+func TestAnthropic(t *testing.T) {
+	t.Run("request", func(t *testing.T) {
+		req := testRequest()
+		data, err := MarshalAnthropicRequest(req)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"system":[{"type":"text","text":"be helpful"}]`)
+		assert.Contains(t, string(data), `{"type":"image","source":{"type":"base64","media_type":"image/png","data":"iVBO"}}`)
+		assert.Contains(t, string(data), `{"type":"tool_use","id":"c1","name":"weather","input":{"city":"bj"}}`)
+		// the tool results are merged into one user message
+		assert.Contains(t, string(data), `{"role":"user","content":[{"type":"tool_result","tool_use_id":"c1","content":"sunny"},{"type":"tool_result","tool_use_id":"c2","content":"{\"hour\":9}"}]}`)
+
+		actual, err := UnmarshalAnthropicRequest(data)
+		assert.NoError(t, err)
+		assert.Equal(t, "m", actual.Model)
+		assertRequest(t, req, actual)
+	})
+
+	t.Run("response", func(t *testing.T) {
+		resp := testResponse()
+		data, err := MarshalAnthropicResponse(resp)
+		assert.NoError(t, err)
+		actual, err := UnmarshalAnthropicResponse(data)
+		assert.NoError(t, err)
+		assert.Equal(t, resp, actual)
+
+		actual, err = UnmarshalAnthropicResponse([]byte(`{"type":"message","role":"assistant","content":[{"type":"thinking","thinking":"hmm","signature":"sig"},{"type":"text","text":"hi"}],"stop_reason":"end_turn"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, "hi", actual.Message.Content)
+		assert.Equal(t, "hmm", actual.Message.ReasoningContent)
+		assert.Equal(t, map[string]any{anthropicSignatureKey: "sig"}, actual.Message.Extra)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := MarshalAnthropicRequest(&Request{Messages: []*schema.Message{{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeAudioURL, AudioURL: &schema.ChatMessageAudioURL{URL: "data:audio/wav;base64,UklG"}},
+		}}}})
+		assert.ErrorContains(t, err, "audio_url part is not supported by anthropic")
+	})
+}
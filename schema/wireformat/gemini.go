@@ -0,0 +1,385 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wireformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/mrh997/eino/schema"
+)
+
+const providerGemini = "gemini"
+
+type geminiRequest struct {
+	Contents          []geminiContent         `json:"contents"`
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	Tools             []geminiTool            `json:"tools,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiGenerationConfig struct {
+	MaxOutputTokens int `json:"maxOutputTokens,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	Thought          bool                    `json:"thought,omitempty"`
+	InlineData       *geminiInlineData       `json:"inlineData,omitempty"`
+	FileData         *geminiFileData         `json:"fileData,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiInlineData struct {
+	MIMEType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+type geminiFileData struct {
+	MIMEType string `json:"mimeType,omitempty"`
+	FileURI  string `json:"fileUri"`
+}
+
+type geminiFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	ID       string          `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Parameters  *jsonschema.Schema `json:"parameters,omitempty"`
+}
+
+type geminiResponse struct {
+	Candidates    []geminiCandidate `json:"candidates"`
+	UsageMetadata *geminiUsage      `json:"usageMetadata,omitempty"`
+	ModelVersion  string            `json:"modelVersion,omitempty"`
+	ResponseID    string            `json:"responseId,omitempty"`
+}
+
+type geminiCandidate struct {
+	Content      geminiContent `json:"content"`
+	FinishReason string        `json:"finishReason,omitempty"`
+}
+
+type geminiUsage struct {
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"`
+}
+
+// geminiResponseContentKey is the key of the tool output in the function response, when the output isn't a JSON object.
+const geminiResponseContentKey = "content"
+
+// MarshalGeminiRequest converts the request to the JSON body of Gemini generateContent, the model isn't in the body.
+// system messages are moved to systemInstruction, and the tool messages become functionResponse parts of user contents,
+// consecutive tool messages are merged into one content.
+// a tool output that isn't a JSON object is wrapped as {"content": output}, the output of MultiContent is its text parts joined,
+// and other parts of tool messages are not supported.
+func MarshalGeminiRequest(req *Request) ([]byte, error) {
+	r := geminiRequest{Contents: make([]geminiContent, 0, len(req.Messages))}
+	if req.MaxTokens > 0 {
+		r.GenerationConfig = &geminiGenerationConfig{MaxOutputTokens: req.MaxTokens}
+	}
+
+	toolNames := make(map[string]string)
+	lastIsTool := false
+	for _, msg := range req.Messages {
+		for _, tc := range msg.ToolCalls {
+			toolNames[tc.ID] = tc.Function.Name
+		}
+		if msg.Role == schema.System {
+			if r.SystemInstruction == nil {
+				r.SystemInstruction = &geminiContent{}
+			}
+			r.SystemInstruction.Parts = append(r.SystemInstruction.Parts, geminiPart{Text: msg.Content})
+			continue
+		}
+
+		content, err := toGeminiContent(msg, toolNames)
+		if err != nil {
+			return nil, err
+		}
+		if msg.Role == schema.Tool && lastIsTool {
+			last := &r.Contents[len(r.Contents)-1]
+			last.Parts = append(last.Parts, content.Parts...)
+		} else {
+			r.Contents = append(r.Contents, content)
+		}
+		lastIsTool = msg.Role == schema.Tool
+	}
+
+	if len(req.Tools) > 0 {
+		tool := geminiTool{}
+		for _, info := range req.Tools {
+			params, err := toolParameters(info)
+			if err != nil {
+				return nil, err
+			}
+			tool.FunctionDeclarations = append(tool.FunctionDeclarations, geminiFunctionDeclaration{Name: info.Name, Description: info.Desc, Parameters: params})
+		}
+		r.Tools = []geminiTool{tool}
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalGeminiRequest converts the JSON body of Gemini generateContent to the request.
+// systemInstruction becomes a system message at the start, and each functionResponse part becomes a tool message.
+func UnmarshalGeminiRequest(data []byte) (*Request, error) {
+	var r geminiRequest
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal gemini request: %w", err)
+	}
+
+	req := &Request{}
+	if r.GenerationConfig != nil {
+		req.MaxTokens = r.GenerationConfig.MaxOutputTokens
+	}
+	if r.SystemInstruction != nil {
+		for _, p := range r.SystemInstruction.Parts {
+			req.Messages = append(req.Messages, schema.SystemMessage(p.Text))
+		}
+	}
+	for _, c := range r.Contents {
+		msgs, err := fromGeminiContent(c)
+		if err != nil {
+			return nil, err
+		}
+		req.Messages = append(req.Messages, msgs...)
+	}
+	for _, t := range r.Tools {
+		for _, f := range t.FunctionDeclarations {
+			req.Tools = append(req.Tools, toolInfo(f.Name, f.Description, f.Parameters))
+		}
+	}
+	return req, nil
+}
+
+// MarshalGeminiResponse converts the response to the JSON body of Gemini generateContent, with the message as the only candidate.
+func MarshalGeminiResponse(resp *Response) ([]byte, error) {
+	content, err := toGeminiContent(resp.Message, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	r := geminiResponse{
+		Candidates:   []geminiCandidate{{Content: content}},
+		ModelVersion: resp.Model,
+		ResponseID:   resp.ID,
+	}
+	if meta := resp.Message.ResponseMeta; meta != nil {
+		r.Candidates[0].FinishReason = meta.FinishReason
+		if meta.Usage != nil {
+			r.UsageMetadata = &geminiUsage{
+				PromptTokenCount:        meta.Usage.PromptTokens,
+				CandidatesTokenCount:    meta.Usage.CompletionTokens,
+				TotalTokenCount:         meta.Usage.TotalTokens,
+				CachedContentTokenCount: meta.Usage.PromptTokenDetails.CachedTokens,
+			}
+		}
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalGeminiResponse converts the JSON body of Gemini generateContent to the response, with the message of the first candidate.
+func UnmarshalGeminiResponse(data []byte) (*Response, error) {
+	var r geminiResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal gemini response: %w", err)
+	}
+	if len(r.Candidates) == 0 {
+		return nil, fmt.Errorf("gemini response has no candidate")
+	}
+
+	msgs, err := fromGeminiContent(r.Candidates[0].Content)
+	if err != nil {
+		return nil, err
+	}
+	msg := msgs[len(msgs)-1]
+	if r.Candidates[0].FinishReason != "" || r.UsageMetadata != nil {
+		msg.ResponseMeta = &schema.ResponseMeta{FinishReason: r.Candidates[0].FinishReason}
+		if u := r.UsageMetadata; u != nil {
+			msg.ResponseMeta.Usage = &schema.TokenUsage{
+				PromptTokens:       u.PromptTokenCount,
+				PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: u.CachedContentTokenCount},
+				CompletionTokens:   u.CandidatesTokenCount,
+				TotalTokens:        u.TotalTokenCount,
+			}
+		}
+	}
+	return &Response{ID: r.ResponseID, Model: r.ModelVersion, Message: msg}, nil
+}
+
+func toGeminiContent(msg *schema.Message, toolNames map[string]string) (geminiContent, error) {
+	c := geminiContent{Role: "user"}
+	if msg.Role == schema.Assistant {
+		c.Role = "model"
+	}
+
+	if msg.Role == schema.Tool {
+		output := msg.Content
+		if len(msg.MultiContent) > 0 {
+			// function responses can only carry JSON, so the text parts are joined as the output
+			var sb strings.Builder
+			for _, part := range msg.MultiContent {
+				if part.Type != schema.ChatMessagePartTypeText {
+					return geminiContent{}, fmt.Errorf("%s part of tool message is not supported by %s", part.Type, providerGemini)
+				}
+				sb.WriteString(part.Text)
+			}
+			output = sb.String()
+		}
+		response := json.RawMessage(output)
+		if !isJSONObject(output) {
+			var err error
+			response, err = json.Marshal(map[string]string{geminiResponseContentKey: output})
+			if err != nil {
+				return geminiContent{}, fmt.Errorf("failed to marshal gemini function response: %w", err)
+			}
+		}
+		name := msg.ToolName
+		if name == "" {
+			name = toolNames[msg.ToolCallID]
+		}
+		c.Parts = append(c.Parts, geminiPart{FunctionResponse: &geminiFunctionResponse{ID: msg.ToolCallID, Name: name, Response: response}})
+		return c, nil
+	}
+
+	if msg.ReasoningContent != "" {
+		c.Parts = append(c.Parts, geminiPart{Text: msg.ReasoningContent, Thought: true})
+	}
+	if len(msg.MultiContent) == 0 && msg.Content != "" {
+		c.Parts = append(c.Parts, geminiPart{Text: msg.Content})
+	}
+	for _, part := range msg.MultiContent {
+		if part.Type == schema.ChatMessagePartTypeText {
+			c.Parts = append(c.Parts, geminiPart{Text: part.Text})
+			continue
+		}
+		url, uri, mimeType, ok := mediaOf(part)
+		if !ok {
+			return geminiContent{}, unsupportedPart(providerGemini, part)
+		}
+		if dataMIME, data, isData := parseDataURL(url); isData {
+			c.Parts = append(c.Parts, geminiPart{InlineData: &geminiInlineData{MIMEType: dataMIME, Data: data}})
+			continue
+		}
+		if uri == "" {
+			uri = url
+		}
+		c.Parts = append(c.Parts, geminiPart{FileData: &geminiFileData{MIMEType: mimeType, FileURI: uri}})
+	}
+	for _, tc := range msg.ToolCalls {
+		args, err := rawArguments(tc.Function.Arguments)
+		if err != nil {
+			return geminiContent{}, err
+		}
+		c.Parts = append(c.Parts, geminiPart{FunctionCall: &geminiFunctionCall{ID: tc.ID, Name: tc.Function.Name, Args: args}})
+	}
+	return c, nil
+}
+
+// fromGeminiContent converts a content, the functionResponse parts become separate tool messages.
+func fromGeminiContent(c geminiContent) ([]*schema.Message, error) {
+	msg := &schema.Message{Role: schema.User}
+	if c.Role == "model" {
+		msg.Role = schema.Assistant
+	}
+
+	var toolMsgs []*schema.Message
+	var parts []schema.ChatMessagePart
+	hasMedia := false
+	for _, p := range c.Parts {
+		switch {
+		case p.FunctionResponse != nil:
+			toolMsgs = append(toolMsgs, fromGeminiFunctionResponse(p.FunctionResponse))
+		case p.FunctionCall != nil:
+			args := string(p.FunctionCall.Args)
+			if args == "" {
+				args = "{}"
+			}
+			msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+				ID:       p.FunctionCall.ID,
+				Type:     "function",
+				Function: schema.FunctionCall{Name: p.FunctionCall.Name, Arguments: args},
+			})
+		case p.InlineData != nil:
+			parts = append(parts, mediaPart(dataURL(p.InlineData.MIMEType, p.InlineData.Data), "", p.InlineData.MIMEType))
+			hasMedia = true
+		case p.FileData != nil:
+			parts = append(parts, mediaPart("", p.FileData.FileURI, p.FileData.MIMEType))
+			hasMedia = true
+		case p.Thought:
+			msg.ReasoningContent += p.Text
+		default:
+			parts = append(parts, schema.ChatMessagePart{Type: schema.ChatMessagePartTypeText, Text: p.Text})
+		}
+	}
+
+	if hasMedia {
+		msg.MultiContent = parts
+	} else {
+		texts := make([]string, len(parts))
+		for i, p := range parts {
+			texts[i] = p.Text
+		}
+		msg.Content = strings.Join(texts, "")
+	}
+
+	if len(toolMsgs) > 0 && len(parts) == 0 && len(msg.ToolCalls) == 0 && msg.ReasoningContent == "" {
+		return toolMsgs, nil
+	}
+	return append(toolMsgs, msg), nil
+}
+
+func fromGeminiFunctionResponse(r *geminiFunctionResponse) *schema.Message {
+	msg := &schema.Message{Role: schema.Tool, ToolCallID: r.ID, ToolName: r.Name, Content: string(r.Response)}
+	var wrapped map[string]json.RawMessage
+	if err := json.Unmarshal(r.Response, &wrapped); err == nil && len(wrapped) == 1 {
+		var content string
+		if err = json.Unmarshal(wrapped[geminiResponseContentKey], &content); err == nil {
+			msg.Content = content
+		}
+	}
+	return msg
+}
+
+func isJSONObject(s string) bool {
+	var obj map[string]json.RawMessage
+	return json.Unmarshal([]byte(s), &obj) == nil && obj != nil
+}
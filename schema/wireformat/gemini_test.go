@@ -0,0 +1,80 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wireformat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/schema"
+)
+
+func TestGemini(t *testing.T) {
+	t.Run("request", func(t *testing.T) {
+		req := testRequest()
+		req.Messages[4].ToolName = ""
+		data, err := MarshalGeminiRequest(req)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"systemInstruction":{"parts":[{"text":"be helpful"}]}`)
+		assert.Contains(t, string(data), `{"role":"model","parts":[{"text":"check the weather","thought":true},{"functionCall":{"id":"c1","name":"weather","args":{"city":"bj"}}},{"functionCall":{"id":"c2","name":"clock","args":{}}}]}`)
+		// the tool name is found by the tool call, and the output which isn't a JSON object is wrapped
+		assert.Contains(t, string(data), `{"role":"user","parts":[{"functionResponse":{"id":"c1","name":"weather","response":{"content":"sunny"}}},{"functionResponse":{"id":"c2","name":"clock","response":{"hour":9}}}]}`)
+		assert.Contains(t, string(data), `"generationConfig":{"maxOutputTokens":100}`)
+
+		actual, err := UnmarshalGeminiRequest(data)
+		assert.NoError(t, err)
+		req.Messages[4].ToolName = "clock"
+		assertRequest(t, req, actual)
+	})
+
+	t.Run("response", func(t *testing.T) {
+		resp := testResponse()
+		data, err := MarshalGeminiResponse(resp)
+		assert.NoError(t, err)
+		actual, err := UnmarshalGeminiResponse(data)
+		assert.NoError(t, err)
+		assert.Equal(t, resp, actual)
+	})
+
+	t.Run("file data", func(t *testing.T) {
+		data, err := MarshalGeminiRequest(&Request{Messages: []*schema.Message{{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeVideoURL, VideoURL: &schema.ChatMessageVideoURL{URL: "gs://bucket/a.mp4", MIMEType: "video/mp4"}},
+		}}}})
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `{"fileData":{"mimeType":"video/mp4","fileUri":"gs://bucket/a.mp4"}}`)
+
+		actual, err := UnmarshalGeminiRequest(data)
+		assert.NoError(t, err)
+		assert.Equal(t, []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeVideoURL, VideoURL: &schema.ChatMessageVideoURL{URI: "gs://bucket/a.mp4", MIMEType: "video/mp4"}},
+		}, actual.Messages[0].MultiContent)
+	})
+	t.Run("tool multi content", func(t *testing.T) {
+		data, err := MarshalGeminiRequest(&Request{Messages: []*schema.Message{{Role: schema.Tool, ToolCallID: "c1", ToolName: "weather", MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeText, Text: "sunny, "},
+			{Type: schema.ChatMessagePartTypeText, Text: "25°C"},
+		}}}})
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `{"functionResponse":{"id":"c1","name":"weather","response":{"content":"sunny, 25°C"}}}`)
+
+		_, err = MarshalGeminiRequest(&Request{Messages: []*schema.Message{{Role: schema.Tool, ToolCallID: "c1", ToolName: "chart", MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: "https://example.com/a.png"}},
+		}}}})
+		assert.ErrorContains(t, err, "image_url part of tool message is not supported by gemini")
+	})
+}
@@ -0,0 +1,365 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wireformat
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/mrh997/eino/schema"
+)
+
+const providerOpenAI = "openai"
+
+type openAIRequest struct {
+	Model     string          `json:"model,omitempty"`
+	MaxTokens int             `json:"max_tokens,omitempty"`
+	Messages  []openAIMessage `json:"messages"`
+	Tools     []openAITool    `json:"tools,omitempty"`
+}
+
+type openAIMessage struct {
+	Role             string           `json:"role"`
+	Content          json.RawMessage  `json:"content,omitempty"`
+	Name             string           `json:"name,omitempty"`
+	ToolCalls        []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID       string           `json:"tool_call_id,omitempty"`
+	ReasoningContent string           `json:"reasoning_content,omitempty"`
+}
+
+type openAIPart struct {
+	Type       string            `json:"type"`
+	Text       string            `json:"text,omitempty"`
+	ImageURL   *openAIImageURL   `json:"image_url,omitempty"`
+	InputAudio *openAIInputAudio `json:"input_audio,omitempty"`
+	File       *openAIFile       `json:"file,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type openAIInputAudio struct {
+	Data   string `json:"data"`
+	Format string `json:"format"`
+}
+
+type openAIFile struct {
+	FileData string `json:"file_data,omitempty"`
+	FileID   string `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Function openAIFunctionCall `json:"function"`
+}
+
+type openAIFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Parameters  *jsonschema.Schema `json:"parameters,omitempty"`
+}
+
+type openAIResponse struct {
+	ID      string         `json:"id,omitempty"`
+	Object  string         `json:"object"`
+	Model   string         `json:"model,omitempty"`
+	Choices []openAIChoice `json:"choices"`
+	Usage   *openAIUsage   `json:"usage,omitempty"`
+}
+
+type openAIChoice struct {
+	Index        int           `json:"index"`
+	Message      openAIMessage `json:"message"`
+	FinishReason string        `json:"finish_reason,omitempty"`
+}
+
+type openAIUsage struct {
+	PromptTokens        int                       `json:"prompt_tokens"`
+	CompletionTokens    int                       `json:"completion_tokens"`
+	TotalTokens         int                       `json:"total_tokens"`
+	PromptTokensDetails *openAIPromptTokenDetails `json:"prompt_tokens_details,omitempty"`
+}
+
+type openAIPromptTokenDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// MarshalOpenAIRequest converts the request to the JSON body of OpenAI chat completions.
+// audio parts must be base64 data URLs of mp3 or wav, and video parts are not supported.
+func MarshalOpenAIRequest(req *Request) ([]byte, error) {
+	r := openAIRequest{Model: req.Model, MaxTokens: req.MaxTokens, Messages: make([]openAIMessage, 0, len(req.Messages))}
+	for _, msg := range req.Messages {
+		m, err := toOpenAIMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		r.Messages = append(r.Messages, m)
+	}
+	for _, info := range req.Tools {
+		params, err := toolParameters(info)
+		if err != nil {
+			return nil, err
+		}
+		r.Tools = append(r.Tools, openAITool{
+			Type:     "function",
+			Function: openAIFunction{Name: info.Name, Description: info.Desc, Parameters: params},
+		})
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalOpenAIRequest converts the JSON body of OpenAI chat completions to the request.
+func UnmarshalOpenAIRequest(data []byte) (*Request, error) {
+	var r openAIRequest
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal openai request: %w", err)
+	}
+
+	req := &Request{Model: r.Model, MaxTokens: r.MaxTokens, Messages: make([]*schema.Message, 0, len(r.Messages))}
+	// the tool names of the tool messages are set by the tool calls
+	toolNames := make(map[string]string)
+	for _, m := range r.Messages {
+		msg, err := fromOpenAIMessage(m)
+		if err != nil {
+			return nil, err
+		}
+		for _, tc := range msg.ToolCalls {
+			toolNames[tc.ID] = tc.Function.Name
+		}
+		if msg.Role == schema.Tool {
+			msg.ToolName = toolNames[msg.ToolCallID]
+		}
+		req.Messages = append(req.Messages, msg)
+	}
+	for _, t := range r.Tools {
+		req.Tools = append(req.Tools, toolInfo(t.Function.Name, t.Function.Description, t.Function.Parameters))
+	}
+	return req, nil
+}
+
+// MarshalOpenAIResponse converts the response to the JSON body of the OpenAI chat completion, with the message as the only choice.
+func MarshalOpenAIResponse(resp *Response) ([]byte, error) {
+	m, err := toOpenAIMessage(resp.Message)
+	if err != nil {
+		return nil, err
+	}
+	r := openAIResponse{
+		ID:      resp.ID,
+		Object:  "chat.completion",
+		Model:   resp.Model,
+		Choices: []openAIChoice{{Message: m}},
+	}
+	if meta := resp.Message.ResponseMeta; meta != nil {
+		r.Choices[0].FinishReason = meta.FinishReason
+		if meta.Usage != nil {
+			r.Usage = &openAIUsage{
+				PromptTokens:     meta.Usage.PromptTokens,
+				CompletionTokens: meta.Usage.CompletionTokens,
+				TotalTokens:      meta.Usage.TotalTokens,
+			}
+			if meta.Usage.PromptTokenDetails.CachedTokens > 0 {
+				r.Usage.PromptTokensDetails = &openAIPromptTokenDetails{CachedTokens: meta.Usage.PromptTokenDetails.CachedTokens}
+			}
+		}
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalOpenAIResponse converts the JSON body of the OpenAI chat completion to the response, with the message of the first choice.
+func UnmarshalOpenAIResponse(data []byte) (*Response, error) {
+	var r openAIResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal openai response: %w", err)
+	}
+	if len(r.Choices) == 0 {
+		return nil, fmt.Errorf("openai response has no choice")
+	}
+
+	msg, err := fromOpenAIMessage(r.Choices[0].Message)
+	if err != nil {
+		return nil, err
+	}
+	if r.Choices[0].FinishReason != "" || r.Usage != nil {
+		msg.ResponseMeta = &schema.ResponseMeta{FinishReason: r.Choices[0].FinishReason}
+		if r.Usage != nil {
+			msg.ResponseMeta.Usage = &schema.TokenUsage{
+				PromptTokens:     r.Usage.PromptTokens,
+				CompletionTokens: r.Usage.CompletionTokens,
+				TotalTokens:      r.Usage.TotalTokens,
+			}
+			if r.Usage.PromptTokensDetails != nil {
+				msg.ResponseMeta.Usage.PromptTokenDetails.CachedTokens = r.Usage.PromptTokensDetails.CachedTokens
+			}
+		}
+	}
+	return &Response{ID: r.ID, Model: r.Model, Message: msg}, nil
+}
+
+func toOpenAIMessage(msg *schema.Message) (openAIMessage, error) {
+	m := openAIMessage{
+		Role:             string(msg.Role),
+		Name:             msg.Name,
+		ToolCallID:       msg.ToolCallID,
+		ReasoningContent: msg.ReasoningContent,
+	}
+	for _, tc := range msg.ToolCalls {
+		m.ToolCalls = append(m.ToolCalls, openAIToolCall{
+			ID:       tc.ID,
+			Type:     "function",
+			Function: openAIFunctionCall{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+		})
+	}
+
+	var content any
+	if len(msg.MultiContent) > 0 {
+		parts := make([]openAIPart, 0, len(msg.MultiContent))
+		for _, part := range msg.MultiContent {
+			p, err := toOpenAIPart(part)
+			if err != nil {
+				return openAIMessage{}, err
+			}
+			parts = append(parts, p)
+		}
+		content = parts
+	} else if msg.Content != "" || len(msg.ToolCalls) == 0 {
+		content = msg.Content
+	}
+	if content != nil {
+		raw, err := json.Marshal(content)
+		if err != nil {
+			return openAIMessage{}, fmt.Errorf("failed to marshal openai message content: %w", err)
+		}
+		m.Content = raw
+	}
+	return m, nil
+}
+
+// openAIAudioFormats maps the mime types of audio to the formats of openai input audio.
+var openAIAudioFormats = map[string]string{
+	"audio/mpeg":  "mp3",
+	"audio/mp3":   "mp3",
+	"audio/wav":   "wav",
+	"audio/wave":  "wav",
+	"audio/x-wav": "wav",
+}
+
+func toOpenAIPart(part schema.ChatMessagePart) (openAIPart, error) {
+	switch part.Type {
+	case schema.ChatMessagePartTypeText:
+		return openAIPart{Type: "text", Text: part.Text}, nil
+	case schema.ChatMessagePartTypeImageURL:
+		if part.ImageURL != nil {
+			return openAIPart{Type: "image_url", ImageURL: &openAIImageURL{URL: part.ImageURL.URL, Detail: string(part.ImageURL.Detail)}}, nil
+		}
+	case schema.ChatMessagePartTypeAudioURL:
+		if part.AudioURL != nil {
+			mimeType, data, ok := parseDataURL(part.AudioURL.URL)
+			if !ok {
+				return openAIPart{}, fmt.Errorf("audio part must be a base64 data url for openai")
+			}
+			format, ok := openAIAudioFormats[mimeType]
+			if !ok {
+				return openAIPart{}, fmt.Errorf("audio mime type %q is not supported by openai, only mp3 and wav are", mimeType)
+			}
+			return openAIPart{Type: "input_audio", InputAudio: &openAIInputAudio{Data: data, Format: format}}, nil
+		}
+	case schema.ChatMessagePartTypeFileURL:
+		if part.FileURL != nil {
+			return openAIPart{Type: "file", File: &openAIFile{FileData: part.FileURL.URL, FileID: part.FileURL.URI, Filename: part.FileURL.Name}}, nil
+		}
+	}
+	return openAIPart{}, unsupportedPart(providerOpenAI, part)
+}
+
+func fromOpenAIMessage(m openAIMessage) (*schema.Message, error) {
+	msg := &schema.Message{
+		Role:             schema.RoleType(m.Role),
+		Name:             m.Name,
+		ToolCallID:       m.ToolCallID,
+		ReasoningContent: m.ReasoningContent,
+	}
+	for _, tc := range m.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			ID:       tc.ID,
+			Type:     tc.Type,
+			Function: schema.FunctionCall{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+		})
+	}
+
+	if len(m.Content) == 0 || string(m.Content) == "null" {
+		return msg, nil
+	}
+	if err := json.Unmarshal(m.Content, &msg.Content); err == nil {
+		return msg, nil
+	}
+	var parts []openAIPart
+	if err := json.Unmarshal(m.Content, &parts); err != nil {
+		return nil, fmt.Errorf("invalid openai message content: %w", err)
+	}
+	for _, p := range parts {
+		part, err := fromOpenAIPart(p)
+		if err != nil {
+			return nil, err
+		}
+		msg.MultiContent = append(msg.MultiContent, part)
+	}
+	return msg, nil
+}
+
+func fromOpenAIPart(p openAIPart) (schema.ChatMessagePart, error) {
+	switch {
+	case p.Type == "text":
+		return schema.ChatMessagePart{Type: schema.ChatMessagePartTypeText, Text: p.Text}, nil
+	case p.Type == "image_url" && p.ImageURL != nil:
+		return schema.ChatMessagePart{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{
+			URL:    p.ImageURL.URL,
+			Detail: schema.ImageURLDetail(p.ImageURL.Detail),
+		}}, nil
+	case p.Type == "input_audio" && p.InputAudio != nil:
+		mimeType := "audio/" + p.InputAudio.Format
+		if p.InputAudio.Format == "mp3" {
+			mimeType = "audio/mpeg"
+		}
+		return schema.ChatMessagePart{Type: schema.ChatMessagePartTypeAudioURL, AudioURL: &schema.ChatMessageAudioURL{
+			URL:      dataURL(mimeType, p.InputAudio.Data),
+			MIMEType: mimeType,
+		}}, nil
+	case p.Type == "file" && p.File != nil:
+		return schema.ChatMessagePart{Type: schema.ChatMessagePartTypeFileURL, FileURL: &schema.ChatMessageFileURL{
+			URL:  p.File.FileData,
+			URI:  p.File.FileID,
+			Name: p.File.Filename,
+		}}, nil
+	}
+	return schema.ChatMessagePart{}, fmt.Errorf("unknown openai content part: %s", p.Type)
+}
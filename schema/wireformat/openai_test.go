@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wireformat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrh997/eino/schema"
+)
+
+func TestOpenAI(t *testing.T) {
+	t.Run("request", func(t *testing.T) {
+		req := testRequest()
+		data, err := MarshalOpenAIRequest(req)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `{"type":"image_url","image_url":{"url":"data:image/png;base64,iVBO"}}`)
+		assert.Contains(t, string(data), `{"role":"tool","content":"sunny","tool_call_id":"c1"}`)
+		assert.Contains(t, string(data), `"tools":[{"type":"function","function":{"name":"weather","description":"get the weather","parameters":{"properties":{"city":{"type":"string"}},"required":["city"],"type":"object"}}}]`)
+
+		actual, err := UnmarshalOpenAIRequest(data)
+		assert.NoError(t, err)
+		assert.Equal(t, "m", actual.Model)
+		// openai has no mime type of images
+		req.Messages[1].MultiContent[1].ImageURL.MIMEType = ""
+		assertRequest(t, req, actual)
+	})
+
+	t.Run("response", func(t *testing.T) {
+		resp := testResponse()
+		data, err := MarshalOpenAIResponse(resp)
+		assert.NoError(t, err)
+		actual, err := UnmarshalOpenAIResponse(data)
+		assert.NoError(t, err)
+		assert.Equal(t, resp, actual)
+
+		actual, err = UnmarshalOpenAIResponse([]byte(`{"choices":[{"message":{"role":"assistant","content":null,"tool_calls":[{"id":"1","type":"function","function":{"name":"a","arguments":"{}"}}]},"finish_reason":"tool_calls"}]}`))
+		assert.NoError(t, err)
+		assert.Equal(t, "", actual.Message.Content)
+		assert.Equal(t, "tool_calls", actual.Message.ResponseMeta.FinishReason)
+	})
+
+	t.Run("audio", func(t *testing.T) {
+		msg := &schema.Message{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeAudioURL, AudioURL: &schema.ChatMessageAudioURL{URL: "data:audio/wav;base64,UklG"}},
+		}}
+		data, err := MarshalOpenAIRequest(&Request{Messages: []*schema.Message{msg}})
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"input_audio":{"data":"UklG","format":"wav"}`)
+
+		for mimeType, format := range map[string]string{"audio/mpeg": "mp3", "audio/wave": "wav", "audio/x-wav": "wav"} {
+			msg.MultiContent[0].AudioURL.URL = "data:" + mimeType + ";base64,UklG"
+			data, err = MarshalOpenAIRequest(&Request{Messages: []*schema.Message{msg}})
+			assert.NoError(t, err)
+			assert.Contains(t, string(data), `"input_audio":{"data":"UklG","format":"`+format+`"}`)
+		}
+
+		// mp3 is read back as audio/mpeg
+		msg.MultiContent[0].AudioURL.URL = "data:audio/mpeg;base64,UklG"
+		data, err = MarshalOpenAIRequest(&Request{Messages: []*schema.Message{msg}})
+		assert.NoError(t, err)
+		req, err := UnmarshalOpenAIRequest(data)
+		assert.NoError(t, err)
+		assert.Equal(t, "data:audio/mpeg;base64,UklG", req.Messages[0].MultiContent[0].AudioURL.URL)
+
+		msg.MultiContent[0].AudioURL.URL = "data:audio/ogg;base64,UklG"
+		_, err = MarshalOpenAIRequest(&Request{Messages: []*schema.Message{msg}})
+		assert.ErrorContains(t, err, `audio mime type "audio/ogg" is not supported by openai`)
+
+		msg.MultiContent[0].AudioURL.URL = "https://example.com/a.wav"
+		_, err = MarshalOpenAIRequest(&Request{Messages: []*schema.Message{msg}})
+		assert.ErrorContains(t, err, "base64 data url")
+
+		_, err = MarshalOpenAIRequest(&Request{Messages: []*schema.Message{{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeVideoURL, VideoURL: &schema.ChatMessageVideoURL{URL: "https://example.com/a.mp4"}},
+		}}}})
+		assert.ErrorContains(t, err, "video_url part is not supported by openai")
+	})
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package wireformat converts the chat requests and responses between schema.Message and the JSON shapes of common chat APIs,
+// i.e. OpenAI chat completions, Anthropic messages and Gemini contents,
+// e.g. to store the traffic in a neutral format, to build local stand-in servers for tests, or to replay transcripts.
+package wireformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/mrh997/eino/schema"
+)
+
+// Request is the neutral form of a chat request.
+type Request struct {
+	// Model is the name of the model, not in the body of Gemini.
+	Model string
+	// MaxTokens is the max number of tokens to generate, 0 if not set.
+	MaxTokens int
+	Messages  []*schema.Message
+	Tools     []*schema.ToolInfo
+}
+
+// Response is the neutral form of a chat response,
+// the finish reason and the token usage are in the ResponseMeta of Message.
+type Response struct {
+	ID      string
+	Model   string
+	Message *schema.Message
+}
+
+// parseDataURL splits a data URL of RFC-2397 with base64 encoding into the mime type and the base64 data.
+func parseDataURL(url string) (mimeType, data string, ok bool) {
+	if !strings.HasPrefix(url, "data:") {
+		return "", "", false
+	}
+	meta, data, found := strings.Cut(url[len("data:"):], ",")
+	if !found || !strings.HasSuffix(meta, ";base64") {
+		return "", "", false
+	}
+	return strings.TrimSuffix(meta, ";base64"), data, true
+}
+
+func dataURL(mimeType, data string) string {
+	return "data:" + mimeType + ";base64," + data
+}
+
+func toolParameters(info *schema.ToolInfo) (*jsonschema.Schema, error) {
+	params, err := info.ParamsOneOf.ToJSONSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert parameters of tool[%s] to json schema: %w", info.Name, err)
+	}
+	return params, nil
+}
+
+func toolInfo(name, desc string, params *jsonschema.Schema) *schema.ToolInfo {
+	info := &schema.ToolInfo{Name: name, Desc: desc}
+	if params != nil {
+		info.ParamsOneOf = schema.NewParamsOneOfByJSONSchema(params)
+	}
+	return info
+}
+
+// rawArguments converts the JSON arguments of a tool call to a JSON object, empty arguments become an empty object.
+func rawArguments(arguments string) (json.RawMessage, error) {
+	if strings.TrimSpace(arguments) == "" {
+		return json.RawMessage("{}"), nil
+	}
+	if !json.Valid([]byte(arguments)) {
+		return nil, fmt.Errorf("tool call arguments are not valid json: %s", arguments)
+	}
+	return json.RawMessage(arguments), nil
+}
+
+// unsupportedPart returns the error of a part not supported by the provider.
+func unsupportedPart(provider string, part schema.ChatMessagePart) error {
+	return fmt.Errorf("%s part is not supported by %s", part.Type, provider)
+}
+
+// mediaOf returns the url, uri and mime type of a non-text part, ok is false if the part has no media.
+func mediaOf(part schema.ChatMessagePart) (url, uri, mimeType string, ok bool) {
+	switch {
+	case part.Type == schema.ChatMessagePartTypeImageURL && part.ImageURL != nil:
+		return part.ImageURL.URL, part.ImageURL.URI, part.ImageURL.MIMEType, true
+	case part.Type == schema.ChatMessagePartTypeAudioURL && part.AudioURL != nil:
+		return part.AudioURL.URL, part.AudioURL.URI, part.AudioURL.MIMEType, true
+	case part.Type == schema.ChatMessagePartTypeVideoURL && part.VideoURL != nil:
+		return part.VideoURL.URL, part.VideoURL.URI, part.VideoURL.MIMEType, true
+	case part.Type == schema.ChatMessagePartTypeFileURL && part.FileURL != nil:
+		return part.FileURL.URL, part.FileURL.URI, part.FileURL.MIMEType, true
+	}
+	return "", "", "", false
+}
+
+// mediaPart builds the part of the media by the top-level type of the mime type, parts of unknown types are files.
+func mediaPart(url, uri, mimeType string) schema.ChatMessagePart {
+	switch strings.SplitN(mimeType, "/", 2)[0] {
+	case "image":
+		return schema.ChatMessagePart{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: url, URI: uri, MIMEType: mimeType}}
+	case "audio":
+		return schema.ChatMessagePart{Type: schema.ChatMessagePartTypeAudioURL, AudioURL: &schema.ChatMessageAudioURL{URL: url, URI: uri, MIMEType: mimeType}}
+	case "video":
+		return schema.ChatMessagePart{Type: schema.ChatMessagePartTypeVideoURL, VideoURL: &schema.ChatMessageVideoURL{URL: url, URI: uri, MIMEType: mimeType}}
+	default:
+		return schema.ChatMessagePart{Type: schema.ChatMessagePartTypeFileURL, FileURL: &schema.ChatMessageFileURL{URL: url, URI: uri, MIMEType: mimeType}}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wireformat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/stretchr/testify/assert"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/mrh997/eino/schema"
+)
+
+func testRequest() *Request {
+	return &Request{
+		Model:     "m",
+		MaxTokens: 100,
+		Messages: []*schema.Message{
+			schema.SystemMessage("be helpful"),
+			{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+				{Type: schema.ChatMessagePartTypeText, Text: "where is it"},
+				{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: "data:image/png;base64,iVBO", MIMEType: "image/png"}},
+			}},
+			{Role: schema.Assistant, ReasoningContent: "check the weather", ToolCalls: []schema.ToolCall{
+				{ID: "c1", Type: "function", Function: schema.FunctionCall{Name: "weather", Arguments: `{"city":"bj"}`}},
+				{ID: "c2", Type: "function", Function: schema.FunctionCall{Name: "clock", Arguments: `{}`}},
+			}},
+			{Role: schema.Tool, Content: "sunny", ToolCallID: "c1", ToolName: "weather"},
+			{Role: schema.Tool, Content: `{"hour":9}`, ToolCallID: "c2", ToolName: "clock"},
+			schema.AssistantMessage("it's sunny in beijing", nil),
+		},
+		Tools: []*schema.ToolInfo{{
+			Name: "weather",
+			Desc: "get the weather",
+			ParamsOneOf: schema.NewParamsOneOfByJSONSchema(&jsonschema.Schema{
+				Type: "object",
+				Properties: orderedmap.New[string, *jsonschema.Schema](orderedmap.WithInitialData[string, *jsonschema.Schema](
+					orderedmap.Pair[string, *jsonschema.Schema]{Key: "city", Value: &jsonschema.Schema{Type: "string"}},
+				)),
+				Required: []string{"city"},
+			}),
+		}},
+	}
+}
+
+func testResponse() *Response {
+	return &Response{ID: "r1", Model: "m", Message: &schema.Message{
+		Role:             schema.Assistant,
+		Content:          "hello",
+		ReasoningContent: "greet",
+		ToolCalls:        []schema.ToolCall{{ID: "c1", Type: "function", Function: schema.FunctionCall{Name: "weather", Arguments: `{"city":"bj"}`}}},
+		ResponseMeta: &schema.ResponseMeta{FinishReason: "stop", Usage: &schema.TokenUsage{
+			PromptTokens:       10,
+			PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: 2},
+			CompletionTokens:   5,
+			TotalTokens:        15,
+		}},
+	}}
+}
+
+// assertRequest asserts the request equals the expected one, comparing the tool parameters by JSON.
+func assertRequest(t *testing.T, expected, actual *Request) {
+	assert.Equal(t, expected.Messages, actual.Messages)
+	assert.Equal(t, expected.MaxTokens, actual.MaxTokens)
+	assert.Equal(t, len(expected.Tools), len(actual.Tools))
+	for i := range expected.Tools {
+		assert.Equal(t, expected.Tools[i].Name, actual.Tools[i].Name)
+		assert.Equal(t, expected.Tools[i].Desc, actual.Tools[i].Desc)
+		e, err := expected.Tools[i].ParamsOneOf.ToJSONSchema()
+		assert.NoError(t, err)
+		a, err := actual.Tools[i].ParamsOneOf.ToJSONSchema()
+		assert.NoError(t, err)
+		eJSON, _ := json.Marshal(e)
+		aJSON, _ := json.Marshal(a)
+		assert.JSONEq(t, string(eJSON), string(aJSON))
+	}
+}
+
+func TestParseDataURL(t *testing.T) {
+	mimeType, data, ok := parseDataURL("data:image/png;base64,iVBO")
+	assert.True(t, ok)
+	assert.Equal(t, "image/png", mimeType)
+	assert.Equal(t, "iVBO", data)
+
+	_, _, ok = parseDataURL("https://example.com/a.png")
+	assert.False(t, ok)
+	_, _, ok = parseDataURL("data:text/plain,hello")
+	assert.False(t, ok)
+}